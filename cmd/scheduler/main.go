@@ -2,6 +2,7 @@ package main
 
 import (
 	"automation-engine/internal/azbus"
+	"automation-engine/internal/broker"
 	"automation-engine/internal/domain/model"
 	"automation-engine/internal/dto"
 	"automation-engine/internal/repository"
@@ -14,7 +15,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
 	"github.com/robfig/cron/v3"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -27,20 +27,6 @@ func main() {
 
 	ctx := context.Background()
 
-	connStr := utils.GetEnv("SERVICE_BUS_CONNECTION_STRING", "")
-
-	// New azure service bus client
-	client, err := azservicebus.NewClientFromConnectionString(connStr, nil)
-	if err != nil {
-		log.Fatalf("Failed to create Service Bus client: %v", err)
-	}
-	defer client.Close(ctx)
-
-	sender, err := azbus.NewSender(ctx, client, "automate_queue")
-	if err != nil {
-		log.Fatalf("")
-	}
-
 	dbHost := os.Getenv("MYSQL_HOST")
 	dbUser := os.Getenv("MYSQL_USER")
 	dbPass := os.Getenv("MYSQL_PASSWORD")
@@ -56,9 +42,11 @@ func main() {
 		Params: map[string]string{
 			"charset":              "utf8mb4",
 			"allowNativePasswords": "true",
+			"time_zone":            "'+00:00'",
 		},
 		ParseTime: true,
-		Loc:       time.Local,
+		// เก็บเวลาทั้งหมดเป็น UTC, timezone ของแต่ละ automation อยู่ที่ run_automations.time_zone
+		Loc: time.UTC,
 	}
 
 	dsn := cfg.FormatDSN()
@@ -68,14 +56,46 @@ func main() {
 		log.Fatalf("Failed to connect database: %v", err)
 	}
 
+	// Message broker (azure | mysql | memory)
+	connStr := utils.GetEnv("SERVICE_BUS_CONNECTION_STRING", "")
+	bus, err := broker.Open(utils.GetEnv("BROKER_BACKEND", broker.BackendAzure), connStr, db)
+	if err != nil {
+		log.Fatalf("Failed to create message broker: %v", err)
+	}
+	defer bus.Close(ctx)
+
+	sender, err := azbus.NewSender(ctx, bus, "automate_queue")
+	if err != nil {
+		log.Fatalf("Failed to create sender: %v", err)
+	}
+
 	txManager := repository.NewTransactionManager(db)
+	conditionRepo := repository.NewConditionRepository(db)
+	operatorRepo := repository.NewOperatorRepository(db)
+	unitRepo := repository.NewUnitRepository(db)
+	actionRepo := repository.NewActionRepository(db)
+	conditionOperatorRepo := repository.NewConditionOperatorRepository(db)
+	conditionUnitRepo := repository.NewConditionUnitRepository(db)
+	conditionActionRepo := repository.NewConditionActionRepository(db)
 	automationRepo := repository.NewAutomationRepository(db)
 	automationActionRepo := repository.NewAutomationActionRepository(db)
 	automationConditionGroupRepo := repository.NewAutomationConditionGroupRepository(db)
 	automationConditionRepo := repository.NewAutomationConditionRepository(db)
 	automationTargetRepo := repository.NewAutomationTargetRepository(db)
 	automationExecutionRepo := repository.NewAutomationExecutionRepository(db)
+	automationActionExecutionRepo := repository.NewAutomationActionExecutionRepository(db)
+	blackoutDateRepo := repository.NewBlackoutDateRepository(db)
 
+	policyService := service.NewPolicyService(
+		txManager,
+		conditionRepo,
+		operatorRepo,
+		unitRepo,
+		actionRepo,
+		conditionOperatorRepo,
+		conditionUnitRepo,
+		conditionActionRepo,
+	)
 	runService := service.NewRunService(
 		txManager,
 		automationRepo,
@@ -84,17 +104,37 @@ func main() {
 		automationConditionRepo,
 		automationTargetRepo,
 		automationExecutionRepo,
+		blackoutDateRepo,
+		policyService,
 	)
 	logService := service.NewLogService(
 		txManager,
 		automationExecutionRepo,
+		automationActionExecutionRepo,
 	)
 
+	// lease ของงานที่ถูก LOCKED, ถ้า scheduler ตายก่อนปล่อย lease งานจะถูกดึงกลับโดย reaper
+	lease := time.Duration(utils.GetEnvAsInt("SCHEDULER_LEASE_SECONDS", 300)) * time.Second
+	schedulerID := schedulerInstanceID()
+
+	// รอบที่พลาดเกินช่วงนี้จะไม่ถูกตามรัน (0 = ไม่จำกัด)
+	maxCatchUp := time.Duration(utils.GetEnvAsInt("SCHEDULER_MAX_CATCHUP_MINUTES", 1440)) * time.Minute
+
 	c := cron.New()
 
 	// ตั้ง Cron ทำงานทุก 1 นาที
 	c.AddFunc("* * * * *", func() {
-		go runWorker(ctx, time.Now(), runService, logService, sender)
+		go runWorker(ctx, time.Now(), runService, logService, sender, schedulerID, lease, maxCatchUp)
+	})
+
+	// ดึงงานที่ lease หมดอายุ (ค้าง LOCKED) กลับมาเป็น PENDING
+	c.AddFunc("* * * * *", func() {
+		go reclaimExpiredLeases(ctx, runService)
+	})
+
+	// ปิด step ของ action แบบ async ที่ไม่มี callback กลับมาภายในเวลาที่กำหนด
+	c.AddFunc("* * * * *", func() {
+		go expireAsyncSteps(ctx, logService)
 	})
 
 	// ลบ Log เก่า ทุกวันตอน 00:01 AM
@@ -109,7 +149,7 @@ func main() {
 	select {}
 }
 
-func runWorker(ctx context.Context, runTime time.Time, runService service.RunService, logService service.LogService, sender *azbus.Sender) {
+func runWorker(ctx context.Context, runTime time.Time, runService service.RunService, logService service.LogService, sender *azbus.Sender, schedulerID string, lease, maxCatchUp time.Duration) {
 	ctx, cancel := context.WithTimeout(ctx, 1*time.Hour)
 	defer cancel()
 
@@ -122,7 +162,7 @@ func runWorker(ctx context.Context, runTime time.Time, runService service.RunSer
 		}
 
 		// 1. Fetch & Lock
-		tasks, err := runService.FetchAndLockTasks(ctx, runTime, 100)
+		tasks, err := runService.FetchAndLockTasks(ctx, runTime, 100, schedulerID, lease)
 		if err != nil {
 			log.Printf("[Worker-%s] ❌ Error fetching tasks: %v", workerID, err)
 			time.Sleep(5 * time.Second)
@@ -136,68 +176,117 @@ func runWorker(ctx context.Context, runTime time.Time, runService service.RunSer
 
 		log.Printf("[Worker-%s] 📥 Picked up %d tasks", workerID, len(tasks))
 
+		// โหลดวันหยุด/blackout ของทุก calendar ที่งานในรอบนี้ใช้
+		blackouts, err := runService.LoadBlackoutCalendar(ctx, tasks)
+		if err != nil {
+			log.Printf("[Worker-%s] ❌ Error loading blackout calendars: %v", workerID, err)
+			return
+		}
+
 		var successTasks []*model.RunAutomation
 
 		for _, task := range tasks {
-			// 2. คำนวณเวลาถัดไป
-			nextRun, err := calculateNextRun(task)
+			// 2. ตัดสินว่าจะรันรอบไหนบ้าง (รวมรอบที่พลาดตาม misfire policy) และคำนวณเวลาถัดไป
+			runs, nextRun, err := service.PlanRuns(task, runTime, maxCatchUp, blackouts)
 			if err != nil {
 				log.Printf("[Worker-%s] ⚠️ Skip Automation [%s]: %v", workerID, task.AutomationID, err)
 				continue
 			}
 
-			// 3. เตรียม Message (DTO)
-			msgPayload := dto.MessageServiceBus{
-				LogID:        logService.GenerateLogID(),
-				AutomationID: task.AutomationID,
-				TriggeredAt:  time.Now(),
+			if len(runs) == 0 {
+				log.Printf("[Worker-%s] ⏭ Run skipped: AutomationID=%s | NextRunTime=%s | Policy=%s | Blackout=%s", workerID, task.AutomationID, task.NextRunTime.Format(time.RFC3339), task.MisfirePolicy, task.BlackoutRule)
+			}
+			if nextRun.IsZero() {
+				log.Printf("[Worker-%s] 🏁 Schedule ended: AutomationID=%s | RunCount=%d", workerID, task.AutomationID, task.RunCount+int32(len(runs)))
 			}
 
-			body, _ := json.Marshal(msgPayload)
-
-			// 4. ส่งเข้า Service Bus (ถ้าพังจะไม่ update DB เพื่อให้รอบหน้ามาทำใหม่)
-			err = sender.SendMessage(ctx, task.InstanceServerChannelID, body)
-			if err != nil {
-				log.Printf("[Worker-%s] ‼️ Failed to dispatch [%s] to bus: %v", workerID, task.AutomationID, err)
-				continue
+			sent := 0
+			for _, run := range runs {
+				// 3. เตรียม Message (DTO)
+				msgPayload := dto.MessageServiceBus{
+					LogID:        logService.GenerateLogID(),
+					AutomationID: task.AutomationID,
+					TriggeredAt:  time.Now(),
+					ScheduledFor: run.ScheduledFor,
+					IsCatchUp:    run.IsCatchUp,
+					TriggerType:  dto.TriggerSchedule,
+				}
+
+				body, _ := json.Marshal(msgPayload)
+
+				// 4. ส่งเข้า Service Bus (ถ้าพังจะไม่ update DB, lease หมดอายุแล้ว reaper จะคืนงานให้รอบถัดไป)
+				err = sender.SendMessage(ctx, task.InstanceServerChannelID, body)
+				if err != nil {
+					log.Printf("[Worker-%s] ‼️ Failed to dispatch [%s] to bus: %v", workerID, task.AutomationID, err)
+					break
+				}
+				sent++
+
+				log.Printf("[Worker-%s] 📤 Dispatched: AutomationID=%s | LogID=%s | CatchUp=%t", workerID, task.AutomationID, msgPayload.LogID, run.IsCatchUp)
+			}
+			if sent < len(runs) {
+				if sent == 0 {
+					continue
+				}
+				// ส่งไปแล้วบางรอบ (fire_all_missed): เลื่อน NextRunTime ไปที่รอบแรกที่ยังไม่ได้ส่ง
+				// ไม่งั้นรอบที่ส่งแล้วจะถูกส่งซ้ำเมื่อ lease ถูกคืน
+				nextRun = runs[sent].ScheduledFor
 			}
 
 			// เตรียมข้อมูลเพื่อ Update DB
 			task.Status = "PENDING"
 			task.NextRunTime = nextRun
+			task.RunCount += int32(sent)
 			task.LastUpd = time.Now()
 			successTasks = append(successTasks, task)
-
-			log.Printf("[Worker-%s] 📤 Dispatched: AutomationID=%s | LogID=%s", workerID, task.AutomationID, msgPayload.LogID)
 		}
 
 		// 5. Bulk Update เฉพาะรายการที่ส่ง Bus สำเร็จ
 		if len(successTasks) > 0 {
-			if err := runService.BulkUpdateNextRun(ctx, successTasks); err != nil {
+			lost, err := runService.BulkUpdateNextRun(ctx, successTasks, schedulerID)
+			if err != nil {
 				log.Printf("[Worker-%s] ❌ Bulk Update Error: %v", workerID, err)
 			} else {
-				log.Printf("[Worker-%s] 💾 Successfully updated %d tasks in database", workerID, len(successTasks))
+				// lease หมดอายุระหว่างส่ง: reaper หรือ scheduler อื่นรับงานไปแล้ว ไม่เขียนทับ
+				for _, id := range lost {
+					log.Printf("[Worker-%s] ⚠️ Lease lost, next run not written: AutomationID=%s", workerID, id)
+				}
+				log.Printf("[Worker-%s] 💾 Successfully updated %d tasks in database", workerID, len(successTasks)-len(lost))
 			}
 		}
 	}
 }
 
-func calculateNextRun(task *model.RunAutomation) (time.Time, error) {
-	now := time.Now()
-
-	switch task.Frequency {
-	case "once":
-		return time.Time{}, nil
-	case "daily":
-		return utils.CalculateDailyNextRun(now, task.StartDate, time.Local)
-	case "weekly":
-		return utils.CalculateWeeklyNextRun(now, task.StartDate, task.DayOfWeek, time.Local)
-	case "monthly":
-		return utils.CalculateMonthlyNextRun(now, task.StartDate, int(task.DayOfMonth), time.Local)
-	case "yearly":
-		return utils.CalculateYearlyNextRun(now, task.StartDate, int(task.DayOfMonth), int(task.MonthOfYear), time.Local)
-	default:
-		return time.Time{}, fmt.Errorf("unsupported frequency: %s", task.Frequency)
+func reclaimExpiredLeases(ctx context.Context, runService service.RunService) {
+	recovered, err := runService.ReclaimExpiredLeases(ctx, time.Now())
+	if err != nil {
+		log.Printf("❌ Failed to reclaim expired leases: %v", err)
+		return
+	}
+
+	if recovered > 0 {
+		log.Printf("♻️ Recovered %d automations stuck in LOCKED", recovered)
+	}
+}
+
+// schedulerInstanceID identifies this process in run_automations.locked_by
+func schedulerInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "scheduler"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+func expireAsyncSteps(ctx context.Context, logService service.LogService) {
+	expired, err := logService.ExpireRunningSteps(ctx, time.Now())
+	if err != nil {
+		log.Printf("❌ Failed to expire async steps: %v", err)
+		return
+	}
+
+	if expired > 0 {
+		log.Printf("⏱ Marked %d async steps as FAILED (callback timeout)", expired)
 	}
 }
 
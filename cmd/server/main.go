@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"time"
 
 	"automation-engine/internal/api"
+	"automation-engine/internal/azbus"
+	"automation-engine/internal/broker"
+	"automation-engine/internal/callback"
+	"automation-engine/internal/directory"
 	"automation-engine/internal/middleware"
 	"automation-engine/internal/repository"
 	"automation-engine/internal/service"
+	"automation-engine/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -50,9 +56,11 @@ func main() {
 		Params: map[string]string{
 			"charset":              "utf8mb4",
 			"allowNativePasswords": "true",
+			"time_zone":            "'+00:00'",
 		},
 		ParseTime: true,
-		Loc:       time.Local,
+		// เก็บเวลาทั้งหมดเป็น UTC, timezone ของแต่ละ automation อยู่ที่ run_automations.time_zone
+		Loc: time.UTC,
 	}
 	dsn := cfg.FormatDSN()
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
@@ -74,6 +82,8 @@ func main() {
 	automationConditionRepo := repository.NewAutomationConditionRepository(db)
 	automationTargetRepo := repository.NewAutomationTargetRepository(db)
 	automationExecutionRepo := repository.NewAutomationExecutionRepository(db)
+	automationActionExecutionRepo := repository.NewAutomationActionExecutionRepository(db)
+	blackoutDateRepo := repository.NewBlackoutDateRepository(db)
 
 	// 2. ประกอบร่างจิ๊กซอว์ (Dependency Injection)
 	// DefinitionService จะสร้าง ActionRepository ภายในตัวมันเองตามที่คุณเขียนไว้
@@ -83,6 +93,7 @@ func main() {
 		conditionRepo,
 		operatorRepo,
 		unitRepo,
+		blackoutDateRepo,
 	)
 	policyService := service.NewPolicyService(
 		txManager,
@@ -102,18 +113,43 @@ func main() {
 		automationConditionRepo,
 		automationTargetRepo,
 		automationExecutionRepo,
+		blackoutDateRepo,
+		policyService,
 	)
 	logService := service.NewLogService(
 		txManager,
 		automationExecutionRepo,
+		automationActionExecutionRepo,
 	)
 
+	// Message broker (azure | mysql | memory) สำหรับสั่งรัน automation ทันที
+	ctx := context.Background()
+	bus, err := broker.Open(utils.GetEnv("BROKER_BACKEND", broker.BackendAzure), utils.GetEnv("SERVICE_BUS_CONNECTION_STRING", ""), db)
+	if err != nil {
+		log.Fatalf("Failed to create message broker: %v", err)
+	}
+	defer bus.Close(ctx)
+
+	sender, err := azbus.NewSender(ctx, bus, "automate_queue")
+	if err != nil {
+		log.Fatalf("Failed to create sender: %v", err)
+	}
+	triggerService := service.NewTriggerService(runService, logService, sender)
+
+	// Employee directory สำหรับขยาย targets เป็นรายชื่อพนักงาน
+	directoryProvider, err := directory.NewFromConfig(os.Getenv("DIRECTORY_PROVIDER"), db)
+	if err != nil {
+		log.Fatalf("Failed to create directory provider: %v", err)
+	}
+	targetService := service.NewTargetService(runService, directoryProvider)
+
 	// สร้าง Handler โดยส่ง Service เข้าไป
 	authHandler := api.NewAuthHandler()
-	definitionHandler := api.NewDefinitionHandler(definitionService)
+	definitionHandler := api.NewDefinitionHandler(definitionService, runService, targetService)
 	policyHandler := api.NewPolicyHandler(policyService)
-	runHandler := api.NewRunHandler(runService)
-	logHandler := api.NewLogHandler(logService)
+	runHandler := api.NewRunHandler(runService, triggerService, targetService)
+	logHandler := api.NewLogHandler(logService, triggerService)
+	callbackHandler := api.NewCallbackHandler(logService, callback.NewSigner(os.Getenv("CALLBACK_SECRET"), os.Getenv("CALLBACK_BASE_URL")))
 
 	// 3. เริ่มต้นระบบ HTTP Server ด้วย Gin
 	r := gin.Default()
@@ -129,6 +165,9 @@ func main() {
 	apiV1 := r.Group("/api/v1")
 	{
 		apiV1.POST("/login", authHandler.Login) // เส้นนี้ไม่ต้องใช้ Token
+
+		// action แบบ async เรียกกลับมา (ใช้ X-Callback-Token แทน JWT)
+		apiV1.POST("/callbacks/action-executions/:step_id", callbackHandler.CompleteActionExecution)
 	}
 
 	// Protected Routes (ต้องมี JWT)
@@ -140,6 +179,9 @@ func main() {
 		{
 			definitionGroup.GET("/actions", definitionHandler.GetActionByID)
 			definitionGroup.POST("/actions", definitionHandler.CreateAction)
+			definitionGroup.POST("/actions/render-test", definitionHandler.RenderTemplate)
+			definitionGroup.GET("/blackout-dates", definitionHandler.ListBlackoutDates)
+			definitionGroup.POST("/blackout-dates", definitionHandler.CreateBlackoutDate)
 		}
 
 		policyGroup := protected.Group("/policy")
@@ -152,12 +194,28 @@ func main() {
 
 		runGroup := protected.Group("/run")
 		{
-			runGroup.POST("/automation", runHandler.CreateAutomation)
+			runGroup.POST("/automations", runHandler.CreateAutomation)
+			runGroup.GET("/automations", runHandler.ListAutomations)
+			runGroup.GET("/automations/:id", runHandler.GetAutomation)
+			runGroup.PUT("/automations/:id", runHandler.UpdateAutomation)
+			runGroup.DELETE("/automations/:id", runHandler.DeleteAutomation)
+			runGroup.POST("/automations/:id/activate", runHandler.ActivateAutomation)
+			runGroup.POST("/automations/:id/pause", runHandler.PauseAutomation)
+			runGroup.POST("/automations/:id/resume", runHandler.ResumeAutomation)
+			runGroup.POST("/automations/:id/archive", runHandler.ArchiveAutomation)
+			runGroup.POST("/automations/:id/trigger", runHandler.TriggerAutomation)
+			runGroup.GET("/automations/:id/schedule-preview", runHandler.GetSchedulePreview)
+			runGroup.GET("/automations/:id/targets/preview", runHandler.GetTargetPreview)
+			runGroup.POST("/schedule/preview", runHandler.PreviewSchedule)
 		}
 
 		logGroup := protected.Group("/logs")
 		{
-			logGroup.GET("/automation-execution", logHandler.CreateAutomationExecution)
+			logGroup.GET("/automation-executions", logHandler.ListAutomationExecutions)
+			logGroup.GET("/automation-executions/:log_id", logHandler.GetAutomationExecution)
+			logGroup.GET("/dead-letters", logHandler.ListDeadLetters)
+			logGroup.POST("/dead-letters/replay", logHandler.ReplayDeadLetters)
+			logGroup.POST("/dead-letters/:log_id/replay", logHandler.ReplayDeadLetter)
 		}
 	}
 
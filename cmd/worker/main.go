@@ -2,6 +2,10 @@ package main
 
 import (
 	"automation-engine/internal/azbus"
+	"automation-engine/internal/broker"
+	"automation-engine/internal/callback"
+	"automation-engine/internal/directory"
+	"automation-engine/internal/provider"
 	"automation-engine/internal/repository"
 	"automation-engine/internal/service"
 	"automation-engine/internal/utils"
@@ -12,7 +16,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
 	"github.com/joho/godotenv"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -40,9 +43,11 @@ func main() {
 		Params: map[string]string{
 			"charset":              "utf8mb4",
 			"allowNativePasswords": "true",
+			"time_zone":            "'+00:00'",
 		},
 		ParseTime: true,
-		Loc:       time.Local,
+		// เก็บเวลาทั้งหมดเป็น UTC, timezone ของแต่ละ automation อยู่ที่ run_automations.time_zone
+		Loc: time.UTC,
 	}
 	dsn := cfg.FormatDSN()
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
@@ -55,12 +60,17 @@ func main() {
 	operatorRepo := repository.NewOperatorRepository(db)
 	unitRepo := repository.NewUnitRepository(db)
 	actionRepo := repository.NewActionRepository(db)
+	conditionOperatorRepo := repository.NewConditionOperatorRepository(db)
+	conditionUnitRepo := repository.NewConditionUnitRepository(db)
+	conditionActionRepo := repository.NewConditionActionRepository(db)
 	automationRepo := repository.NewAutomationRepository(db)
 	automationActionRepo := repository.NewAutomationActionRepository(db)
 	automationConditionGroupRepo := repository.NewAutomationConditionGroupRepository(db)
 	automationConditionRepo := repository.NewAutomationConditionRepository(db)
 	automationTargetRepo := repository.NewAutomationTargetRepository(db)
 	automationExecutionRepo := repository.NewAutomationExecutionRepository(db)
+	automationActionExecutionRepo := repository.NewAutomationActionExecutionRepository(db)
+	blackoutDateRepo := repository.NewBlackoutDateRepository(db)
 
 	definitionService := service.NewDefinitionService(
 		txManager,
@@ -68,6 +78,17 @@ func main() {
 		conditionRepo,
 		operatorRepo,
 		unitRepo,
+		blackoutDateRepo,
+	)
+	policyService := service.NewPolicyService(
+		txManager,
+		conditionRepo,
+		operatorRepo,
+		unitRepo,
+		actionRepo,
+		conditionOperatorRepo,
+		conditionUnitRepo,
+		conditionActionRepo,
 	)
 	runService := service.NewRunService(
 		txManager,
@@ -77,12 +98,28 @@ func main() {
 		automationConditionRepo,
 		automationTargetRepo,
 		automationExecutionRepo,
+		blackoutDateRepo,
+		policyService,
 	)
 	logService := service.NewLogService(
 		txManager,
 		automationExecutionRepo,
+		automationActionExecutionRepo,
 	)
 
+	// Condition providers (keyed by def_conditions.condition_type)
+	conditionRegistry := provider.NewRegistry(definitionService)
+	if err := conditionRegistry.RegisterFromConfig(os.Getenv("CONDITION_PROVIDERS"), db); err != nil {
+		log.Fatalf("Failed to register condition providers: %v", err)
+	}
+
+	// Employee directory สำหรับขยาย targets เป็นรายชื่อพนักงาน
+	directoryProvider, err := directory.NewFromConfig(os.Getenv("DIRECTORY_PROVIDER"), db)
+	if err != nil {
+		log.Fatalf("Failed to create directory provider: %v", err)
+	}
+	targetService := service.NewTargetService(runService, directoryProvider)
+
 	// Create context with cancel
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -94,20 +131,27 @@ func main() {
 	utils.LoadEnvVariables()
 	connStr := utils.GetEnv("SERVICE_BUS_CONNECTION_STRING", "")
 
-	// New azure service bus client
-	client, err := azservicebus.NewClientFromConnectionString(connStr, nil)
+	// Message broker (azure | mysql | memory)
+	bus, err := broker.Open(utils.GetEnv("BROKER_BACKEND", broker.BackendAzure), connStr, db)
 	if err != nil {
-		log.Fatalf("Failed to create Service Bus client: %v", err)
+		log.Fatalf("Failed to create message broker: %v", err)
 	}
-	defer client.Close(ctx)
+	defer bus.Close(ctx)
 
 	// Create session receiver
 	receiver1Opts := azbus.SessionReceiverOptions{
 		SessionPool: utils.GetEnvAsInt("SESSION_POOL", 20),
 		BatchSize:   utils.GetEnvAsInt("BATCH_SIZE", 5),
 		ProcessPool: utils.GetEnvAsInt("PROCESS_POOL", 1),
+		// เวลารอ callback ของ action แบบ async (นาที)
+		CallbackTimeout: utils.GetEnvAsInt("ASYNC_CALLBACK_TIMEOUT", 60),
+		// เวลาที่ worker จอง execution ไว้ก่อนให้ message ที่ส่งซ้ำทำต่อได้ (นาที)
+		ClaimTimeout: utils.GetEnvAsInt("EXECUTION_CLAIM_TIMEOUT", 10),
+		// จำนวนผู้รับที่ action แบบ fan-out เรียกพร้อมกัน
+		FanOutPool: utils.GetEnvAsInt("FAN_OUT_POOL", 5),
 	}
-	receiver1 := azbus.NewSessionReceiver(ctx, &wg, client, "automate_queue", runService, definitionService, logService, &receiver1Opts)
+	callbackSigner := callback.NewSigner(os.Getenv("CALLBACK_SECRET"), os.Getenv("CALLBACK_BASE_URL"))
+	receiver1 := azbus.NewSessionReceiver(ctx, &wg, bus, "automate_queue", runService, definitionService, logService, targetService, conditionRegistry, callbackSigner, &receiver1Opts)
 
 	// Run session receiver
 	go receiver1.RunDispatcher()
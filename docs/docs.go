@@ -1,4 +1,5 @@
-// Package docs Code generated by swaggo/swag. DO NOT EDIT
+// Code generated by swaggo/swag. DO NOT EDIT.
+
 package docs
 
 import "github.com/swaggo/swag"
@@ -15,8 +16,94 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/callbacks/action-executions/{step_id}": {
+            "post": {
+                "description": "ปลายทางของ action แบบ async เรียกกลับมาเพื่อปิด step (ใช้ X-Callback-Token ที่ได้รับตอนถูกเรียก แทน JWT)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "callbacks"
+                ],
+                "summary": "Complete async action",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Step ID (จาก X-Callback-URL)",
+                        "name": "step_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Callback token",
+                        "name": "X-Callback-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Action result",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ActionCallbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/definition/actions": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "ดึงข้อมูลนิยามของ Action จากตาราง def_actions",
                 "consumes": [
                     "application/json"
@@ -64,37 +151,2611 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.ActionResponse": {
-            "type": "object",
-            "properties": {
-                "action_code": {
-                    "type": "string"
-                },
-                "action_id": {
-                    "type": "string"
-                },
-                "action_name": {
-                    "type": "string"
-                },
-                "action_type": {
-                    "type": "string"
-                },
-                "invoke_method": {
-                    "type": "string"
-                },
-                "invoke_type": {
-                    "type": "string"
-                },
-                "invoke_url": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
+        },
+        "/definition/actions/render-test": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ทดสอบ render payload template กับข้อมูล automation (หรือข้อมูลตัวอย่าง) ก่อนบันทึก โดยไม่เรียก action จริง",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "definition"
+                ],
+                "summary": "Render payload template",
+                "parameters": [
+                    {
+                        "description": "Template and sample data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RenderTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RenderTemplateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/definition/blackout-dates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ดึงช่วงวันหยุด/blackout ทั้งหมด หรือเฉพาะของ calendar ที่ระบุ",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "definition"
+                ],
+                "summary": "List blackout dates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Calendar code (e.g. TH_HOLIDAY)",
+                        "name": "calendar_code",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DefBlackoutDate"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "เพิ่มช่วงวันหยุด/blackout ลงใน calendar (scheduler จะ skip หรือ shift รอบที่ตรงกับช่วงนี้ตาม blackout_rule ของ automation)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "definition"
+                ],
+                "summary": "Create blackout date range",
+                "parameters": [
+                    {
+                        "description": "Blackout Date Payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateBlackoutDateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.DefBlackoutDate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "ตรวจสอบ Username/Password และส่งกลับ JWT Token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User Login",
+                "parameters": [
+                    {
+                        "description": "Login Credentials",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
                 }
             }
+        },
+        "/logs/automation-executions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ค้นหาประวัติการรัน Automation เรียงจากล่าสุด (cursor pagination ด้วย log_id)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "List automation executions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "automation_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "SUCCESS, FAILED, PARTIAL, SKIPPED, RUNNING, RETRYING",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 เช่น 2024-01-01T00:00:00+07:00",
+                        "name": "triggered_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339",
+                        "name": "triggered_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor จากหน้าก่อนหน้า",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "จำนวนต่อหน้า (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.ExecutionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/automation-executions/{log_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "รายละเอียดการรัน พร้อม config_snapshot ที่ใช้ตอนรัน, error message และผลของแต่ละ action (steps)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Get automation execution",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Log ID",
+                        "name": "log_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.ExecutionDetailResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/dead-letters": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "execution ที่ message ถูกย้ายเข้า dead-letter (retry ครบ, action ล้มเหลวแบบ retry ไม่ได้ หรือส่งซ้ำเกินจำนวน) พร้อมเหตุผลและ message เดิม",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "List dead-lettered executions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "automation_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RetryExhausted, ActionFailed, MaxDeliveryCountExceeded",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339",
+                        "name": "dead_lettered_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339",
+                        "name": "dead_lettered_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "รวมรายการที่ replay ไปแล้ว (default false)",
+                        "name": "include_replayed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor จากหน้าก่อนหน้า",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "จำนวนต่อหน้า (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.DeadLetterListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/dead-letters/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "replay execution ที่เข้า dead-letter และยังไม่เคย replay ตามเงื่อนไขที่ระบุ (สูงสุด 100 รายการต่อครั้ง)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Replay dead-lettered executions",
+                "parameters": [
+                    {
+                        "description": "Filter",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ReplayBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReplayBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/dead-letters/{log_id}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ส่ง message เดิมกลับเข้า automate_queue ด้วย log_id ใหม่ (replay_of_log_id ชี้ไปที่ log เดิม)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Replay dead-lettered execution",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Log ID ของ execution ที่เข้า dead-letter",
+                        "name": "log_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReplayResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/run/automations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "List automations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Instance server ID",
+                        "name": "instance_server_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Instance server channel ID",
+                        "name": "instance_server_channel_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Frequency",
+                        "name": "frequency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scheduler status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Y or N",
+                        "name": "is_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "DRAFT, ACTIVE, PAUSED, ARCHIVED",
+                        "name": "lifecycle_state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.RunAutomation"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "สร้าง Automation พร้อม condition groups, conditions, actions และ targets ใน transaction เดียว",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Create automation",
+                "parameters": [
+                    {
+                        "description": "Automation document",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AutomationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AutomationSnapshot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/run/automations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Get automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AutomationSnapshot"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "แทนที่ Automation และรายการลูกทั้งหมดด้วยเอกสารใหม่",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Replace automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Automation document",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AutomationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AutomationSnapshot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Delete automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/run/automations/{id}/activate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "DRAFT → ACTIVE และคำนวณ next_run_time จากเวลาปัจจุบัน",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Activate draft automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RunAutomation"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/run/automations/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "DRAFT/PAUSED → ARCHIVED เก็บไว้ดูย้อนหลัง แก้ไขหรือสั่งรันไม่ได้อีก (ต้อง pause ก่อนถ้ายัง ACTIVE)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Archive automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RunAutomation"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/run/automations/{id}/pause": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ACTIVE → PAUSED, scheduler จะไม่หยิบงานนี้จนกว่าจะ resume",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Pause automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RunAutomation"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/run/automations/{id}/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "PAUSED → ACTIVE และคำนวณ next_run_time ใหม่จากเวลาปัจจุบัน (ไม่ตามรอบที่พลาดไประหว่าง pause)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Resume automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RunAutomation"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/run/automations/{id}/schedule-preview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "แสดงเวลาที่ automation จะรัน N รอบถัดไป (ตาม time zone ของ automation, รวม end date / max runs / blackout) โดยไม่แก้ไขข้อมูล",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Preview automation schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "จำนวนรอบ (default 10, max 100)",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SchedulePreviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/run/automations/{id}/targets/preview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ขยาย targets ของ automation (INCLUDE ลบ EXCLUDE, ตัดคนซ้ำ) เป็นรายชื่อพนักงานจาก directory ณ ตอนนี้",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Preview automation recipients",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.TargetPreviewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/run/automations/{id}/trigger": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ส่ง message ให้ worker รัน automation ทันทีโดยไม่กระทบ schedule (next_run_time) ติดตามผลได้ที่ /logs/automation-executions/{log_id}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Run automation now",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Trigger options",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.TriggerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/api.TriggerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/run/schedule/preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "คำนวณเวลารัน N รอบถัดไปจาก schedule ที่ยังไม่ได้บันทึก ใช้ตรวจสอบก่อนสร้าง/แก้ไข automation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "run"
+                ],
+                "summary": "Preview unsaved schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "จำนวนรอบ (default 10, max 100)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Schedule",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SchedulePreviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.ActionCallbackRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "error_message": {
+                    "type": "string"
+                },
+                "response": {
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "SUCCESS",
+                        "FAILED"
+                    ]
+                }
+            }
+        },
+        "api.ActionResponse": {
+            "type": "object",
+            "properties": {
+                "action_code": {
+                    "type": "string"
+                },
+                "action_id": {
+                    "type": "string"
+                },
+                "action_name": {
+                    "type": "string"
+                },
+                "action_type": {
+                    "type": "string"
+                },
+                "invoke_headers": {
+                    "description": "InvokeHeaders ถูกเก็บเป็น JSON object ในคอลัมน์ invoke_headers, ค่าของ header ถูกปิดเป็น *** (มักเป็น token)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "invoke_method": {
+                    "type": "string"
+                },
+                "invoke_timeout": {
+                    "description": "InvokeTimeout เป็นวินาที, 0 = ใช้ค่า default ของ httpclient (60s)",
+                    "type": "integer"
+                },
+                "invoke_type": {
+                    "type": "string"
+                },
+                "invoke_url": {
+                    "type": "string"
+                },
+                "payload_template": {
+                    "description": "PayloadTemplate ดู CreateActionRequest",
+                    "type": "string"
+                },
+                "retry_backoff_seconds": {
+                    "type": "integer"
+                },
+                "retry_max_attempts": {
+                    "description": "Retry policy ดู CreateActionRequest",
+                    "type": "integer"
+                },
+                "retry_max_backoff_seconds": {
+                    "type": "integer"
+                },
+                "retry_status_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.AutomationActionRequest": {
+            "type": "object",
+            "required": [
+                "action_id"
+            ],
+            "properties": {
+                "action_id": {
+                    "type": "string"
+                },
+                "config_json": {
+                    "description": "ConfigJSON เป็น JSON object ที่ถูก merge เข้ากับ payload ของ action\n(log_id, automation_action_id, automation, targets, recipients, recipient, triggered_at เป็น key สงวนของระบบ)",
+                    "type": "string"
+                },
+                "execution_mode": {
+                    "description": "ExecutionMode: SINGLE = เรียกครั้งเดียวต่อ execution, FAN_OUT = เรียกแยกรายผู้รับ (payload มี key recipient) default SINGLE",
+                    "type": "string",
+                    "enum": [
+                        "SINGLE",
+                        "FAN_OUT"
+                    ]
+                },
+                "fan_out_concurrency": {
+                    "description": "FanOutConcurrency จำนวนผู้รับที่เรียกพร้อมกัน (0 = ค่า FAN_OUT_POOL ของ worker)",
+                    "type": "integer",
+                    "maximum": 50,
+                    "minimum": 0
+                },
+                "sort_order": {
+                    "description": "SortOrder ลำดับการเรียก action (น้อยไปมาก)",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.AutomationRequest": {
+            "type": "object",
+            "required": [
+                "actions",
+                "automation_name",
+                "frequency",
+                "instance_server_channel_id",
+                "instance_server_id",
+                "start_date"
+            ],
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/api.AutomationActionRequest"
+                    }
+                },
+                "automation_name": {
+                    "type": "string"
+                },
+                "blackout_calendar": {
+                    "description": "calendar_code ของ def_blackout_dates (ว่าง = ไม่เช็ควันหยุด)",
+                    "type": "string"
+                },
+                "blackout_rule": {
+                    "description": "รอบที่ตรงวันหยุด: skip = ข้าม, shift = เลื่อนไปวันทำการถัดไป (default skip)",
+                    "type": "string",
+                    "enum": [
+                        "skip",
+                        "shift"
+                    ]
+                },
+                "condition_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ConditionGroupRequest"
+                    }
+                },
+                "cron_expression": {
+                    "description": "frequency = cron เช่น \"30 8 * * 1-5\", \"0 0 1,15 * *\", \"0 */2 * * *\"",
+                    "type": "string"
+                },
+                "day_of_month": {
+                    "type": "integer",
+                    "maximum": 31,
+                    "minimum": 1
+                },
+                "day_of_week": {
+                    "type": "string",
+                    "enum": [
+                        "sun",
+                        "mon",
+                        "tue",
+                        "wed",
+                        "thu",
+                        "fri",
+                        "sat"
+                    ]
+                },
+                "end_date": {
+                    "description": "ไม่รันรอบที่เลยวันนี้ไปแล้ว (ว่าง = ไม่มีกำหนด)",
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "once",
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "yearly",
+                        "cron"
+                    ]
+                },
+                "instance_server_channel_id": {
+                    "type": "string"
+                },
+                "instance_server_id": {
+                    "type": "string"
+                },
+                "lifecycle_state": {
+                    "description": "ตอนสร้างเท่านั้น (default ACTIVE), PUT ไม่เปลี่ยน state",
+                    "type": "string",
+                    "enum": [
+                        "DRAFT",
+                        "ACTIVE"
+                    ]
+                },
+                "max_runs": {
+                    "description": "จำนวนครั้งสูงสุดที่จะรัน (0 = ไม่จำกัด)",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "misfire_policy": {
+                    "description": "รอบที่พลาดไป (เช่น scheduler ล่ม), default fire_once_now",
+                    "type": "string",
+                    "enum": [
+                        "fire_once_now",
+                        "fire_all_missed",
+                        "skip_to_next"
+                    ]
+                },
+                "month_of_year": {
+                    "type": "integer",
+                    "maximum": 12,
+                    "minimum": 1
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "targets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.AutomationTargetRequest"
+                    }
+                },
+                "time_zone": {
+                    "description": "IANA เช่น Asia/Bangkok (ว่าง = timezone ของ server)",
+                    "type": "string"
+                }
+            }
+        },
+        "api.AutomationTargetRequest": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "department_id": {
+                    "type": "string"
+                },
+                "division_id": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "employee_type_code": {
+                    "type": "string"
+                },
+                "position_id": {
+                    "type": "string"
+                },
+                "section_id": {
+                    "type": "string"
+                },
+                "section_lv01_id": {
+                    "type": "string"
+                },
+                "section_lv02_id": {
+                    "type": "string"
+                },
+                "section_lv03_id": {
+                    "type": "string"
+                },
+                "section_lv04_id": {
+                    "type": "string"
+                },
+                "section_lv05_id": {
+                    "type": "string"
+                },
+                "target_mode": {
+                    "description": "TargetMode: INCLUDE = เพิ่มพนักงานที่ตรงเงื่อนไข, EXCLUDE = ตัดพนักงานที่ตรงเงื่อนไขออกจาก INCLUDE (default INCLUDE)",
+                    "type": "string",
+                    "enum": [
+                        "INCLUDE",
+                        "EXCLUDE"
+                    ]
+                }
+            }
+        },
+        "api.ConditionGroupRequest": {
+            "type": "object",
+            "required": [
+                "conditions"
+            ],
+            "properties": {
+                "conditions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/api.ConditionRequest"
+                    }
+                },
+                "group_operator": {
+                    "type": "string",
+                    "enum": [
+                        "AND",
+                        "OR"
+                    ]
+                },
+                "sort_order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.ConditionRequest": {
+            "type": "object",
+            "required": [
+                "condition_id",
+                "operator_id"
+            ],
+            "properties": {
+                "comparison_operator": {
+                    "type": "string",
+                    "enum": [
+                        "AND",
+                        "OR"
+                    ]
+                },
+                "condition_id": {
+                    "type": "string"
+                },
+                "operator_id": {
+                    "type": "string"
+                },
+                "unit_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CreateBlackoutDateRequest": {
+            "type": "object",
+            "required": [
+                "calendar_code",
+                "end_date",
+                "start_date"
+            ],
+            "properties": {
+                "calendar_code": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "description": "รวมวันสุดท้ายด้วย (inclusive)",
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "ACTIVE",
+                        "INACTIVE"
+                    ]
+                }
+            }
+        },
+        "api.DeadLetterListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.DeadLetterResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.DeadLetterResponse": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "automation_id": {
+                    "type": "string"
+                },
+                "dead_letter_reason": {
+                    "type": "string"
+                },
+                "dead_lettered_at": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "is_catch_up": {
+                    "type": "boolean"
+                },
+                "is_dry_run": {
+                    "type": "boolean"
+                },
+                "log_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Message คือ dto.MessageServiceBus เดิมที่ถูกส่งเข้า dead-letter",
+                    "type": "object"
+                },
+                "replay_of_log_id": {
+                    "description": "ReplayOfLogID คือ execution ที่เข้า dead-letter แล้วถูก replay มาเป็นรายการนี้",
+                    "type": "string"
+                },
+                "replayed_by_log_id": {
+                    "type": "string"
+                },
+                "scheduled_for": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "target_failed": {
+                    "type": "integer"
+                },
+                "target_skipped": {
+                    "type": "integer"
+                },
+                "target_succeeded": {
+                    "type": "integer"
+                },
+                "target_total": {
+                    "description": "ผลรายผู้รับของ action แบบ fan-out",
+                    "type": "integer"
+                },
+                "trigger_type": {
+                    "type": "string"
+                },
+                "triggered_at": {
+                    "type": "string"
+                },
+                "triggered_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ExecutionDetailResponse": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "automation_id": {
+                    "type": "string"
+                },
+                "config_snapshot": {
+                    "type": "object"
+                },
+                "dead_letter_reason": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "is_catch_up": {
+                    "type": "boolean"
+                },
+                "is_dry_run": {
+                    "type": "boolean"
+                },
+                "log_id": {
+                    "type": "string"
+                },
+                "replay_of_log_id": {
+                    "description": "ReplayOfLogID คือ execution ที่เข้า dead-letter แล้วถูก replay มาเป็นรายการนี้",
+                    "type": "string"
+                },
+                "replayed_by_log_id": {
+                    "type": "string"
+                },
+                "scheduled_for": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LogAutomationActionExecution"
+                    }
+                },
+                "target_failed": {
+                    "type": "integer"
+                },
+                "target_skipped": {
+                    "type": "integer"
+                },
+                "target_succeeded": {
+                    "type": "integer"
+                },
+                "target_total": {
+                    "description": "ผลรายผู้รับของ action แบบ fan-out",
+                    "type": "integer"
+                },
+                "trigger_type": {
+                    "type": "string"
+                },
+                "triggered_at": {
+                    "type": "string"
+                },
+                "triggered_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ExecutionListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ExecutionResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ExecutionResponse": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "automation_id": {
+                    "type": "string"
+                },
+                "dead_letter_reason": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "is_catch_up": {
+                    "type": "boolean"
+                },
+                "is_dry_run": {
+                    "type": "boolean"
+                },
+                "log_id": {
+                    "type": "string"
+                },
+                "replay_of_log_id": {
+                    "description": "ReplayOfLogID คือ execution ที่เข้า dead-letter แล้วถูก replay มาเป็นรายการนี้",
+                    "type": "string"
+                },
+                "replayed_by_log_id": {
+                    "type": "string"
+                },
+                "scheduled_for": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "target_failed": {
+                    "type": "integer"
+                },
+                "target_skipped": {
+                    "type": "integer"
+                },
+                "target_succeeded": {
+                    "type": "integer"
+                },
+                "target_total": {
+                    "description": "ผลรายผู้รับของ action แบบ fan-out",
+                    "type": "integer"
+                },
+                "trigger_type": {
+                    "type": "string"
+                },
+                "triggered_at": {
+                    "type": "string"
+                },
+                "triggered_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.RenderTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "action_id": {
+                    "type": "string"
+                },
+                "automation_id": {
+                    "description": "AutomationID ใช้ข้อมูล automation, targets และรายชื่อผู้รับจริง (ว่าง = automation ตัวอย่าง)",
+                    "type": "string"
+                },
+                "config_json": {
+                    "type": "string"
+                },
+                "outputs": {
+                    "description": "Outputs คือ response จำลองของ action ก่อนหน้า (key = automation_action_id)",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "payload_template": {
+                    "description": "PayloadTemplate ที่ต้องการทดสอบ แทน payload_template ของ action_id (payload_template ใน config_json ยังมีผลก่อนเหมือนตอนรันจริง)",
+                    "type": "string"
+                },
+                "recipient": {
+                    "description": "Recipient จำลองการเรียกแบบ fan-out",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/directory.Recipient"
+                        }
+                    ]
+                }
+            }
+        },
+        "api.RenderTemplateResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "object"
+                }
+            }
+        },
+        "api.ReplayBatchRequest": {
+            "type": "object",
+            "properties": {
+                "automation_id": {
+                    "type": "string"
+                },
+                "dead_lettered_from": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "dead_lettered_to": {
+                    "description": "RFC3339",
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "reason": {
+                    "description": "เช่น RetryExhausted, ActionFailed, MaxDeliveryCountExceeded",
+                    "type": "string"
+                }
+            }
+        },
+        "api.ReplayBatchResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ReplayFailure"
+                    }
+                },
+                "replayed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ReplayResponse"
+                    }
+                }
+            }
+        },
+        "api.ReplayFailure": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "log_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ReplayResponse": {
+            "type": "object",
+            "properties": {
+                "automation_id": {
+                    "type": "string"
+                },
+                "log_id": {
+                    "type": "string"
+                },
+                "replay_of_log_id": {
+                    "type": "string"
+                },
+                "triggered_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.SchedulePreviewResponse": {
+            "type": "object",
+            "properties": {
+                "fire_times": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "time_zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ScheduleRequest": {
+            "type": "object",
+            "required": [
+                "frequency",
+                "start_date"
+            ],
+            "properties": {
+                "blackout_calendar": {
+                    "description": "calendar_code ของ def_blackout_dates (ว่าง = ไม่เช็ควันหยุด)",
+                    "type": "string"
+                },
+                "blackout_rule": {
+                    "description": "รอบที่ตรงวันหยุด: skip = ข้าม, shift = เลื่อนไปวันทำการถัดไป (default skip)",
+                    "type": "string",
+                    "enum": [
+                        "skip",
+                        "shift"
+                    ]
+                },
+                "cron_expression": {
+                    "description": "frequency = cron เช่น \"30 8 * * 1-5\", \"0 0 1,15 * *\", \"0 */2 * * *\"",
+                    "type": "string"
+                },
+                "day_of_month": {
+                    "type": "integer",
+                    "maximum": 31,
+                    "minimum": 1
+                },
+                "day_of_week": {
+                    "type": "string",
+                    "enum": [
+                        "sun",
+                        "mon",
+                        "tue",
+                        "wed",
+                        "thu",
+                        "fri",
+                        "sat"
+                    ]
+                },
+                "end_date": {
+                    "description": "ไม่รันรอบที่เลยวันนี้ไปแล้ว (ว่าง = ไม่มีกำหนด)",
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "once",
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "yearly",
+                        "cron"
+                    ]
+                },
+                "max_runs": {
+                    "description": "จำนวนครั้งสูงสุดที่จะรัน (0 = ไม่จำกัด)",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "month_of_year": {
+                    "type": "integer",
+                    "maximum": 12,
+                    "minimum": 1
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "description": "IANA เช่น Asia/Bangkok (ว่าง = timezone ของ server)",
+                    "type": "string"
+                }
+            }
+        },
+        "api.TargetPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "automation_id": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/directory.Recipient"
+                    }
+                },
+                "resolved_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.TriggerRequest": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "ประเมิน condition และสร้าง payload แต่ไม่เรียก action จริง (ดูสิ่งที่จะถูกส่งได้ที่ request_preview ของแต่ละ step)",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.TriggerResponse": {
+            "type": "object",
+            "properties": {
+                "automation_id": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "log_id": {
+                    "type": "string"
+                },
+                "triggered_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "directory.Recipient": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "department_id": {
+                    "type": "string"
+                },
+                "division_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "employee_name": {
+                    "type": "string"
+                },
+                "employee_type_code": {
+                    "type": "string"
+                },
+                "position_id": {
+                    "type": "string"
+                },
+                "section_id": {
+                    "type": "string"
+                },
+                "section_lv01_id": {
+                    "type": "string"
+                },
+                "section_lv02_id": {
+                    "type": "string"
+                },
+                "section_lv03_id": {
+                    "type": "string"
+                },
+                "section_lv04_id": {
+                    "type": "string"
+                },
+                "section_lv05_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AutomationSnapshot": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RunAutomationAction"
+                    }
+                },
+                "automation": {
+                    "$ref": "#/definitions/model.RunAutomation"
+                },
+                "condition_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RunAutomationConditionGroup"
+                    }
+                },
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RunAutomationCondition"
+                    }
+                },
+                "targets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RunAutomationTarget"
+                    }
+                }
+            }
+        },
+        "model.DefBlackoutDate": {
+            "type": "object",
+            "properties": {
+                "blackout_id": {
+                    "type": "string"
+                },
+                "calendar_code": {
+                    "type": "string"
+                },
+                "created": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "last_upd": {
+                    "type": "string"
+                },
+                "last_upd_by": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LogAutomationActionExecution": {
+            "type": "object",
+            "properties": {
+                "action_id": {
+                    "type": "string"
+                },
+                "attempt": {
+                    "type": "integer"
+                },
+                "automation_action_id": {
+                    "type": "string"
+                },
+                "context_json": {
+                    "type": "string"
+                },
+                "deadline_at": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "http_status": {
+                    "type": "integer"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "log_id": {
+                    "type": "string"
+                },
+                "output_json": {
+                    "type": "string"
+                },
+                "request_hash": {
+                    "type": "string"
+                },
+                "request_preview": {
+                    "type": "string"
+                },
+                "response_body": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "step_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RunAutomation": {
+            "type": "object",
+            "properties": {
+                "automation_id": {
+                    "type": "string"
+                },
+                "automation_name": {
+                    "type": "string"
+                },
+                "blackout_calendar": {
+                    "type": "string"
+                },
+                "blackout_rule": {
+                    "type": "string"
+                },
+                "created": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "cron_expression": {
+                    "type": "string"
+                },
+                "day_of_month": {
+                    "type": "integer"
+                },
+                "day_of_week": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string"
+                },
+                "instance_server_channel_id": {
+                    "type": "string"
+                },
+                "instance_server_id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "string"
+                },
+                "last_upd": {
+                    "type": "string"
+                },
+                "last_upd_by": {
+                    "type": "string"
+                },
+                "lifecycle_state": {
+                    "type": "string"
+                },
+                "lock_expires_at": {
+                    "type": "string"
+                },
+                "locked_by": {
+                    "type": "string"
+                },
+                "max_runs": {
+                    "type": "integer"
+                },
+                "misfire_policy": {
+                    "type": "string"
+                },
+                "month_of_year": {
+                    "type": "integer"
+                },
+                "next_run_time": {
+                    "type": "string"
+                },
+                "run_count": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RunAutomationAction": {
+            "type": "object",
+            "properties": {
+                "action_id": {
+                    "type": "string"
+                },
+                "automation_action_id": {
+                    "type": "string"
+                },
+                "automation_id": {
+                    "type": "string"
+                },
+                "config_json": {
+                    "type": "string"
+                },
+                "created": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "execution_mode": {
+                    "type": "string"
+                },
+                "fan_out_concurrency": {
+                    "type": "integer"
+                },
+                "last_upd": {
+                    "type": "string"
+                },
+                "last_upd_by": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.RunAutomationCondition": {
+            "type": "object",
+            "properties": {
+                "automation_condition_group_id": {
+                    "type": "string"
+                },
+                "automation_condition_id": {
+                    "type": "string"
+                },
+                "comparison_operator": {
+                    "type": "string"
+                },
+                "condition_id": {
+                    "type": "string"
+                },
+                "created": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "last_upd": {
+                    "type": "string"
+                },
+                "last_upd_by": {
+                    "type": "string"
+                },
+                "operator_id": {
+                    "type": "string"
+                },
+                "unit_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RunAutomationConditionGroup": {
+            "type": "object",
+            "properties": {
+                "automation_condition_group_id": {
+                    "type": "string"
+                },
+                "automation_id": {
+                    "type": "string"
+                },
+                "created": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "group_operator": {
+                    "type": "string"
+                },
+                "last_upd": {
+                    "type": "string"
+                },
+                "last_upd_by": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.RunAutomationTarget": {
+            "type": "object",
+            "properties": {
+                "automation_id": {
+                    "type": "string"
+                },
+                "automation_target_id": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "company_id": {
+                    "type": "string"
+                },
+                "created": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "department_id": {
+                    "type": "string"
+                },
+                "division_id": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "employee_type_code": {
+                    "type": "string"
+                },
+                "last_upd": {
+                    "type": "string"
+                },
+                "last_upd_by": {
+                    "type": "string"
+                },
+                "position_id": {
+                    "type": "string"
+                },
+                "section_id": {
+                    "type": "string"
+                },
+                "section_lv01_id": {
+                    "type": "string"
+                },
+                "section_lv02_id": {
+                    "type": "string"
+                },
+                "section_lv03_id": {
+                    "type": "string"
+                },
+                "section_lv04_id": {
+                    "type": "string"
+                },
+                "section_lv05_id": {
+                    "type": "string"
+                },
+                "target_mode": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
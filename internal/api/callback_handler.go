@@ -0,0 +1,82 @@
+package api
+
+import (
+	"automation-engine/internal/callback"
+	"automation-engine/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CallbackHandler struct {
+	logService service.LogService
+	signer     *callback.Signer
+}
+
+func NewCallbackHandler(logService service.LogService, signer *callback.Signer) *CallbackHandler {
+	return &CallbackHandler{
+		logService: logService,
+		signer:     signer,
+	}
+}
+
+type ActionCallbackRequest struct {
+	Status       string          `json:"status" binding:"required,oneof=SUCCESS FAILED"`
+	ErrorMessage string          `json:"error_message"`
+	Response     json.RawMessage `json:"response" swaggertype:"object"`
+}
+
+// CompleteActionExecution godoc
+// @Summary      Complete async action
+// @Description  ปลายทางของ action แบบ async เรียกกลับมาเพื่อปิด step (ใช้ X-Callback-Token ที่ได้รับตอนถูกเรียก แทน JWT)
+// @Tags         callbacks
+// @Accept       json
+// @Produce      json
+// @Param        step_id           path      string                     true  "Step ID (จาก X-Callback-URL)"
+// @Param        X-Callback-Token  header    string                     true  "Callback token"
+// @Param        body              body      api.ActionCallbackRequest  true  "Action result"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /callbacks/action-executions/{step_id} [post]
+func (h *CallbackHandler) CompleteActionExecution(c *gin.Context) {
+	stepID := c.Param("step_id")
+
+	// 1. ตรวจ token (HMAC ของ step_id)
+	if !h.signer.Verify(stepID, c.GetHeader(callback.TokenHeader)) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid callback token"})
+		return
+	}
+
+	// 2. Bind + Validate
+	var req ActionCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 3. ปิด step และสรุปผล execution
+	err := h.logService.CompleteStep(c.Request.Context(), stepID, service.StepResult{
+		Status:       req.Status,
+		ResponseBody: string(req.Response),
+		ErrorMessage: req.ErrorMessage,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "action execution not found"})
+		case errors.Is(err, service.ErrStepNotRunning):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
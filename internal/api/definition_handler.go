@@ -1,24 +1,38 @@
 package api
 
 import (
+	"automation-engine/internal/directory"
 	"automation-engine/internal/domain/model"
+	"automation-engine/internal/dto"
 	"automation-engine/internal/service"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type DefinitionHandler struct {
 	definitionService service.DefinitionService
+	runService        service.RunService
+	targetService     service.TargetService
 }
 
-func NewDefinitionHandler(definitionService service.DefinitionService) *DefinitionHandler {
+func NewDefinitionHandler(definitionService service.DefinitionService, runService service.RunService, targetService service.TargetService) *DefinitionHandler {
 	return &DefinitionHandler{
 		definitionService: definitionService,
+		runService:        runService,
+		targetService:     targetService,
 	}
 }
 
+// maskedHeaderValue replaces the values of invoke_headers in responses
+const maskedHeaderValue = "***"
+
 type ActionResponse struct {
 	ActionID     string `json:"action_id"`
 	ActionCode   string `json:"action_code"`
@@ -28,6 +42,17 @@ type ActionResponse struct {
 	InvokeMethod string `json:"invoke_method"`
 	InvokeType   string `json:"invoke_type"`
 	Status       string `json:"status"`
+	// InvokeHeaders ถูกเก็บเป็น JSON object ในคอลัมน์ invoke_headers, ค่าของ header ถูกปิดเป็น *** (มักเป็น token)
+	InvokeHeaders map[string]string `json:"invoke_headers"`
+	// InvokeTimeout เป็นวินาที, 0 = ใช้ค่า default ของ httpclient (60s)
+	InvokeTimeout int32 `json:"invoke_timeout"`
+	// Retry policy ดู CreateActionRequest
+	RetryMaxAttempts       int32 `json:"retry_max_attempts"`
+	RetryBackoffSeconds    int32 `json:"retry_backoff_seconds"`
+	RetryMaxBackoffSeconds int32 `json:"retry_max_backoff_seconds"`
+	RetryStatusCodes       []int `json:"retry_status_codes"`
+	// PayloadTemplate ดู CreateActionRequest
+	PayloadTemplate string `json:"payload_template"`
 }
 
 // GetActionByID godoc
@@ -62,7 +87,7 @@ func (h *DefinitionHandler) GetActionByID(c *gin.Context) {
 	}
 
 	// 3. ส่งข้อมูลกลับ
-	c.JSON(http.StatusOK, action)
+	c.JSON(http.StatusOK, toActionResponse(action))
 }
 
 // CreateAction godoc
@@ -84,8 +109,23 @@ type CreateActionRequest struct {
 	ActionType   string `json:"action_type" binding:"required"`
 	InvokeURL    string `json:"invoke_url" binding:"required,url"`
 	InvokeMethod string `json:"invoke_method" binding:"required,oneof=GET POST PUT DELETE"`
-	InvokeType   string `json:"invoke_type" binding:"required"`
+	InvokeType   string `json:"invoke_type" binding:"required,oneof=sync async"`
 	Status       string `json:"status" binding:"required,oneof=ACTIVE INACTIVE"`
+	// InvokeHeaders คือ header เพิ่มเติมที่ส่งไปพร้อมกับการเรียก action
+	InvokeHeaders map[string]string `json:"invoke_headers"`
+	// InvokeTimeout เป็นวินาที, 0 = ใช้ค่า default (60s)
+	InvokeTimeout int32 `json:"invoke_timeout" binding:"min=0"`
+	// RetryMaxAttempts คือจำนวนครั้งที่เรียกได้ทั้งหมดรวมครั้งแรก, 0/1 = ไม่ retry (ล้มเหลวแล้วเข้า dead-letter ทันที)
+	RetryMaxAttempts int32 `json:"retry_max_attempts" binding:"min=0,max=20"`
+	// RetryBackoffSeconds คือเวลารอก่อน retry ครั้งแรก แล้วเพิ่มเท่าตัวทุกครั้ง, 0 = 30 วินาที
+	RetryBackoffSeconds int32 `json:"retry_backoff_seconds" binding:"min=0"`
+	// RetryMaxBackoffSeconds คือเพดานของเวลารอ, 0 = 1 ชั่วโมง
+	RetryMaxBackoffSeconds int32 `json:"retry_max_backoff_seconds" binding:"min=0"`
+	// RetryStatusCodes คือ HTTP status ที่ควร retry, ว่าง = 408, 429 และ 5xx (network error/timeout retry เสมอ)
+	RetryStatusCodes []int `json:"retry_status_codes" binding:"dive,min=100,max=599"`
+	// PayloadTemplate คือ Go text/template ที่ render เป็น body ของ request (ต้องได้ JSON), ว่าง = ส่ง config_json + context ของ automation
+	// เช่น {"title": {{ json .Automation.AutomationName }}, "date": {{ json (formatTime "2006-01-02" .TriggeredAt) }}}
+	PayloadTemplate string `json:"payload_template"`
 }
 
 func (h *DefinitionHandler) CreateAction(c *gin.Context) {
@@ -100,19 +140,44 @@ func (h *DefinitionHandler) CreateAction(c *gin.Context) {
 	}
 
 	// 2. Map request → domain model
+	var invokeHeaders string
+	if len(req.InvokeHeaders) > 0 {
+		headerBytes, err := json.Marshal(req.InvokeHeaders)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		invokeHeaders = string(headerBytes)
+	}
+
 	action := &model.DefAction{
-		ActionID:     req.ActionID,
-		ActionCode:   req.ActionCode,
-		ActionName:   req.ActionName,
-		ActionType:   req.ActionType,
-		InvokeURL:    req.InvokeURL,
-		InvokeMethod: req.InvokeMethod,
-		InvokeType:   req.InvokeType,
-		Status:       req.Status,
+		ActionID:      req.ActionID,
+		ActionCode:    req.ActionCode,
+		ActionName:    req.ActionName,
+		ActionType:    req.ActionType,
+		InvokeURL:     req.InvokeURL,
+		InvokeMethod:  req.InvokeMethod,
+		InvokeType:    req.InvokeType,
+		Status:        req.Status,
+		InvokeHeaders: invokeHeaders,
+		InvokeTimeout: req.InvokeTimeout,
+		// retry policy
+		RetryMaxAttempts:       req.RetryMaxAttempts,
+		RetryBackoffSeconds:    req.RetryBackoffSeconds,
+		RetryMaxBackoffSeconds: req.RetryMaxBackoffSeconds,
+		RetryStatusCodes:       service.FormatRetryStatusCodes(req.RetryStatusCodes),
+		PayloadTemplate:        req.PayloadTemplate,
+	}
+	if action.RetryMaxAttempts == 0 {
+		action.RetryMaxAttempts = 1
 	}
 
 	// 3. Call service
 	if err := h.definitionService.CreateAction(c.Request.Context(), action); err != nil {
+		if errors.Is(err, service.ErrInvalidAction) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "failed to create action",
 		})
@@ -120,16 +185,234 @@ func (h *DefinitionHandler) CreateAction(c *gin.Context) {
 	}
 
 	// 4. Response
+	c.JSON(http.StatusCreated, toActionResponse(action))
+}
+
+func toActionResponse(action *model.DefAction) ActionResponse {
 	resp := ActionResponse{
-		ActionID:     action.ActionID,
-		ActionCode:   action.ActionCode,
-		ActionName:   action.ActionName,
-		ActionType:   action.ActionType,
-		InvokeURL:    action.InvokeURL,
-		InvokeMethod: action.InvokeMethod,
-		InvokeType:   action.InvokeType,
-		Status:       action.Status,
+		ActionID:      action.ActionID,
+		ActionCode:    action.ActionCode,
+		ActionName:    action.ActionName,
+		ActionType:    action.ActionType,
+		InvokeURL:     action.InvokeURL,
+		InvokeMethod:  action.InvokeMethod,
+		InvokeType:    action.InvokeType,
+		Status:        action.Status,
+		InvokeTimeout: action.InvokeTimeout,
+		// retry policy
+		RetryMaxAttempts:       action.RetryMaxAttempts,
+		RetryBackoffSeconds:    action.RetryBackoffSeconds,
+		RetryMaxBackoffSeconds: action.RetryMaxBackoffSeconds,
+		RetryStatusCodes:       []int{},
+		PayloadTemplate:        action.PayloadTemplate,
+	}
+
+	// ส่งกลับแค่ชื่อ header ไม่ส่งค่า
+	if action.InvokeHeaders != "" {
+		var headers map[string]string
+		if err := json.Unmarshal([]byte(action.InvokeHeaders), &headers); err == nil {
+			resp.InvokeHeaders = make(map[string]string, len(headers))
+			for key := range headers {
+				resp.InvokeHeaders[key] = maskedHeaderValue
+			}
+		}
+	}
+
+	if codes, err := service.ParseRetryStatusCodes(action.RetryStatusCodes); err == nil {
+		for code := range codes {
+			resp.RetryStatusCodes = append(resp.RetryStatusCodes, code)
+		}
+		sort.Ints(resp.RetryStatusCodes)
+	}
+	return resp
+}
+
+type RenderTemplateRequest struct {
+	// PayloadTemplate ที่ต้องการทดสอบ แทน payload_template ของ action_id (payload_template ใน config_json ยังมีผลก่อนเหมือนตอนรันจริง)
+	PayloadTemplate string `json:"payload_template"`
+	ActionID        string `json:"action_id"`
+	ConfigJSON      string `json:"config_json"`
+	// AutomationID ใช้ข้อมูล automation, targets และรายชื่อผู้รับจริง (ว่าง = automation ตัวอย่าง)
+	AutomationID string `json:"automation_id"`
+	// Recipient จำลองการเรียกแบบ fan-out
+	Recipient *directory.Recipient `json:"recipient"`
+	// Outputs คือ response จำลองของ action ก่อนหน้า (key = automation_action_id)
+	Outputs map[string]interface{} `json:"outputs"`
+}
+
+type RenderTemplateResponse struct {
+	Body json.RawMessage `json:"body" swaggertype:"object"`
+}
+
+// RenderTemplate godoc
+// @Summary      Render payload template
+// @Description  ทดสอบ render payload template กับข้อมูล automation (หรือข้อมูลตัวอย่าง) ก่อนบันทึก โดยไม่เรียก action จริง
+// @Tags         definition
+// @Accept       json
+// @Produce      json
+// @Param        body  body      api.RenderTemplateRequest  true  "Template and sample data"
+// @Success      200   {object}  api.RenderTemplateResponse
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      422   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /definition/actions/render-test [post]
+// @Security BearerAuth
+func (h *DefinitionHandler) RenderTemplate(c *gin.Context) {
+	var req RenderTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+
+	// 1. template ของ action (ถ้าระบุ action_id)
+	action := &model.DefAction{ActionID: req.ActionID}
+	if req.ActionID != "" {
+		found, err := h.definitionService.GetActionByID(ctx, req.ActionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "action not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		action = found
+	}
+	if req.PayloadTemplate != "" {
+		override := *action
+		override.PayloadTemplate = req.PayloadTemplate
+		action = &override
+	}
+
+	// 2. ข้อมูล automation จริง หรือข้อมูลตัวอย่าง
+	actionCtx := dto.ActionContext{
+		LogID:       "RENDER_TEST",
+		Automation:  &model.RunAutomation{AutomationID: "SAMPLE", AutomationName: "Sample automation"},
+		Recipient:   req.Recipient,
+		TriggeredAt: time.Now(),
+		Outputs:     req.Outputs,
+	}
+	if req.AutomationID != "" {
+		snapshot, err := h.runService.GetAutomationSnapshot(ctx, req.AutomationID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "automation not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		recipients, err := h.targetService.ResolveTargets(ctx, snapshot.Targets)
+		if err != nil && !errors.Is(err, directory.ErrNoProvider) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		actionCtx.Automation = snapshot.Automation
+		actionCtx.Targets = snapshot.Targets
+		actionCtx.Recipients = recipients
+	}
+	if actionCtx.Outputs == nil {
+		actionCtx.Outputs = map[string]interface{}{}
+	}
+
+	// 3. Render แบบเดียวกับ worker
+	body, err := dto.BuildActionPayload(action, &model.RunAutomationAction{
+		AutomationActionID: "RENDER_TEST",
+		ActionID:           req.ActionID,
+		ConfigJSON:         req.ConfigJSON,
+	}, actionCtx)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, RenderTemplateResponse{Body: body})
+}
+
+type CreateBlackoutDateRequest struct {
+	CalendarCode string `json:"calendar_code" binding:"required,max=50"`
+	StartDate    string `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate      string `json:"end_date" binding:"required,datetime=2006-01-02"` // รวมวันสุดท้ายด้วย (inclusive)
+	Description  string `json:"description"`
+	Status       string `json:"status" binding:"omitempty,oneof=ACTIVE INACTIVE"`
+}
+
+// CreateBlackoutDate godoc
+// @Summary      Create blackout date range
+// @Description  เพิ่มช่วงวันหยุด/blackout ลงใน calendar (scheduler จะ skip หรือ shift รอบที่ตรงกับช่วงนี้ตาม blackout_rule ของ automation)
+// @Tags         definition
+// @Accept       json
+// @Produce      json
+// @Param        body  body      api.CreateBlackoutDateRequest  true  "Blackout Date Payload"
+// @Success      201   {object}  model.DefBlackoutDate
+// @Failure      400   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /definition/blackout-dates [post]
+// @Security BearerAuth
+func (h *DefinitionHandler) CreateBlackoutDate(c *gin.Context) {
+	var req CreateBlackoutDateRequest
+
+	// 1. Bind + Validate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 2. วันที่ผ่าน validate แล้ว จึง parse ได้เสมอ
+	startDate, _ := time.Parse(time.DateOnly, req.StartDate)
+	endDate, _ := time.Parse(time.DateOnly, req.EndDate)
+	if endDate.Before(startDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return
+	}
+
+	status := req.Status
+	if status == "" {
+		status = "ACTIVE"
+	}
+
+	userID := c.GetString("user_id")
+	now := time.Now()
+	blackout := &model.DefBlackoutDate{
+		CalendarCode: req.CalendarCode,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Description:  req.Description,
+		Status:       status,
+		Created:      now,
+		CreatedBy:    userID,
+		LastUpd:      now,
+		LastUpdBy:    userID,
+	}
+
+	// 3. Call service
+	if err := h.definitionService.CreateBlackoutDate(c.Request.Context(), blackout); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create blackout date"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, blackout)
+}
+
+// ListBlackoutDates godoc
+// @Summary      List blackout dates
+// @Description  ดึงช่วงวันหยุด/blackout ทั้งหมด หรือเฉพาะของ calendar ที่ระบุ
+// @Tags         definition
+// @Produce      json
+// @Param        calendar_code  query     string  false  "Calendar code (e.g. TH_HOLIDAY)"
+// @Success      200            {array}   model.DefBlackoutDate
+// @Failure      500            {object}  map[string]string
+// @Router       /definition/blackout-dates [get]
+// @Security BearerAuth
+func (h *DefinitionHandler) ListBlackoutDates(c *gin.Context) {
+	rows, err := h.definitionService.ListBlackoutDates(c.Request.Context(), c.Query("calendar_code"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusCreated, resp)
+	c.JSON(http.StatusOK, rows)
 }
@@ -1,21 +1,393 @@
 package api
 
 import (
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/dto"
+	"automation-engine/internal/repository"
 	"automation-engine/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type LogHandler struct {
-	logService service.LogService
+	logService     service.LogService
+	triggerService service.TriggerService
 }
 
-func NewLogHandler(logService service.LogService) *LogHandler {
+func NewLogHandler(logService service.LogService, triggerService service.TriggerService) *LogHandler {
 	return &LogHandler{
-		logService: logService,
+		logService:     logService,
+		triggerService: triggerService,
 	}
 }
 
-func (h *LogHandler) CreateAutomationExecution(c *gin.Context) {
-	// Implementation for creating an automation execution log
+type ExecutionResponse struct {
+	LogID        string    `json:"log_id"`
+	AutomationID string    `json:"automation_id"`
+	Status       string    `json:"status"`
+	TriggeredAt  time.Time `json:"triggered_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+	ErrorMessage string    `json:"error_message"`
+	ScheduledFor time.Time `json:"scheduled_for"`
+	IsCatchUp    bool      `json:"is_catch_up"`
+	TriggerType  string    `json:"trigger_type"`
+	TriggeredBy  string    `json:"triggered_by"`
+	IsDryRun     bool      `json:"is_dry_run"`
+	Attempt      int32     `json:"attempt"`
+	// ReplayOfLogID คือ execution ที่เข้า dead-letter แล้วถูก replay มาเป็นรายการนี้
+	ReplayOfLogID    string `json:"replay_of_log_id"`
+	ReplayedByLogID  string `json:"replayed_by_log_id"`
+	DeadLetterReason string `json:"dead_letter_reason"`
+	// ผลรายผู้รับของ action แบบ fan-out
+	TargetTotal     int32 `json:"target_total"`
+	TargetSucceeded int32 `json:"target_succeeded"`
+	TargetFailed    int32 `json:"target_failed"`
+	TargetSkipped   int32 `json:"target_skipped"`
+}
+
+type ExecutionListResponse struct {
+	Data       []ExecutionResponse `json:"data"`
+	NextCursor string              `json:"next_cursor"`
+}
+
+type ExecutionDetailResponse struct {
+	ExecutionResponse
+	ConfigSnapshot json.RawMessage                       `json:"config_snapshot" swaggertype:"object"`
+	Steps          []*model.LogAutomationActionExecution `json:"steps"`
+}
+
+// ListAutomationExecutions godoc
+// @Summary      List automation executions
+// @Description  ค้นหาประวัติการรัน Automation เรียงจากล่าสุด (cursor pagination ด้วย log_id)
+// @Tags         logs
+// @Produce      json
+// @Param        automation_id   query     string  false  "Automation ID"
+// @Param        status          query     string  false  "SUCCESS, FAILED, PARTIAL, SKIPPED, RUNNING, RETRYING"
+// @Param        triggered_from  query     string  false  "RFC3339 เช่น 2024-01-01T00:00:00+07:00"
+// @Param        triggered_to    query     string  false  "RFC3339"
+// @Param        cursor          query     string  false  "next_cursor จากหน้าก่อนหน้า"
+// @Param        limit           query     int     false  "จำนวนต่อหน้า (default 50, max 200)"
+// @Success      200  {object}  api.ExecutionListResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /logs/automation-executions [get]
+// @Security BearerAuth
+func (h *LogHandler) ListAutomationExecutions(c *gin.Context) {
+	filter := repository.ExecutionFilter{
+		AutomationID: c.Query("automation_id"),
+		Status:       c.Query("status"),
+		Cursor:       c.Query("cursor"),
+	}
+
+	var err error
+	if filter.TriggeredFrom, err = parseTimeQuery(c, "triggered_from"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.TriggeredTo, err = parseTimeQuery(c, "triggered_to"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+			return
+		}
+	}
+
+	rows, nextCursor, err := h.logService.ListExecutions(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := ExecutionListResponse{
+		Data:       make([]ExecutionResponse, 0, len(rows)),
+		NextCursor: nextCursor,
+	}
+	for _, row := range rows {
+		resp.Data = append(resp.Data, toExecutionResponse(row))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetAutomationExecution godoc
+// @Summary      Get automation execution
+// @Description  รายละเอียดการรัน พร้อม config_snapshot ที่ใช้ตอนรัน, error message และผลของแต่ละ action (steps)
+// @Tags         logs
+// @Produce      json
+// @Param        log_id  path      string  true  "Log ID"
+// @Success      200     {object}  api.ExecutionDetailResponse
+// @Failure      404     {object}  map[string]string
+// @Router       /logs/automation-executions/{log_id} [get]
+// @Security BearerAuth
+func (h *LogHandler) GetAutomationExecution(c *gin.Context) {
+	row, err := h.logService.GetExecution(c.Request.Context(), c.Param("log_id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "execution not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := ExecutionDetailResponse{
+		ExecutionResponse: toExecutionResponse(row),
+	}
+
+	// snapshot บางรายการอาจว่าง (เช่น fail ก่อนโหลด automation)
+	if json.Valid([]byte(row.ConfigSnapshot)) {
+		resp.ConfigSnapshot = json.RawMessage(row.ConfigSnapshot)
+	}
+
+	steps, err := h.logService.ListSteps(c.Request.Context(), row.LogID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	resp.Steps = steps
+
+	c.JSON(http.StatusOK, resp)
+}
+
+type DeadLetterResponse struct {
+	ExecutionResponse
+	DeadLetteredAt time.Time `json:"dead_lettered_at"`
+	// Message คือ dto.MessageServiceBus เดิมที่ถูกส่งเข้า dead-letter
+	Message json.RawMessage `json:"message" swaggertype:"object"`
+}
+
+type DeadLetterListResponse struct {
+	Data       []DeadLetterResponse `json:"data"`
+	NextCursor string               `json:"next_cursor"`
+}
+
+type ReplayResponse struct {
+	LogID         string    `json:"log_id"`
+	ReplayOfLogID string    `json:"replay_of_log_id"`
+	AutomationID  string    `json:"automation_id"`
+	TriggeredAt   time.Time `json:"triggered_at"`
+}
+
+type ReplayBatchRequest struct {
+	AutomationID     string     `json:"automation_id"`
+	Reason           string     `json:"reason"`             // เช่น RetryExhausted, ActionFailed, MaxDeliveryCountExceeded
+	DeadLetteredFrom *time.Time `json:"dead_lettered_from"` // RFC3339
+	DeadLetteredTo   *time.Time `json:"dead_lettered_to"`   // RFC3339
+	Limit            int        `json:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type ReplayFailure struct {
+	LogID string `json:"log_id"`
+	Error string `json:"error"`
+}
+
+type ReplayBatchResponse struct {
+	Replayed []ReplayResponse `json:"replayed"`
+	Failed   []ReplayFailure  `json:"failed"`
+}
+
+// ListDeadLetters godoc
+// @Summary      List dead-lettered executions
+// @Description  execution ที่ message ถูกย้ายเข้า dead-letter (retry ครบ, action ล้มเหลวแบบ retry ไม่ได้ หรือส่งซ้ำเกินจำนวน) พร้อมเหตุผลและ message เดิม
+// @Tags         logs
+// @Produce      json
+// @Param        automation_id       query     string  false  "Automation ID"
+// @Param        reason              query     string  false  "RetryExhausted, ActionFailed, MaxDeliveryCountExceeded"
+// @Param        dead_lettered_from  query     string  false  "RFC3339"
+// @Param        dead_lettered_to    query     string  false  "RFC3339"
+// @Param        include_replayed    query     bool    false  "รวมรายการที่ replay ไปแล้ว (default false)"
+// @Param        cursor              query     string  false  "next_cursor จากหน้าก่อนหน้า"
+// @Param        limit               query     int     false  "จำนวนต่อหน้า (default 50, max 200)"
+// @Success      200  {object}  api.DeadLetterListResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /logs/dead-letters [get]
+// @Security BearerAuth
+func (h *LogHandler) ListDeadLetters(c *gin.Context) {
+	filter := repository.ExecutionFilter{
+		AutomationID:     c.Query("automation_id"),
+		DeadLettered:     true,
+		DeadLetterReason: c.Query("reason"),
+		ExcludeReplayed:  c.Query("include_replayed") != "true",
+		Cursor:           c.Query("cursor"),
+	}
+
+	var err error
+	if filter.DeadLetteredFrom, err = parseTimeQuery(c, "dead_lettered_from"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.DeadLetteredTo, err = parseTimeQuery(c, "dead_lettered_to"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+			return
+		}
+	}
+
+	rows, nextCursor, err := h.logService.ListExecutions(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := DeadLetterListResponse{
+		Data:       make([]DeadLetterResponse, 0, len(rows)),
+		NextCursor: nextCursor,
+	}
+	for _, row := range rows {
+		item := DeadLetterResponse{
+			ExecutionResponse: toExecutionResponse(row),
+			DeadLetteredAt:    row.DeadLetteredAt,
+		}
+		if json.Valid([]byte(row.MessagePayload)) {
+			item.Message = json.RawMessage(row.MessagePayload)
+		}
+		resp.Data = append(resp.Data, item)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ReplayDeadLetter godoc
+// @Summary      Replay dead-lettered execution
+// @Description  ส่ง message เดิมกลับเข้า automate_queue ด้วย log_id ใหม่ (replay_of_log_id ชี้ไปที่ log เดิม)
+// @Tags         logs
+// @Produce      json
+// @Param        log_id  path      string  true  "Log ID ของ execution ที่เข้า dead-letter"
+// @Success      202     {object}  api.ReplayResponse
+// @Failure      404     {object}  map[string]string
+// @Failure      409     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Router       /logs/dead-letters/{log_id}/replay [post]
+// @Security BearerAuth
+func (h *LogHandler) ReplayDeadLetter(c *gin.Context) {
+	msg, err := h.triggerService.ReplayExecution(c.Request.Context(), c.Param("log_id"), c.GetString("user_id"))
+	if err != nil {
+		respondReplayError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, toReplayResponse(msg))
+}
+
+// ReplayDeadLetters godoc
+// @Summary      Replay dead-lettered executions
+// @Description  replay execution ที่เข้า dead-letter และยังไม่เคย replay ตามเงื่อนไขที่ระบุ (สูงสุด 100 รายการต่อครั้ง)
+// @Tags         logs
+// @Accept       json
+// @Produce      json
+// @Param        body  body      api.ReplayBatchRequest  true  "Filter"
+// @Success      202   {object}  api.ReplayBatchResponse
+// @Failure      400   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /logs/dead-letters/replay [post]
+// @Security BearerAuth
+func (h *LogHandler) ReplayDeadLetters(c *gin.Context) {
+	var req ReplayBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := repository.ExecutionFilter{
+		AutomationID:     req.AutomationID,
+		DeadLetterReason: req.Reason,
+		Limit:            req.Limit,
+	}
+	if req.DeadLetteredFrom != nil {
+		filter.DeadLetteredFrom = *req.DeadLetteredFrom
+	}
+	if req.DeadLetteredTo != nil {
+		filter.DeadLetteredTo = *req.DeadLetteredTo
+	}
+
+	results, err := h.triggerService.ReplayDeadLetters(c.Request.Context(), filter, c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := ReplayBatchResponse{
+		Replayed: make([]ReplayResponse, 0, len(results)),
+		Failed:   make([]ReplayFailure, 0),
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			resp.Failed = append(resp.Failed, ReplayFailure{LogID: result.OriginalLogID, Error: result.Err.Error()})
+			continue
+		}
+		resp.Replayed = append(resp.Replayed, toReplayResponse(result.Message))
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+func toReplayResponse(msg *dto.MessageServiceBus) ReplayResponse {
+	return ReplayResponse{
+		LogID:         msg.LogID,
+		ReplayOfLogID: msg.ReplayOfLogID,
+		AutomationID:  msg.AutomationID,
+		TriggeredAt:   msg.TriggeredAt,
+	}
+}
+
+func respondReplayError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotDeadLettered), errors.Is(err, service.ErrAlreadyReplayed), errors.Is(err, service.ErrAutomationArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func toExecutionResponse(row *model.LogAutomationExecution) ExecutionResponse {
+	return ExecutionResponse{
+		LogID:            row.LogID,
+		AutomationID:     row.AutomationID,
+		Status:           row.Status,
+		TriggeredAt:      row.TriggeredAt,
+		FinishedAt:       row.FinishedAt,
+		ErrorMessage:     row.ErrorMessage,
+		ScheduledFor:     row.ScheduledFor,
+		IsCatchUp:        row.IsCatchUp,
+		TriggerType:      row.TriggerType,
+		TriggeredBy:      row.TriggeredBy,
+		IsDryRun:         row.IsDryRun,
+		Attempt:          row.Attempt,
+		ReplayOfLogID:    row.ReplayOfLogID,
+		ReplayedByLogID:  row.ReplayedByLogID,
+		DeadLetterReason: row.DeadLetterReason,
+		TargetTotal:      row.TargetTotal,
+		TargetSucceeded:  row.TargetSucceeded,
+		TargetFailed:     row.TargetFailed,
+		TargetSkipped:    row.TargetSkipped,
+	}
+}
+
+func parseTimeQuery(c *gin.Context, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New(key + " must be RFC3339")
+	}
+	return t, nil
 }
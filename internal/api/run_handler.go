@@ -1,21 +1,590 @@
 package api
 
 import (
+	"automation-engine/internal/directory"
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/dto"
 	"automation-engine/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type RunHandler struct {
-	runService service.RunService
+	runService     service.RunService
+	triggerService service.TriggerService
+	targetService  service.TargetService
 }
 
-func NewRunHandler(runService service.RunService) *RunHandler {
+func NewRunHandler(runService service.RunService, triggerService service.TriggerService, targetService service.TargetService) *RunHandler {
 	return &RunHandler{
-		runService: runService,
+		runService:     runService,
+		triggerService: triggerService,
+		targetService:  targetService,
 	}
 }
 
+type AutomationRequest struct {
+	AutomationName          string `json:"automation_name" binding:"required"`
+	InstanceServerID        string `json:"instance_server_id" binding:"required"`
+	InstanceServerChannelID string `json:"instance_server_channel_id" binding:"required"`
+	ScheduleRequest
+	LifecycleState  string                    `json:"lifecycle_state" binding:"omitempty,oneof=DRAFT ACTIVE"`                              // ตอนสร้างเท่านั้น (default ACTIVE), PUT ไม่เปลี่ยน state
+	MisfirePolicy   string                    `json:"misfire_policy" binding:"omitempty,oneof=fire_once_now fire_all_missed skip_to_next"` // รอบที่พลาดไป (เช่น scheduler ล่ม), default fire_once_now
+	ConditionGroups []ConditionGroupRequest   `json:"condition_groups" binding:"dive"`
+	Actions         []AutomationActionRequest `json:"actions" binding:"required,min=1,dive"`
+	Targets         []AutomationTargetRequest `json:"targets" binding:"dive"`
+}
+
+// ScheduleRequest holds the schedule fields of an automation, also used on its own by the stateless schedule preview
+type ScheduleRequest struct {
+	Frequency        string     `json:"frequency" binding:"required,oneof=once daily weekly monthly yearly cron"`
+	StartDate        time.Time  `json:"start_date" binding:"required"`
+	DayOfWeek        string     `json:"day_of_week" binding:"omitempty,oneof=sun mon tue wed thu fri sat"`
+	DayOfMonth       int32      `json:"day_of_month" binding:"omitempty,min=1,max=31"`
+	MonthOfYear      int32      `json:"month_of_year" binding:"omitempty,min=1,max=12"`
+	CronExpression   string     `json:"cron_expression" binding:"required_if=Frequency cron"` // frequency = cron เช่น "30 8 * * 1-5", "0 0 1,15 * *", "0 */2 * * *"
+	TimeZone         string     `json:"time_zone"`                                            // IANA เช่น Asia/Bangkok (ว่าง = timezone ของ server)
+	EndDate          *time.Time `json:"end_date"`                                             // ไม่รันรอบที่เลยวันนี้ไปแล้ว (ว่าง = ไม่มีกำหนด)
+	MaxRuns          int32      `json:"max_runs" binding:"omitempty,min=0"`                   // จำนวนครั้งสูงสุดที่จะรัน (0 = ไม่จำกัด)
+	BlackoutCalendar string     `json:"blackout_calendar"`                                    // calendar_code ของ def_blackout_dates (ว่าง = ไม่เช็ควันหยุด)
+	BlackoutRule     string     `json:"blackout_rule" binding:"omitempty,oneof=skip shift"`   // รอบที่ตรงวันหยุด: skip = ข้าม, shift = เลื่อนไปวันทำการถัดไป (default skip)
+}
+
+type ConditionGroupRequest struct {
+	GroupOperator string             `json:"group_operator" binding:"omitempty,oneof=AND OR"`
+	SortOrder     int32              `json:"sort_order"`
+	Conditions    []ConditionRequest `json:"conditions" binding:"required,min=1,dive"`
+}
+
+type ConditionRequest struct {
+	ConditionID        string `json:"condition_id" binding:"required"`
+	OperatorID         string `json:"operator_id" binding:"required"`
+	Value              string `json:"value"`
+	UnitID             string `json:"unit_id"`
+	ComparisonOperator string `json:"comparison_operator" binding:"omitempty,oneof=AND OR"`
+}
+
+type AutomationActionRequest struct {
+	ActionID string `json:"action_id" binding:"required"`
+	// ConfigJSON เป็น JSON object ที่ถูก merge เข้ากับ payload ของ action
+	// (log_id, automation_action_id, automation, targets, recipients, recipient, triggered_at เป็น key สงวนของระบบ)
+	ConfigJSON string `json:"config_json"`
+	// SortOrder ลำดับการเรียก action (น้อยไปมาก)
+	SortOrder int32 `json:"sort_order"`
+	// ExecutionMode: SINGLE = เรียกครั้งเดียวต่อ execution, FAN_OUT = เรียกแยกรายผู้รับ (payload มี key recipient) default SINGLE
+	ExecutionMode string `json:"execution_mode" binding:"omitempty,oneof=SINGLE FAN_OUT"`
+	// FanOutConcurrency จำนวนผู้รับที่เรียกพร้อมกัน (0 = ค่า FAN_OUT_POOL ของ worker)
+	FanOutConcurrency int32 `json:"fan_out_concurrency" binding:"omitempty,min=0,max=50"`
+}
+
+type AutomationTargetRequest struct {
+	// TargetMode: INCLUDE = เพิ่มพนักงานที่ตรงเงื่อนไข, EXCLUDE = ตัดพนักงานที่ตรงเงื่อนไขออกจาก INCLUDE (default INCLUDE)
+	TargetMode       string `json:"target_mode" binding:"omitempty,oneof=INCLUDE EXCLUDE"`
+	CompanyID        string `json:"company_id"`
+	BranchID         string `json:"branch_id"`
+	DepartmentID     string `json:"department_id"`
+	DivisionID       string `json:"division_id"`
+	SectionID        string `json:"section_id"`
+	SectionLv01ID    string `json:"section_lv01_id"`
+	SectionLv02ID    string `json:"section_lv02_id"`
+	SectionLv03ID    string `json:"section_lv03_id"`
+	SectionLv04ID    string `json:"section_lv04_id"`
+	SectionLv05ID    string `json:"section_lv05_id"`
+	PositionID       string `json:"position_id"`
+	EmployeeTypeCode string `json:"employee_type_code"`
+	EmployeeID       string `json:"employee_id"`
+}
+
+// toAutomation maps the schedule fields → model.RunAutomation
+func (req *ScheduleRequest) toAutomation() *model.RunAutomation {
+	automation := &model.RunAutomation{
+		Frequency:        req.Frequency,
+		StartDate:        req.StartDate,
+		DayOfWeek:        req.DayOfWeek,
+		DayOfMonth:       req.DayOfMonth,
+		MonthOfYear:      req.MonthOfYear,
+		CronExpression:   req.CronExpression,
+		TimeZone:         req.TimeZone,
+		MaxRuns:          req.MaxRuns,
+		BlackoutCalendar: req.BlackoutCalendar,
+		BlackoutRule:     req.BlackoutRule,
+	}
+	if req.EndDate != nil {
+		automation.EndDate = *req.EndDate
+	}
+	return automation
+}
+
+// toSnapshot maps the nested request document → dto.AutomationSnapshot (IDs are assigned by RunService)
+func (req *AutomationRequest) toSnapshot() *dto.AutomationSnapshot {
+	automation := req.ScheduleRequest.toAutomation()
+	automation.AutomationName = req.AutomationName
+	automation.InstanceServerID = req.InstanceServerID
+	automation.InstanceServerChannelID = req.InstanceServerChannelID
+	automation.LifecycleState = req.LifecycleState
+	automation.MisfirePolicy = req.MisfirePolicy
+
+	snapshot := &dto.AutomationSnapshot{
+		Automation: automation,
+	}
+
+	for i, g := range req.ConditionGroups {
+		// ใช้ลำดับใน request เป็น id ชั่วคราวเพื่อผูก condition เข้ากับ group
+		tempGroupID := strconv.Itoa(i)
+		snapshot.ConditionGroups = append(snapshot.ConditionGroups, &model.RunAutomationConditionGroup{
+			AutomationConditionGroupID: tempGroupID,
+			GroupOperator:              g.GroupOperator,
+			SortOrder:                  g.SortOrder,
+		})
+
+		for _, cond := range g.Conditions {
+			snapshot.Conditions = append(snapshot.Conditions, &model.RunAutomationCondition{
+				AutomationConditionGroupID: tempGroupID,
+				ConditionID:                cond.ConditionID,
+				OperatorID:                 cond.OperatorID,
+				Value:                      cond.Value,
+				UnitID:                     cond.UnitID,
+				ComparisonOperator:         cond.ComparisonOperator,
+			})
+		}
+	}
+
+	for _, a := range req.Actions {
+		snapshot.Actions = append(snapshot.Actions, &model.RunAutomationAction{
+			ActionID:          a.ActionID,
+			ConfigJSON:        a.ConfigJSON,
+			SortOrder:         a.SortOrder,
+			ExecutionMode:     a.ExecutionMode,
+			FanOutConcurrency: a.FanOutConcurrency,
+		})
+	}
+
+	for _, t := range req.Targets {
+		snapshot.Targets = append(snapshot.Targets, &model.RunAutomationTarget{
+			CompanyID:        t.CompanyID,
+			BranchID:         t.BranchID,
+			DepartmentID:     t.DepartmentID,
+			DivisionID:       t.DivisionID,
+			SectionID:        t.SectionID,
+			SectionLv01ID:    t.SectionLv01ID,
+			SectionLv02ID:    t.SectionLv02ID,
+			SectionLv03ID:    t.SectionLv03ID,
+			SectionLv04ID:    t.SectionLv04ID,
+			SectionLv05ID:    t.SectionLv05ID,
+			PositionID:       t.PositionID,
+			EmployeeTypeCode: t.EmployeeTypeCode,
+			EmployeeID:       t.EmployeeID,
+			TargetMode:       t.TargetMode,
+		})
+	}
+
+	return snapshot
+}
+
+// CreateAutomation godoc
+// @Summary      Create automation
+// @Description  สร้าง Automation พร้อม condition groups, conditions, actions และ targets ใน transaction เดียว
+// @Tags         run
+// @Accept       json
+// @Produce      json
+// @Param        body  body      api.AutomationRequest  true  "Automation document"
+// @Success      201   {object}  dto.AutomationSnapshot
+// @Failure      400   {object}  map[string]string
+// @Failure      422   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]string
+// @Router       /run/automations [post]
+// @Security BearerAuth
 func (h *RunHandler) CreateAutomation(c *gin.Context) {
-	// Implementation for creating an automation run
+	var req AutomationRequest
+
+	// 1. Bind + Validate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 2. Call service
+	snapshot, err := h.runService.CreateAutomation(c.Request.Context(), req.toSnapshot(), c.GetString("user_id"))
+	if err != nil {
+		respondRunError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, snapshot)
+}
+
+// ListAutomations godoc
+// @Summary      List automations
+// @Tags         run
+// @Produce      json
+// @Param        instance_server_id          query     string  false  "Instance server ID"
+// @Param        instance_server_channel_id  query     string  false  "Instance server channel ID"
+// @Param        frequency                   query     string  false  "Frequency"
+// @Param        status                      query     string  false  "Scheduler status"
+// @Param        is_active                   query     string  false  "Y or N"
+// @Param        lifecycle_state             query     string  false  "DRAFT, ACTIVE, PAUSED, ARCHIVED"
+// @Success      200  {array}   model.RunAutomation
+// @Failure      500  {object}  map[string]string
+// @Router       /run/automations [get]
+// @Security BearerAuth
+func (h *RunHandler) ListAutomations(c *gin.Context) {
+	filter := model.RunAutomation{
+		InstanceServerID:        c.Query("instance_server_id"),
+		InstanceServerChannelID: c.Query("instance_server_channel_id"),
+		Frequency:               c.Query("frequency"),
+		Status:                  c.Query("status"),
+		IsActive:                c.Query("is_active"),
+		LifecycleState:          c.Query("lifecycle_state"),
+	}
+
+	automations, err := h.runService.ListAutomations(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, automations)
+}
+
+// GetAutomation godoc
+// @Summary      Get automation
+// @Tags         run
+// @Produce      json
+// @Param        id   path      string  true  "Automation ID"
+// @Success      200  {object}  dto.AutomationSnapshot
+// @Failure      404  {object}  map[string]string
+// @Router       /run/automations/{id} [get]
+// @Security BearerAuth
+func (h *RunHandler) GetAutomation(c *gin.Context) {
+	snapshot, err := h.runService.GetAutomationSnapshot(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondRunError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// UpdateAutomation godoc
+// @Summary      Replace automation
+// @Description  แทนที่ Automation และรายการลูกทั้งหมดด้วยเอกสารใหม่
+// @Tags         run
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                 true  "Automation ID"
+// @Param        body  body      api.AutomationRequest  true  "Automation document"
+// @Success      200   {object}  dto.AutomationSnapshot
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Failure      422   {object}  map[string]interface{}
+// @Router       /run/automations/{id} [put]
+// @Security BearerAuth
+func (h *RunHandler) UpdateAutomation(c *gin.Context) {
+	var req AutomationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	snapshot, err := h.runService.ReplaceAutomation(c.Request.Context(), c.Param("id"), req.toSnapshot(), c.GetString("user_id"))
+	if err != nil {
+		respondRunError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// DeleteAutomation godoc
+// @Summary      Delete automation
+// @Tags         run
+// @Param        id   path  string  true  "Automation ID"
+// @Success      204
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /run/automations/{id} [delete]
+// @Security BearerAuth
+func (h *RunHandler) DeleteAutomation(c *gin.Context) {
+	if err := h.runService.DeleteAutomation(c.Request.Context(), c.Param("id")); err != nil {
+		respondRunError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ActivateAutomation godoc
+// @Summary      Activate draft automation
+// @Description  DRAFT → ACTIVE และคำนวณ next_run_time จากเวลาปัจจุบัน
+// @Tags         run
+// @Produce      json
+// @Param        id   path      string  true  "Automation ID"
+// @Success      200  {object}  model.RunAutomation
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /run/automations/{id}/activate [post]
+// @Security BearerAuth
+func (h *RunHandler) ActivateAutomation(c *gin.Context) {
+	h.transition(c, service.TransitionActivate)
+}
+
+// PauseAutomation godoc
+// @Summary      Pause automation
+// @Description  ACTIVE → PAUSED, scheduler จะไม่หยิบงานนี้จนกว่าจะ resume
+// @Tags         run
+// @Produce      json
+// @Param        id   path      string  true  "Automation ID"
+// @Success      200  {object}  model.RunAutomation
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /run/automations/{id}/pause [post]
+// @Security BearerAuth
+func (h *RunHandler) PauseAutomation(c *gin.Context) {
+	h.transition(c, service.TransitionPause)
+}
+
+// ResumeAutomation godoc
+// @Summary      Resume automation
+// @Description  PAUSED → ACTIVE และคำนวณ next_run_time ใหม่จากเวลาปัจจุบัน (ไม่ตามรอบที่พลาดไประหว่าง pause)
+// @Tags         run
+// @Produce      json
+// @Param        id   path      string  true  "Automation ID"
+// @Success      200  {object}  model.RunAutomation
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /run/automations/{id}/resume [post]
+// @Security BearerAuth
+func (h *RunHandler) ResumeAutomation(c *gin.Context) {
+	h.transition(c, service.TransitionResume)
+}
+
+// ArchiveAutomation godoc
+// @Summary      Archive automation
+// @Description  DRAFT/PAUSED → ARCHIVED เก็บไว้ดูย้อนหลัง แก้ไขหรือสั่งรันไม่ได้อีก (ต้อง pause ก่อนถ้ายัง ACTIVE)
+// @Tags         run
+// @Produce      json
+// @Param        id   path      string  true  "Automation ID"
+// @Success      200  {object}  model.RunAutomation
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /run/automations/{id}/archive [post]
+// @Security BearerAuth
+func (h *RunHandler) ArchiveAutomation(c *gin.Context) {
+	h.transition(c, service.TransitionArchive)
+}
+
+type SchedulePreviewResponse struct {
+	TimeZone  string      `json:"time_zone"`
+	FireTimes []time.Time `json:"fire_times"`
+}
+
+// GetSchedulePreview godoc
+// @Summary      Preview automation schedule
+// @Description  แสดงเวลาที่ automation จะรัน N รอบถัดไป (ตาม time zone ของ automation, รวม end date / max runs / blackout) โดยไม่แก้ไขข้อมูล
+// @Tags         run
+// @Produce      json
+// @Param        id     path      string  true   "Automation ID"
+// @Param        count  query     int     false  "จำนวนรอบ (default 10, max 100)"
+// @Success      200    {object}  api.SchedulePreviewResponse
+// @Failure      400    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Router       /run/automations/{id}/schedule-preview [get]
+// @Security BearerAuth
+func (h *RunHandler) GetSchedulePreview(c *gin.Context) {
+	count, err := parseCountQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	automation, err := h.runService.GetAutomationByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondRunError(c, err)
+		return
+	}
+
+	h.previewSchedule(c, automation, count)
+}
+
+// PreviewSchedule godoc
+// @Summary      Preview unsaved schedule
+// @Description  คำนวณเวลารัน N รอบถัดไปจาก schedule ที่ยังไม่ได้บันทึก ใช้ตรวจสอบก่อนสร้าง/แก้ไข automation
+// @Tags         run
+// @Accept       json
+// @Produce      json
+// @Param        count  query     int                  false  "จำนวนรอบ (default 10, max 100)"
+// @Param        body   body      api.ScheduleRequest  true   "Schedule"
+// @Success      200    {object}  api.SchedulePreviewResponse
+// @Failure      400    {object}  map[string]string
+// @Router       /run/schedule/preview [post]
+// @Security BearerAuth
+func (h *RunHandler) PreviewSchedule(c *gin.Context) {
+	count, err := parseCountQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.previewSchedule(c, req.toAutomation(), count)
+}
+
+func (h *RunHandler) previewSchedule(c *gin.Context, automation *model.RunAutomation, count int) {
+	fireTimes, err := h.runService.PreviewSchedule(c.Request.Context(), automation, count)
+	if err != nil {
+		respondRunError(c, err)
+		return
+	}
+
+	// validate ผ่านแล้ว location จึงโหลดได้เสมอ
+	loc, _ := service.AutomationLocation(automation)
+	c.JSON(http.StatusOK, SchedulePreviewResponse{
+		TimeZone:  loc.String(),
+		FireTimes: fireTimes,
+	})
+}
+
+type TargetPreviewResponse struct {
+	AutomationID string                `json:"automation_id"`
+	ResolvedAt   time.Time             `json:"resolved_at"`
+	Count        int                   `json:"count"`
+	Recipients   []directory.Recipient `json:"recipients"`
+}
+
+// GetTargetPreview godoc
+// @Summary      Preview automation recipients
+// @Description  ขยาย targets ของ automation (INCLUDE ลบ EXCLUDE, ตัดคนซ้ำ) เป็นรายชื่อพนักงานจาก directory ณ ตอนนี้
+// @Tags         run
+// @Produce      json
+// @Param        id   path      string  true  "Automation ID"
+// @Success      200  {object}  api.TargetPreviewResponse
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Failure      501  {object}  map[string]string
+// @Router       /run/automations/{id}/targets/preview [get]
+// @Security BearerAuth
+func (h *RunHandler) GetTargetPreview(c *gin.Context) {
+	recipients, err := h.targetService.PreviewTargets(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, directory.ErrNoProvider) {
+			// target ระดับหน่วยงานต้องใช้ DIRECTORY_PROVIDER
+			c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+			return
+		}
+		respondRunError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TargetPreviewResponse{
+		AutomationID: c.Param("id"),
+		ResolvedAt:   time.Now(),
+		Count:        len(recipients),
+		Recipients:   recipients,
+	})
+}
+
+func parseCountQuery(c *gin.Context) (int, error) {
+	value := c.Query("count")
+	if value == "" {
+		return 0, nil
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 {
+		return 0, errors.New("count must be a positive number")
+	}
+	return count, nil
+}
+
+type TriggerRequest struct {
+	DryRun bool `json:"dry_run"` // ประเมิน condition และสร้าง payload แต่ไม่เรียก action จริง (ดูสิ่งที่จะถูกส่งได้ที่ request_preview ของแต่ละ step)
+}
+
+type TriggerResponse struct {
+	LogID        string    `json:"log_id"`
+	AutomationID string    `json:"automation_id"`
+	TriggeredAt  time.Time `json:"triggered_at"`
+	DryRun       bool      `json:"dry_run"`
+}
+
+// TriggerAutomation godoc
+// @Summary      Run automation now
+// @Description  ส่ง message ให้ worker รัน automation ทันทีโดยไม่กระทบ schedule (next_run_time) ติดตามผลได้ที่ /logs/automation-executions/{log_id}
+// @Tags         run
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string              true   "Automation ID"
+// @Param        body  body      api.TriggerRequest  false  "Trigger options"
+// @Success      202   {object}  api.TriggerResponse
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Router       /run/automations/{id}/trigger [post]
+// @Security BearerAuth
+func (h *RunHandler) TriggerAutomation(c *gin.Context) {
+	var req TriggerRequest
+
+	// body เป็น optional
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	msg, err := h.triggerService.TriggerAutomation(c.Request.Context(), c.Param("id"), req.DryRun, c.GetString("user_id"))
+	if err != nil {
+		respondRunError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, TriggerResponse{
+		LogID:        msg.LogID,
+		AutomationID: msg.AutomationID,
+		TriggeredAt:  msg.TriggeredAt,
+		DryRun:       msg.DryRun,
+	})
+}
+
+func (h *RunHandler) transition(c *gin.Context, transition string) {
+	automation, err := h.runService.TransitionAutomation(c.Request.Context(), c.Param("id"), transition, c.GetString("user_id"))
+	if err != nil {
+		respondRunError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, automation)
+}
+
+func respondRunError(c *gin.Context, err error) {
+	var policyErr *service.PolicyViolationError
+
+	switch {
+	case errors.As(err, &policyErr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":      "automation violates policy rules",
+			"violations": policyErr.Violations,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "automation not found"})
+	case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrAutomationArchived), errors.Is(err, service.ErrLifecycleChanged),
+		errors.Is(err, service.ErrAutomationLocked):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidAutomation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
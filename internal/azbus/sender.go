@@ -1,53 +1,34 @@
 package azbus
 
 import (
+	"automation-engine/internal/broker"
 	"context"
 	"time"
-
-	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
 )
 
 type Sender struct {
-	sender *azservicebus.Sender
-	client *azservicebus.Client
+	broker broker.Broker
 	queue  string
 }
 
-func NewSender(ctx context.Context, client *azservicebus.Client, queueName string) (*Sender, error) {
-	s, err := client.NewSender(queueName, nil)
-	if err != nil {
-		return nil, err
-	}
-
+func NewSender(ctx context.Context, b broker.Broker, queueName string) (*Sender, error) {
 	return &Sender{
-		sender: s,
-		client: client,
+		broker: b,
 		queue:  queueName,
 	}, nil
 }
 
 // SendMessage sends a normal message (no scheduling)
 func (s *Sender) SendMessage(ctx context.Context, sessionID string, body []byte) error {
-	msg := &azservicebus.Message{
-		SessionID: &sessionID,
-		Body:      body,
-	}
-
-	return s.sender.SendMessage(ctx, msg, nil)
+	return s.broker.Send(ctx, s.queue, sessionID, body)
 }
 
 // ScheduleMessage sends a message scheduled for a future time
 func (s *Sender) ScheduleMessage(ctx context.Context, sessionID string, body []byte, runAt time.Time) error {
-	msg := &azservicebus.Message{
-		SessionID:            &sessionID,
-		Body:                 body,
-		ScheduledEnqueueTime: &runAt,
-	}
-
-	return s.sender.SendMessage(ctx, msg, nil)
+	return s.broker.Schedule(ctx, s.queue, sessionID, body, runAt)
 }
 
-// Close cleans up the sender
+// Close cleans up the sender. The broker is owned (and closed) by the caller.
 func (s *Sender) Close(ctx context.Context) error {
-	return s.sender.Close(ctx)
+	return nil
 }
@@ -1,26 +1,55 @@
 package azbus
 
 import (
+	"automation-engine/internal/broker"
+	"automation-engine/internal/callback"
+	"automation-engine/internal/directory"
 	"automation-engine/internal/domain/model"
 	"automation-engine/internal/dto"
+	"automation-engine/internal/evaluator"
 	"automation-engine/internal/httpclient"
 	"automation-engine/internal/service"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxStepResponseLength limits the response body stored per action step
+	maxStepResponseLength = 4000
 
-	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	// IdempotencyKeyHeader carries a key that stays the same for every delivery and retry of one action call,
+	// receivers can use it to ignore duplicates
+	IdempotencyKeyHeader = "Idempotency-Key"
 )
 
+// errClaimFailed means the execution could not be claimed, the message is abandoned without touching the log
+var errClaimFailed = errors.New("failed to claim execution")
+
+// errPolicyViolation means the automation no longer passes the governance policy, redelivering the message cannot help
+var errPolicyViolation = errors.New("automation violates policy")
+
 type SessionReceiverOptions struct {
 	SessionPool int
 	BatchSize   int
 	ProcessPool int
 	RetryDelay  int
+	// CallbackTimeout คือเวลาสูงสุด (นาที) ที่รอ callback จาก action แบบ async
+	CallbackTimeout int
+	// ClaimTimeout คือเวลา (นาที) ที่ worker จอง execution ไว้ ต้องนานกว่าเวลาเรียก action ทั้งหมดของ automation
+	ClaimTimeout int
+	// FanOutPool คือจำนวนผู้รับที่ action แบบ fan-out เรียกพร้อมกัน (เมื่อ action ไม่ได้กำหนด fan_out_concurrency)
+	FanOutPool int
 }
 
 type Option func(*SessionReceiverOptions)
@@ -28,21 +57,27 @@ type Option func(*SessionReceiverOptions)
 type SessionReceiver struct {
 	ctx               context.Context
 	wg                *sync.WaitGroup
-	client            *azservicebus.Client
+	broker            broker.Broker
 	queueName         string
 	runService        service.RunService
 	definitionService service.DefinitionService
 	logService        service.LogService
+	targetService     service.TargetService
+	conditionResolver evaluator.ValueResolver
+	callbackSigner    *callback.Signer
 	options           SessionReceiverOptions
 }
 
-func NewSessionReceiver(ctx context.Context, wg *sync.WaitGroup, client *azservicebus.Client, queueName string, runService service.RunService, definitionService service.DefinitionService, logService service.LogService, opts *SessionReceiverOptions) *SessionReceiver {
+func NewSessionReceiver(ctx context.Context, wg *sync.WaitGroup, b broker.Broker, queueName string, runService service.RunService, definitionService service.DefinitionService, logService service.LogService, targetService service.TargetService, conditionResolver evaluator.ValueResolver, callbackSigner *callback.Signer, opts *SessionReceiverOptions) *SessionReceiver {
 	// 1. กำหนดค่า Default
 	defaultOpts := SessionReceiverOptions{
-		SessionPool: 20,
-		BatchSize:   5,
-		ProcessPool: 1,
-		RetryDelay:  5,
+		SessionPool:     20,
+		BatchSize:       5,
+		ProcessPool:     1,
+		RetryDelay:      5,
+		CallbackTimeout: 60,
+		ClaimTimeout:    10,
+		FanOutPool:      5,
 	}
 
 	// 2. Apply options ที่กำหนดมา หากมี
@@ -59,17 +94,29 @@ func NewSessionReceiver(ctx context.Context, wg *sync.WaitGroup, client *azservi
 		if opts.RetryDelay > 0 {
 			defaultOpts.RetryDelay = opts.RetryDelay
 		}
+		if opts.CallbackTimeout > 0 {
+			defaultOpts.CallbackTimeout = opts.CallbackTimeout
+		}
+		if opts.ClaimTimeout > 0 {
+			defaultOpts.ClaimTimeout = opts.ClaimTimeout
+		}
+		if opts.FanOutPool > 0 {
+			defaultOpts.FanOutPool = opts.FanOutPool
+		}
 	}
 
 	// 3. สร้าง Struct โดยใช้ opts ที่ได้มา
 	return &SessionReceiver{
 		ctx:               ctx,
 		wg:                wg,
-		client:            client,
+		broker:            b,
 		queueName:         queueName,
 		runService:        runService,
 		definitionService: definitionService,
 		logService:        logService,
+		targetService:     targetService,
+		conditionResolver: conditionResolver,
+		callbackSigner:    callbackSigner,
 		options:           defaultOpts,
 	}
 }
@@ -96,8 +143,7 @@ func (sr *SessionReceiver) RunDispatcher() {
 		case workerNo := <-workerCH:
 			acceptSessionCtx, acceptSessionCancel := context.WithTimeout(sr.ctx, 5*time.Second)
 
-			// sessionReceiver, err := sr.client.AcceptSessionForQueue(acceptSessionCtx, sr.queueName, "202205072D33E7BA048F", nil)
-			sessionReceiver, err := sr.client.AcceptNextSessionForQueue(acceptSessionCtx, sr.queueName, nil)
+			sessionReceiver, err := sr.broker.AcceptNextSession(acceptSessionCtx, sr.queueName)
 			if err != nil {
 				acceptSessionCancel()
 
@@ -118,7 +164,7 @@ func (sr *SessionReceiver) RunDispatcher() {
 }
 
 // runSessionWorker processes messages from a single session
-func (sr *SessionReceiver) runSessionWorker(sessionReceiver *azservicebus.SessionReceiver, acceptSessionCancel context.CancelFunc, workerCH chan int, workerNo int) {
+func (sr *SessionReceiver) runSessionWorker(sessionReceiver broker.Session, acceptSessionCancel context.CancelFunc, workerCH chan int, workerNo int) {
 	defer sr.wg.Done()
 	defer func() { workerCH <- workerNo }()
 	defer acceptSessionCancel()
@@ -129,7 +175,7 @@ func (sr *SessionReceiver) runSessionWorker(sessionReceiver *azservicebus.Sessio
 
 	recvCtx, cancel := context.WithTimeout(sr.ctx, 10*time.Second)
 
-	msgs, err := sessionReceiver.ReceiveMessages(recvCtx, sr.options.BatchSize, nil)
+	msgs, err := sessionReceiver.ReceiveMessages(recvCtx, sr.options.BatchSize)
 	cancel()
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
@@ -160,30 +206,87 @@ func (sr *SessionReceiver) runSessionWorker(sessionReceiver *azservicebus.Sessio
 }
 
 // runMessageWorker handles a single message, applies business logic and updates Redis
-func (sr *SessionReceiver) runMessageWorker(sem chan struct{}, wg *sync.WaitGroup, sessionReceiver *azservicebus.SessionReceiver, msg *azservicebus.ReceivedMessage) {
+func (sr *SessionReceiver) runMessageWorker(sem chan struct{}, wg *sync.WaitGroup, sessionReceiver broker.Session, msg *broker.Message) {
 	defer wg.Done()
 	defer func() { <-sem }()
 
 	log, err := sr.handleMessage(msg)
 
-	if err != nil {
-		// Log: Failed/Abandon
+	// message ซ้ำ: execution นี้จบไปแล้ว หรือ worker อื่นกำลังทำอยู่
+	var duplicate *duplicateMessage
+	if errors.As(err, &duplicate) {
+		sr.handleDuplicate(sessionReceiver, msg, duplicate)
+		return
+	}
+	if errors.Is(err, errClaimFailed) {
+		fmt.Println(err)
+		sessionReceiver.AbandonMessage(sr.ctx, msg)
+		return
+	}
+
+	// message อ่านไม่ได้ ส่งซ้ำกี่ครั้งก็ไม่สำเร็จ
+	if log == nil {
 		fmt.Println(err)
+		if msg != nil {
+			sessionReceiver.DeadLetterMessage(sr.ctx, msg, "InvalidMessage", err.Error())
+		}
+		return
+	}
 
+	if err != nil {
+		fmt.Println(err)
 		log.ErrorMessage = err.Error()
+
+		// ผิด policy: ส่งซ้ำก็ผิดเหมือนเดิม จบ execution ทันที (แก้ policy แล้ว replay จาก dead-letter ได้)
+		if errors.Is(err, errPolicyViolation) {
+			log.Status = "FAILED"
+			log.FinishedAt = time.Now()
+			if log.IsDryRun {
+				sr.logService.Upsert(sr.ctx, log)
+				sessionReceiver.CompleteMessage(sr.ctx, msg)
+				return
+			}
+			sr.deadLetter(sessionReceiver, msg, log, "PolicyViolation")
+			return
+		}
+
+		// Action ล้มเหลว: retry ตาม policy ของ action หรือส่งเข้า dead-letter
+		var failure *actionFailure
+		if errors.As(err, &failure) {
+			sr.handleActionFailure(sessionReceiver, msg, log, failure)
+			return
+		}
+
+		// ส่งซ้ำครบจำนวนแล้ว dead-letter เองเพื่อให้เห็นใน log (ไม่งั้น broker จะย้ายไปเงียบๆ)
+		if msg.DeliveryCount >= broker.DefaultMaxDeliveryCount {
+			log.Status = "FAILED"
+			log.FinishedAt = time.Now()
+			sr.deadLetter(sessionReceiver, msg, log, "MaxDeliveryCountExceeded")
+			return
+		}
+
+		// Log: Failed/Abandon
 		sr.logService.Upsert(sr.ctx, log)
-		sessionReceiver.AbandonMessage(sr.ctx, msg, nil)
+		sessionReceiver.AbandonMessage(sr.ctx, msg)
 
 		return
 	}
 
-	// Log: Success/Complete
+	// Log: Success/Complete (log ไม่มี claimed_until เป็นการปล่อย claim)
 	sr.logService.Upsert(sr.ctx, log)
-	sessionReceiver.CompleteMessage(sr.ctx, msg, nil)
+
+	// callback ของ action แบบ async ที่มาระหว่างที่ worker ถือ claim อยู่ยังไม่ได้ปิด execution
+	if log.Status == "RUNNING" {
+		if err := sr.logService.SettleExecution(sr.ctx, log.LogID); err != nil {
+			fmt.Println("failed to settle execution:", err)
+		}
+	}
+
+	sessionReceiver.CompleteMessage(sr.ctx, msg)
 }
 
 // handleMessage contains the actual business logic for processing a single message
-func (sr *SessionReceiver) handleMessage(msg *azservicebus.ReceivedMessage) (*model.LogAutomationExecution, error) {
+func (sr *SessionReceiver) handleMessage(msg *broker.Message) (*model.LogAutomationExecution, error) {
 	// Check for nil message early
 	if msg == nil {
 		return nil, fmt.Errorf("received nil message")
@@ -201,6 +304,27 @@ func (sr *SessionReceiver) handleMessage(msg *azservicebus.ReceivedMessage) (*mo
 		LogID:        body.LogID,
 		AutomationID: body.AutomationID,
 		TriggeredAt:  body.TriggeredAt,
+		ScheduledFor: body.ScheduledFor,
+		IsCatchUp:    body.IsCatchUp,
+		TriggerType:  body.TriggerType,
+		TriggeredBy:  body.TriggeredBy,
+		IsDryRun:     body.DryRun,
+		Attempt:      body.CurrentAttempt(),
+		// replay ของ execution ที่เข้า dead-letter
+		ReplayOfLogID: body.ReplayOfLogID,
+	}
+	if log.TriggerType == "" {
+		log.TriggerType = dto.TriggerSchedule
+	}
+
+	// จอง LogID ก่อนเรียก action เพื่อไม่ให้ message ที่ถูกส่งซ้ำเรียก action ซ้ำ
+	claimedUntil := time.Now().Add(time.Duration(sr.options.ClaimTimeout) * time.Minute)
+	claim, err := sr.logService.ClaimExecution(sr.ctx, &log, claimedUntil)
+	if err != nil {
+		return &log, fmt.Errorf("%w %s: %v", errClaimFailed, body.LogID, err)
+	}
+	if !claim.Claimed {
+		return &log, &duplicateMessage{logID: body.LogID, busyUntil: claim.BusyUntil}
 	}
 
 	// Fetch automation snapshot
@@ -210,6 +334,28 @@ func (sr *SessionReceiver) handleMessage(msg *azservicebus.ReceivedMessage) (*mo
 		return &log, fmt.Errorf("failed to get automation snapshot by ID: %w", err)
 	}
 
+	snapshotBody, err := json.Marshal(snapshot)
+	if err != nil {
+		log.Status = "FAILED"
+		return &log, err
+	}
+
+	log.ConfigSnapshot = string(snapshotBody)
+
+	// รอบตาม schedule ที่ค้างอยู่ใน queue ตอน automation ถูก pause/archive จะไม่ถูกรัน
+	if log.TriggerType == dto.TriggerSchedule && snapshot.Automation.LifecycleState != service.StateActive {
+		log.Status = "SKIPPED"
+		log.ErrorMessage = fmt.Sprintf("automation is %s", snapshot.Automation.LifecycleState)
+		log.FinishedAt = time.Now()
+		return &log, nil
+	}
+
+	// Re-check governance rules in case policy changed after the automation was saved
+	if err := sr.runService.ValidatePolicy(sr.ctx, snapshot); err != nil {
+		log.Status = "FAILED"
+		return &log, fmt.Errorf("%w: %w", errPolicyViolation, err)
+	}
+
 	actionIDs := make([]string, 0)
 	for _, action := range snapshot.Actions {
 		actionIDs = append(actionIDs, action.ActionID)
@@ -221,25 +367,151 @@ func (sr *SessionReceiver) handleMessage(msg *azservicebus.ReceivedMessage) (*mo
 		return &log, err
 	}
 
-	snapshotBody, err := json.Marshal(snapshot)
+	defActions := make(map[string]*model.DefAction, len(actions))
+	for _, action := range actions {
+		defActions[action.ActionID] = action
+	}
+
+	// DefAction เดียวกันอาจถูกใช้หลายครั้ง (config ต่างกัน) จึงวนตาม run_automation_actions เรียงตาม SortOrder
+	automationActions := make([]*model.RunAutomationAction, len(snapshot.Actions))
+	copy(automationActions, snapshot.Actions)
+	sort.SliceStable(automationActions, func(i, j int) bool {
+		if automationActions[i].SortOrder != automationActions[j].SortOrder {
+			return automationActions[i].SortOrder < automationActions[j].SortOrder
+		}
+		return automationActions[i].AutomationActionID < automationActions[j].AutomationActionID
+	})
+
+	hasSingle, hasFanOut := false, false
+	for _, automationAction := range automationActions {
+		if automationAction.ExecutionMode == service.ExecutionModeFanOut {
+			hasFanOut = true
+		} else {
+			hasSingle = true
+		}
+	}
+
+	// Evaluate condition groups before invoking any action
+	operators, err := sr.definitionService.ListOperators(sr.ctx)
 	if err != nil {
 		log.Status = "FAILED"
-		return &log, err
+		return &log, fmt.Errorf("failed to list operators: %w", err)
 	}
+	conditions := evaluator.New(operators, sr.conditionResolver)
 
-	log.ConfigSnapshot = string(snapshotBody)
+	// action แบบ fan-out ประเมินเงื่อนไขรายคน, ระดับ automation ใช้กับ action แบบ SINGLE เท่านั้น
+	matched := false
+	if hasSingle {
+		matched, err = conditions.Evaluate(sr.ctx, snapshot)
+		if err != nil {
+			log.Status = "FAILED"
+			return &log, fmt.Errorf("failed to evaluate conditions: %w", err)
+		}
+	}
 
-	for _, action := range actions {
-		statusCode, response, err := httpclient.PostRequest(action.InvokeURL, snapshotBody)
+	if !matched && !hasFanOut {
+		log.Status = "SKIPPED"
+		log.FinishedAt = time.Now()
+		return &log, nil
+	}
+
+	// ขยาย targets เป็นรายชื่อพนักงาน (ไม่มี directory ก็ส่งแค่ targets เหมือนเดิม)
+	recipients, err := sr.targetService.ResolveTargets(sr.ctx, snapshot.Targets)
+	if err != nil && !errors.Is(err, directory.ErrNoProvider) {
+		log.Status = "FAILED"
+		return &log, fmt.Errorf("failed to resolve targets: %w", err)
+	}
+
+	actionCtx := dto.ActionContext{
+		LogID:       body.LogID,
+		Automation:  snapshot.Automation,
+		Targets:     snapshot.Targets,
+		Recipients:  recipients,
+		TriggeredAt: body.TriggeredAt,
+		Outputs:     map[string]interface{}{},
+	}
+
+	// รอบ retry หรือ message ที่ส่งซ้ำ: ข้าม action ที่สำเร็จ (หรือรอ callback อยู่) จากรอบก่อน
+	completed := map[string]string{}
+	if claim.Resumed {
+		steps, err := sr.logService.ListSteps(sr.ctx, body.LogID)
 		if err != nil {
 			log.Status = "FAILED"
-			return &log, err
-		} else if statusCode != 200 {
-			respBytes, _ := json.Marshal(response)
+			return &log, fmt.Errorf("failed to list previous steps: %w", err)
+		}
+		for _, step := range steps {
+			if step.Status == "SUCCESS" || step.Status == "RUNNING" || step.Status == "SKIPPED" {
+				completed[service.StepKey(step.AutomationActionID, step.EmployeeID)] = step.Status
+			}
+		}
+		// action ที่ข้ามไปยังต้องส่ง output ให้ action ถัดไปเหมือนรอบแรก
+		actionCtx.Outputs = service.StepOutputs(steps)
+	}
+
+	running, ran := false, false
+	for _, automationAction := range automationActions {
+		fanOut := automationAction.ExecutionMode == service.ExecutionModeFanOut
+		if !fanOut && !matched {
+			continue
+		}
+		if status, ok := completed[service.StepKey(automationAction.AutomationActionID, "")]; ok {
+			running = running || status == "RUNNING"
+			ran = true
+			continue
+		}
+
+		action, ok := defActions[automationAction.ActionID]
+		if !ok {
+			log.Status = "FAILED"
+			return &log, fmt.Errorf("action %s not found", automationAction.ActionID)
+		}
+
+		if fanOut {
+			result, err := sr.fanOut(&body, snapshot, conditions, automationAction, action, actionCtx, completed)
+			running = running || result.pending
+			ran = ran || result.ran
+			if err != nil {
+				sr.rollUpTargets(&log)
+				log.Status = "FAILED"
+				return &log, err
+			}
+			continue
+		}
 
+		payload, err := dto.BuildActionPayload(action, automationAction, actionCtx)
+		if err != nil {
 			log.Status = "FAILED"
-			return &log, fmt.Errorf(string(respBytes))
+			return &log, fmt.Errorf("invalid config of automation action %s: %w", automationAction.AutomationActionID, err)
 		}
+
+		pending, output, err := sr.invokeAction(&body, automationAction, action, payload, "", actionCtx.Outputs)
+		if err != nil {
+			log.Status = "FAILED"
+			return &log, err
+		}
+		running = running || pending
+		// response ของ action นี้ให้ template ของ action ถัดไปอ่านผ่าน .Outputs
+		if output != nil {
+			actionCtx.Outputs[automationAction.AutomationActionID] = output
+		}
+		ran = true
+	}
+
+	if hasFanOut {
+		sr.rollUpTargets(&log)
+	}
+
+	// เงื่อนไขไม่ผ่านทั้งระดับ automation และทุกผู้รับของ action แบบ fan-out
+	if !ran {
+		log.Status = "SKIPPED"
+		log.FinishedAt = time.Now()
+		return &log, nil
+	}
+
+	// มี action แบบ async ที่ยังรอ callback อยู่
+	if running {
+		log.Status = "RUNNING"
+		return &log, nil
 	}
 
 	// Successfully processed the message
@@ -247,3 +519,466 @@ func (sr *SessionReceiver) handleMessage(msg *azservicebus.ReceivedMessage) (*mo
 	log.FinishedAt = time.Now()
 	return &log, nil
 }
+
+// fanOutResult summarises the recipients of one FAN_OUT action
+type fanOutResult struct {
+	// pending is true when an async call is waiting for its callback
+	pending bool
+	// ran is true when the action was called (or already called in an earlier attempt) for at least one recipient
+	ran bool
+}
+
+// fanOut calls a FAN_OUT action once per resolved recipient, at most FanOutConcurrency (or FanOutPool) calls at a time.
+// Each recipient is evaluated against the automation conditions first, a recipient that does not match gets a SKIPPED step.
+// A failed recipient does not stop the others, the failures are returned together as one *actionFailure.
+func (sr *SessionReceiver) fanOut(msg *dto.MessageServiceBus, snapshot *dto.AutomationSnapshot, conditions *evaluator.Evaluator, automationAction *model.RunAutomationAction, action *model.DefAction, actionCtx dto.ActionContext, completed map[string]string) (fanOutResult, error) {
+	var result fanOutResult
+	if actionCtx.Recipients == nil {
+		// ไม่มี directory ขยาย targets ไม่ได้ retry ไปก็ไม่หาย
+		return result, &actionFailure{action: action, fanOut: true, err: fmt.Errorf("fan-out action %s requires DIRECTORY_PROVIDER: %w", automationAction.AutomationActionID, directory.ErrNoProvider)}
+	}
+
+	concurrency := int(automationAction.FanOutConcurrency)
+	if concurrency <= 0 {
+		concurrency = sr.options.FanOutPool
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failures []*actionFailure
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for _, recipient := range actionCtx.Recipients {
+		if status, ok := completed[service.StepKey(automationAction.AutomationActionID, recipient.EmployeeID)]; ok {
+			mu.Lock()
+			result.pending = result.pending || status == "RUNNING"
+			result.ran = result.ran || status != "SKIPPED"
+			mu.Unlock()
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(recipient directory.Recipient) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			pending, ran, err := sr.invokeForRecipient(msg, snapshot, conditions, automationAction, action, actionCtx, recipient)
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.pending = result.pending || pending
+			result.ran = result.ran || ran
+			var failure *actionFailure
+			if errors.As(err, &failure) {
+				failures = append(failures, failure)
+			}
+		}(recipient)
+	}
+	wg.Wait()
+
+	if len(failures) == 0 {
+		return result, nil
+	}
+
+	// retry ได้ถ้ามีผู้รับอย่างน้อยหนึ่งคนที่ retry ได้ (รอบถัดไปเรียกเฉพาะคนที่ยังไม่สำเร็จ)
+	retryable := false
+	for _, failure := range failures {
+		_, ok := failure.policy()
+		retryable = retryable || ok
+	}
+	return result, &actionFailure{
+		action:    action,
+		retryable: retryable,
+		fanOut:    true,
+		err:       fmt.Errorf("action %s failed for %d of %d recipients: %w", action.ActionID, len(failures), len(actionCtx.Recipients), failures[0].err),
+	}
+}
+
+// invokeForRecipient evaluates the conditions for one recipient and calls the action with the recipient in the payload.
+// ran is false when the recipient did not match the conditions.
+func (sr *SessionReceiver) invokeForRecipient(msg *dto.MessageServiceBus, snapshot *dto.AutomationSnapshot, conditions *evaluator.Evaluator, automationAction *model.RunAutomationAction, action *model.DefAction, actionCtx dto.ActionContext, recipient directory.Recipient) (pending bool, ran bool, err error) {
+	matched, err := conditions.EvaluateFor(sr.ctx, snapshot, &recipient)
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate conditions for %s: %w", recipient.EmployeeID, err)
+		sr.recordRecipientStep(msg, automationAction, action, recipient.EmployeeID, "FAILED", err.Error())
+		// provider อาจล่มชั่วคราว
+		return false, false, &actionFailure{action: action, retryable: true, err: err}
+	}
+	if !matched {
+		sr.recordRecipientStep(msg, automationAction, action, recipient.EmployeeID, "SKIPPED", "")
+		return false, false, nil
+	}
+
+	targetCtx := actionCtx
+	targetCtx.Recipients = nil
+	targetCtx.Recipient = &recipient
+	payload, err := dto.BuildActionPayload(action, automationAction, targetCtx)
+	if err != nil {
+		err = fmt.Errorf("invalid config of automation action %s: %w", automationAction.AutomationActionID, err)
+		sr.recordRecipientStep(msg, automationAction, action, recipient.EmployeeID, "FAILED", err.Error())
+		return false, false, &actionFailure{action: action, err: err}
+	}
+
+	pending, _, err = sr.invokeAction(msg, automationAction, action, payload, recipient.EmployeeID, targetCtx.Outputs)
+	return pending, true, err
+}
+
+// recordRecipientStep stores a fan-out step that did not call the action (conditions not matched or failed before the call)
+func (sr *SessionReceiver) recordRecipientStep(msg *dto.MessageServiceBus, automationAction *model.RunAutomationAction, action *model.DefAction, employeeID string, status string, errorMessage string) {
+	now := time.Now()
+	step := &model.LogAutomationActionExecution{
+		StepID:             sr.logService.GenerateStepID(),
+		LogID:              msg.LogID,
+		AutomationActionID: automationAction.AutomationActionID,
+		ActionID:           action.ActionID,
+		EmployeeID:         employeeID,
+		SortOrder:          automationAction.SortOrder,
+		Attempt:            msg.CurrentAttempt(),
+		Status:             status,
+		ErrorMessage:       errorMessage,
+		StartedAt:          now,
+		FinishedAt:         now,
+	}
+	if err := sr.logService.RecordStep(sr.ctx, step); err != nil {
+		fmt.Println("failed to record action step:", err)
+	}
+}
+
+// rollUpTargets copies the per-recipient counts of the fan-out steps onto the execution log
+func (sr *SessionReceiver) rollUpTargets(log *model.LogAutomationExecution) {
+	steps, err := sr.logService.ListSteps(sr.ctx, log.LogID)
+	if err != nil {
+		fmt.Println("failed to list steps:", err)
+		return
+	}
+	service.RollUpTargets(log, steps)
+}
+
+// invokeAction calls a single DefAction and records the step in log_automation_action_executions.
+// pending is true when an async action accepted the request and will report back through the callback endpoint.
+// A dry run records the request that would have been sent (status DRY_RUN) instead of calling the action.
+// employeeID is the recipient of a fan-out call, empty for a SINGLE action. A failed call is returned as *actionFailure.
+// outputs is the execution context the payload was built from, it is stored on the step (context_json).
+// output is the decoded JSON response of a successful synchronous call, also stored on the step (output_json).
+func (sr *SessionReceiver) invokeAction(msg *dto.MessageServiceBus, automationAction *model.RunAutomationAction, action *model.DefAction, body []byte, employeeID string, outputs map[string]interface{}) (pending bool, output map[string]interface{}, err error) {
+	hash := sha256.Sum256(body)
+	step := &model.LogAutomationActionExecution{
+		StepID:             sr.logService.GenerateStepID(),
+		LogID:              msg.LogID,
+		AutomationActionID: automationAction.AutomationActionID,
+		ActionID:           action.ActionID,
+		EmployeeID:         employeeID,
+		SortOrder:          automationAction.SortOrder,
+		Attempt:            msg.CurrentAttempt(),
+		RequestHash:        hex.EncodeToString(hash[:]),
+		StartedAt:          time.Now(),
+	}
+	if len(outputs) > 0 {
+		contextJSON, _ := json.Marshal(outputs)
+		step.ContextJSON = string(contextJSON)
+	}
+
+	async := strings.EqualFold(action.InvokeType, "async")
+
+	var resp *httpclient.Response
+	req, err := buildActionRequest(action, body)
+	if err == nil {
+		req.WithHeader(IdempotencyKeyHeader, idempotencyKey(msg.LogID, automationAction.AutomationActionID, employeeID))
+	}
+	if err == nil && async {
+		if sr.callbackSigner.Enabled() {
+			req.WithHeader(callback.URLHeader, sr.callbackSigner.URL(step.StepID))
+			req.WithHeader(callback.TokenHeader, sr.callbackSigner.Token(step.StepID))
+		} else {
+			err = errors.New("async action requires CALLBACK_SECRET and CALLBACK_BASE_URL")
+		}
+	}
+	if err == nil && msg.DryRun {
+		if err := sr.recordDryRun(step, req); err != nil {
+			return false, nil, &actionFailure{action: action, err: err}
+		}
+		return false, nil, nil
+	}
+
+	// action แบบ async: บันทึก step เป็น RUNNING ก่อนส่ง เพราะปลายทางอาจ callback กลับมาก่อนตอบ 202
+	dispatched := false
+	if err == nil && async {
+		step.Status = "RUNNING"
+		step.DeadlineAt = step.StartedAt.Add(time.Duration(sr.options.CallbackTimeout) * time.Minute)
+		step.FinishedAt = step.StartedAt
+		if err = sr.logService.RecordStep(sr.ctx, step); err != nil {
+			err = fmt.Errorf("failed to record action step: %w", err)
+		} else {
+			dispatched = true
+		}
+	}
+
+	// error ก่อนส่ง (เช่น config ผิด) retry ไปก็ไม่หาย, error หลังส่ง (network/timeout) retry ได้
+	sent := false
+	if err == nil {
+		sent = true
+		resp, err = req.Do(sr.ctx)
+	}
+
+	step.FinishedAt = time.Now()
+	step.LatencyMs = step.FinishedAt.Sub(step.StartedAt).Milliseconds()
+	if resp != nil {
+		step.HTTPStatus = int32(resp.StatusCode)
+		step.ResponseBody = truncate(string(resp.Body), maxStepResponseLength)
+	}
+
+	var failure *actionFailure
+	switch {
+	case err != nil:
+		step.Status = "FAILED"
+		step.ErrorMessage = err.Error()
+		failure = &actionFailure{action: action, retryable: sent, err: err}
+	case !resp.IsSuccess():
+		step.Status = "FAILED"
+		step.ErrorMessage = fmt.Sprintf("action %s responded with status %d: %s", action.ActionID, resp.StatusCode, step.ResponseBody)
+		failure = &actionFailure{action: action, retryable: true, statusCode: resp.StatusCode, err: errors.New(step.ErrorMessage)}
+	case async:
+		// ปลายทางรับงานแล้ว รอ callback มาปิด step (หรือ sweeper ตัดเมื่อเลย deadline)
+		step.Status = "RUNNING"
+		pending = true
+	default:
+		step.Status = "SUCCESS"
+		output = resp.Data
+		if output != nil {
+			// เก็บเต็ม (ไม่ตัดแบบ response_body) เพราะใช้สร้าง context ใหม่ตอน retry
+			outputJSON, _ := json.Marshal(output)
+			step.OutputJSON = string(outputJSON)
+		}
+	}
+
+	if dispatched {
+		completed, recordErr := sr.logService.FinishDispatch(sr.ctx, step)
+		if recordErr != nil {
+			fmt.Println("failed to record action step:", recordErr)
+		} else if completed {
+			// callback ปิด step ไปแล้ว ให้ SettleExecution สรุปผลจาก step แทนผลของ HTTP call
+			return true, nil, nil
+		}
+	} else if recordErr := sr.logService.RecordStep(sr.ctx, step); recordErr != nil {
+		fmt.Println("failed to record action step:", recordErr)
+	}
+
+	if failure != nil {
+		return false, nil, failure
+	}
+	return pending, output, nil
+}
+
+// idempotencyKey is the same for every attempt of one automation action (and fan-out recipient) within an execution.
+// A replay gets a new LogID and therefore new keys.
+func idempotencyKey(logID string, automationActionID string, employeeID string) string {
+	key := logID + "-" + automationActionID
+	if employeeID != "" {
+		key += "-" + employeeID
+	}
+	return key
+}
+
+// duplicateMessage is returned by handleMessage when the LogID was already claimed by another delivery
+type duplicateMessage struct {
+	logID string
+	// busyUntil is set while another worker still holds the execution
+	busyUntil time.Time
+}
+
+func (d *duplicateMessage) Error() string {
+	if d.busyUntil.IsZero() {
+		return fmt.Sprintf("execution %s has already been processed", d.logID)
+	}
+	return fmt.Sprintf("execution %s is being processed until %s", d.logID, d.busyUntil.UTC().Format(time.RFC3339))
+}
+
+// handleDuplicate completes a duplicate delivery. When another worker still holds the execution a copy is scheduled for
+// the end of its claim, so the execution is picked up again if that worker died.
+func (sr *SessionReceiver) handleDuplicate(sessionReceiver broker.Session, msg *broker.Message, duplicate *duplicateMessage) {
+	log.Printf("[%s] Duplicate message: %v", sr.queueName, duplicate)
+
+	if !duplicate.busyUntil.IsZero() {
+		if err := sr.broker.Schedule(sr.ctx, sr.queueName, msg.SessionID, msg.Body, duplicate.busyUntil); err != nil {
+			fmt.Println("failed to schedule duplicate message:", err)
+			sessionReceiver.AbandonMessage(sr.ctx, msg)
+			return
+		}
+	}
+
+	sessionReceiver.CompleteMessage(sr.ctx, msg)
+}
+
+// actionFailure is a failed action call. retryable is narrowed by the action's retry policy in policy().
+type actionFailure struct {
+	action     *model.DefAction
+	retryable  bool
+	statusCode int
+	// fanOut is set when the failure is the combined result of a FAN_OUT action's recipients
+	fanOut bool
+	err    error
+}
+
+func (f *actionFailure) Error() string { return f.err.Error() }
+
+func (f *actionFailure) Unwrap() error { return f.err }
+
+// policy returns the retry policy of the failed action and whether this failure may be retried under it
+func (f *actionFailure) policy() (service.RetryPolicy, bool) {
+	policy, err := service.ActionRetryPolicy(f.action)
+	if err != nil {
+		fmt.Println(err)
+		return service.RetryPolicy{MaxAttempts: 1}, false
+	}
+
+	retryable := f.retryable
+	if retryable && f.statusCode != 0 {
+		retryable = policy.IsRetryableStatus(f.statusCode)
+	}
+	return policy, retryable
+}
+
+// handleActionFailure re-schedules the message with exponential backoff while the failed action's retry policy allows,
+// otherwise the execution is FAILED and the message is dead-lettered
+func (sr *SessionReceiver) handleActionFailure(sessionReceiver broker.Session, msg *broker.Message, log *model.LogAutomationExecution, failure *actionFailure) {
+	// dry run ไม่ต้อง retry และไม่ต้องเก็บใน dead-letter
+	if log.IsDryRun {
+		log.Status = "FAILED"
+		log.FinishedAt = time.Now()
+		sr.logService.Upsert(sr.ctx, log)
+		sessionReceiver.CompleteMessage(sr.ctx, msg)
+		return
+	}
+
+	policy, retryable := failure.policy()
+	if retryable && policy.CanRetry(log.Attempt) {
+		retryAt := time.Now().Add(policy.Delay(log.Attempt))
+		if err := sr.scheduleRetry(msg, log.Attempt+1, retryAt); err != nil {
+			// ตั้งเวลา retry ไม่ได้ ปล่อยให้ broker ส่งซ้ำแทน
+			fmt.Println("failed to schedule retry:", err)
+			sr.logService.Upsert(sr.ctx, log)
+			sessionReceiver.AbandonMessage(sr.ctx, msg)
+			return
+		}
+
+		log.Status = "RETRYING"
+		log.ErrorMessage = fmt.Sprintf("attempt %d/%d failed, retry at %s: %v", log.Attempt, policy.MaxAttempts, retryAt.UTC().Format(time.RFC3339), failure)
+		sr.logService.Upsert(sr.ctx, log)
+		sessionReceiver.CompleteMessage(sr.ctx, msg)
+		return
+	}
+
+	reason := "ActionFailed"
+	if retryable {
+		reason = "RetryExhausted"
+	}
+	log.Status = "FAILED"
+	log.FinishedAt = time.Now()
+	log.ErrorMessage = fmt.Sprintf("attempt %d/%d failed: %v", log.Attempt, policy.MaxAttempts, failure)
+
+	// fan-out ที่สำเร็จบางคน: จบเป็น PARTIAL ไม่เข้า dead-letter (replay จะส่งซ้ำให้คนที่สำเร็จไปแล้ว)
+	if failure.fanOut && log.TargetSucceeded > 0 {
+		log.Status = service.StatusPartial
+		sr.logService.Upsert(sr.ctx, log)
+		sessionReceiver.CompleteMessage(sr.ctx, msg)
+		return
+	}
+
+	sr.deadLetter(sessionReceiver, msg, log, reason)
+}
+
+// deadLetter moves the message to the dead-letter queue and keeps the reason and the original message on the execution log,
+// so it can be listed and replayed through the logs API
+func (sr *SessionReceiver) deadLetter(sessionReceiver broker.Session, msg *broker.Message, log *model.LogAutomationExecution, reason string) {
+	log.DeadLetterReason = reason
+	log.DeadLetteredAt = time.Now()
+	log.MessagePayload = string(msg.Body)
+	sr.logService.Upsert(sr.ctx, log)
+
+	if err := sessionReceiver.DeadLetterMessage(sr.ctx, msg, reason, truncate(log.ErrorMessage, maxStepResponseLength)); err != nil {
+		fmt.Println("failed to dead-letter message:", err)
+	}
+}
+
+// scheduleRetry sends a copy of the message with the next attempt number, visible at retryAt
+func (sr *SessionReceiver) scheduleRetry(msg *broker.Message, attempt int32, retryAt time.Time) error {
+	var body dto.MessageServiceBus
+	if err := json.Unmarshal(msg.Body, &body); err != nil {
+		return err
+	}
+	body.Attempt = attempt
+
+	retryBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return sr.broker.Schedule(sr.ctx, sr.queueName, msg.SessionID, retryBody, retryAt)
+}
+
+// recordDryRun stores what the action request would have sent
+func (sr *SessionReceiver) recordDryRun(step *model.LogAutomationActionExecution, req *httpclient.Request) error {
+	preview, err := req.Preview()
+	if err != nil {
+		step.Status = "FAILED"
+		step.ErrorMessage = err.Error()
+	} else {
+		previewBody, _ := json.Marshal(preview)
+		step.Status = "DRY_RUN"
+		step.RequestPreview = truncate(string(previewBody), maxStepResponseLength)
+		log.Printf("[%s] Dry run: LogID=%s | ActionID=%s | %s %s", sr.queueName, step.LogID, step.ActionID, preview.Method, preview.URL)
+	}
+	step.FinishedAt = time.Now()
+
+	if recordErr := sr.logService.RecordStep(sr.ctx, step); recordErr != nil {
+		fmt.Println("failed to record action step:", recordErr)
+	}
+	return err
+}
+
+// buildActionRequest maps a DefAction onto an HTTP request.
+// GET sends the snapshot as query string, other methods send it as JSON body.
+func buildActionRequest(action *model.DefAction, body []byte) (*httpclient.Request, error) {
+	method := strings.ToUpper(action.InvokeMethod)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req := httpclient.NewRequest(method, action.InvokeURL)
+	if method == http.MethodGet {
+		query, err := httpclient.EncodeQuery(body)
+		if err != nil {
+			return nil, err
+		}
+		req.WithQuery(query)
+	} else {
+		req.WithJSONBody(body)
+	}
+
+	if action.InvokeHeaders != "" {
+		var headers map[string]string
+		if err := json.Unmarshal([]byte(action.InvokeHeaders), &headers); err != nil {
+			return nil, fmt.Errorf("invalid invoke_headers of action %s: %w", action.ActionID, err)
+		}
+		req.WithHeaders(headers)
+	}
+
+	if action.InvokeTimeout > 0 {
+		req.WithTimeout(time.Duration(action.InvokeTimeout) * time.Second)
+	}
+
+	return req, nil
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	// อย่าตัดกลางตัวอักษร UTF-8 (เช่น ภาษาไทย)
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
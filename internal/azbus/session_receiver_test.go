@@ -0,0 +1,19 @@
+package azbus
+
+import "testing"
+
+func TestIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		logID, actionID, employeeID string
+		want                        string
+	}{
+		{"LOG001", "AA001", "", "LOG001-AA001"},
+		{"LOG001", "AA001", "E001", "LOG001-AA001-E001"},
+		{"LOG002", "AA001", "", "LOG002-AA001"},
+	}
+	for _, tt := range tests {
+		if got := idempotencyKey(tt.logID, tt.actionID, tt.employeeID); got != tt.want {
+			t.Errorf("idempotencyKey(%s, %s, %s) = %s, want %s", tt.logID, tt.actionID, tt.employeeID, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package broker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+)
+
+// AzureBroker is the Azure Service Bus backend
+type AzureBroker struct {
+	client *azservicebus.Client
+
+	mu      sync.Mutex
+	senders map[string]*azservicebus.Sender
+}
+
+func NewAzureBroker(connStr string) (*AzureBroker, error) {
+	client, err := azservicebus.NewClientFromConnectionString(connStr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AzureBroker{
+		client:  client,
+		senders: map[string]*azservicebus.Sender{},
+	}, nil
+}
+
+func (b *AzureBroker) sender(queue string) (*azservicebus.Sender, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s, ok := b.senders[queue]; ok {
+		return s, nil
+	}
+
+	s, err := b.client.NewSender(queue, nil)
+	if err != nil {
+		return nil, err
+	}
+	b.senders[queue] = s
+	return s, nil
+}
+
+func (b *AzureBroker) Send(ctx context.Context, queue, sessionID string, body []byte) error {
+	s, err := b.sender(queue)
+	if err != nil {
+		return err
+	}
+
+	return s.SendMessage(ctx, &azservicebus.Message{
+		SessionID: &sessionID,
+		Body:      body,
+	}, nil)
+}
+
+func (b *AzureBroker) Schedule(ctx context.Context, queue, sessionID string, body []byte, at time.Time) error {
+	s, err := b.sender(queue)
+	if err != nil {
+		return err
+	}
+
+	return s.SendMessage(ctx, &azservicebus.Message{
+		SessionID:            &sessionID,
+		Body:                 body,
+		ScheduledEnqueueTime: &at,
+	}, nil)
+}
+
+func (b *AzureBroker) AcceptNextSession(ctx context.Context, queue string) (Session, error) {
+	receiver, err := b.client.AcceptNextSessionForQueue(ctx, queue, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &azureSession{receiver: receiver}, nil
+}
+
+func (b *AzureBroker) Close(ctx context.Context) error {
+	b.mu.Lock()
+	for queue, s := range b.senders {
+		s.Close(ctx)
+		delete(b.senders, queue)
+	}
+	b.mu.Unlock()
+
+	return b.client.Close(ctx)
+}
+
+type azureSession struct {
+	receiver *azservicebus.SessionReceiver
+}
+
+func (s *azureSession) SessionID() string {
+	return s.receiver.SessionID()
+}
+
+func (s *azureSession) ReceiveMessages(ctx context.Context, maxMessages int) ([]*Message, error) {
+	received, err := s.receiver.ReceiveMessages(ctx, maxMessages, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]*Message, 0, len(received))
+	for _, r := range received {
+		msg := &Message{
+			ID:            r.MessageID,
+			Body:          r.Body,
+			DeliveryCount: int(r.DeliveryCount),
+			raw:           r,
+		}
+		if r.SessionID != nil {
+			msg.SessionID = *r.SessionID
+		}
+		if r.EnqueuedTime != nil {
+			msg.EnqueuedAt = *r.EnqueuedTime
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+func (s *azureSession) CompleteMessage(ctx context.Context, msg *Message) error {
+	r, ok := msg.raw.(*azservicebus.ReceivedMessage)
+	if !ok {
+		return ErrMessageNotLocked
+	}
+	return s.receiver.CompleteMessage(ctx, r, nil)
+}
+
+func (s *azureSession) AbandonMessage(ctx context.Context, msg *Message) error {
+	r, ok := msg.raw.(*azservicebus.ReceivedMessage)
+	if !ok {
+		return ErrMessageNotLocked
+	}
+	return s.receiver.AbandonMessage(ctx, r, nil)
+}
+
+func (s *azureSession) DeadLetterMessage(ctx context.Context, msg *Message, reason, description string) error {
+	r, ok := msg.raw.(*azservicebus.ReceivedMessage)
+	if !ok {
+		return ErrMessageNotLocked
+	}
+	return s.receiver.DeadLetterMessage(ctx, r, &azservicebus.DeadLetterOptions{
+		Reason:           &reason,
+		ErrorDescription: &description,
+	})
+}
+
+func (s *azureSession) Close(ctx context.Context) error {
+	return s.receiver.Close(ctx)
+}
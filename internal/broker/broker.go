@@ -0,0 +1,48 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultMaxDeliveryCount matches the Azure Service Bus queue default.
+// A message abandoned this many times is moved to the dead-letter queue.
+const DefaultMaxDeliveryCount = 10
+
+var ErrMessageNotLocked = errors.New("message is not locked by this session")
+
+// Message is a message received from a session. raw keeps the backend specific handle.
+type Message struct {
+	ID            string
+	SessionID     string
+	Body          []byte
+	DeliveryCount int
+	EnqueuedAt    time.Time
+
+	raw interface{}
+}
+
+// Broker sends messages to session-enabled queues and hands out sessions to receivers.
+// Messages of the same session are delivered in FIFO order to one session receiver at a time.
+type Broker interface {
+	// Send enqueues a message for immediate delivery
+	Send(ctx context.Context, queue, sessionID string, body []byte) error
+	// Schedule enqueues a message that becomes visible at the given time
+	Schedule(ctx context.Context, queue, sessionID string, body []byte, at time.Time) error
+	// AcceptNextSession locks the next session that has visible messages.
+	// It blocks until a session is available or ctx is done (then ctx.Err() is returned).
+	AcceptNextSession(ctx context.Context, queue string) (Session, error)
+	Close(ctx context.Context) error
+}
+
+// Session is an exclusive lock on one session of a queue
+type Session interface {
+	SessionID() string
+	// ReceiveMessages blocks until at least one message is available or ctx is done (then ctx.Err() is returned)
+	ReceiveMessages(ctx context.Context, maxMessages int) ([]*Message, error)
+	CompleteMessage(ctx context.Context, msg *Message) error
+	AbandonMessage(ctx context.Context, msg *Message) error
+	DeadLetterMessage(ctx context.Context, msg *Message, reason, description string) error
+	Close(ctx context.Context) error
+}
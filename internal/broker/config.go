@@ -0,0 +1,29 @@
+package broker
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+const (
+	BackendAzure = "azure"
+	BackendMySQL = "mysql"
+)
+
+// Open creates the broker selected by BROKER_BACKEND (default azure).
+// azure needs the Service Bus connection string, mysql needs db.
+// MemoryBroker is not selectable: server, scheduler and worker are separate processes and would each get their own queue.
+func Open(backend, connStr string, db *gorm.DB) (Broker, error) {
+	switch backend {
+	case "", BackendAzure:
+		return NewAzureBroker(connStr)
+	case BackendMySQL:
+		if db == nil {
+			return nil, fmt.Errorf("broker backend %q requires a database", backend)
+		}
+		return NewMySQLBroker(db), nil
+	default:
+		return nil, fmt.Errorf("unknown broker backend %q", backend)
+	}
+}
@@ -0,0 +1,297 @@
+package broker
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemoryBroker is an in-process backend for tests.
+// Sender and receiver must share the same instance, messages are lost when the process exits.
+type MemoryBroker struct {
+	mu               sync.Mutex
+	seq              int64
+	queues           map[string]*memoryQueue
+	maxDeliveryCount int
+	pollInterval     time.Duration
+}
+
+type memoryQueue struct {
+	sessions    map[string]*memorySessionState
+	deadLetters []*memoryMessage
+}
+
+type memorySessionState struct {
+	locked   bool
+	messages []*memoryMessage // เรียงตาม availableAt, seq (FIFO)
+}
+
+type memoryMessage struct {
+	seq           int64
+	sessionID     string
+	body          []byte
+	deliveryCount int
+	enqueuedAt    time.Time
+	availableAt   time.Time
+	inFlight      bool
+
+	deadLetterReason      string
+	deadLetterDescription string
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		queues:           map[string]*memoryQueue{},
+		maxDeliveryCount: DefaultMaxDeliveryCount,
+		pollInterval:     100 * time.Millisecond,
+	}
+}
+
+func (b *MemoryBroker) queue(name string) *memoryQueue {
+	q, ok := b.queues[name]
+	if !ok {
+		q = &memoryQueue{sessions: map[string]*memorySessionState{}}
+		b.queues[name] = q
+	}
+	return q
+}
+
+func (b *MemoryBroker) Send(ctx context.Context, queue, sessionID string, body []byte) error {
+	return b.Schedule(ctx, queue, sessionID, body, time.Now())
+}
+
+func (b *MemoryBroker) Schedule(ctx context.Context, queue, sessionID string, body []byte, at time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	msg := &memoryMessage{
+		seq:         b.seq,
+		sessionID:   sessionID,
+		body:        append([]byte(nil), body...),
+		enqueuedAt:  time.Now(),
+		availableAt: at,
+	}
+
+	q := b.queue(queue)
+	state, ok := q.sessions[sessionID]
+	if !ok {
+		state = &memorySessionState{}
+		q.sessions[sessionID] = state
+	}
+	state.messages = append(state.messages, msg)
+	sort.SliceStable(state.messages, func(i, j int) bool {
+		if !state.messages[i].availableAt.Equal(state.messages[j].availableAt) {
+			return state.messages[i].availableAt.Before(state.messages[j].availableAt)
+		}
+		return state.messages[i].seq < state.messages[j].seq
+	})
+
+	return nil
+}
+
+func (b *MemoryBroker) AcceptNextSession(ctx context.Context, queue string) (Session, error) {
+	for {
+		if session := b.tryAcceptSession(queue); session != nil {
+			return session, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(b.pollInterval):
+		}
+	}
+}
+
+// tryAcceptSession locks the session whose oldest visible message was sent first
+func (b *MemoryBroker) tryAcceptSession(queue string) Session {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	q := b.queue(queue)
+
+	var (
+		nextID  string
+		nextSeq int64
+	)
+	for sessionID, state := range q.sessions {
+		if state.locked {
+			continue
+		}
+		for _, m := range state.messages {
+			if !m.inFlight && !m.availableAt.After(now) {
+				if nextID == "" || m.seq < nextSeq {
+					nextID, nextSeq = sessionID, m.seq
+				}
+				break
+			}
+		}
+	}
+	if nextID == "" {
+		return nil
+	}
+
+	q.sessions[nextID].locked = true
+	return &memorySession{broker: b, queue: queue, sessionID: nextID}
+}
+
+func (b *MemoryBroker) Close(ctx context.Context) error {
+	return nil
+}
+
+type memorySession struct {
+	broker    *MemoryBroker
+	queue     string
+	sessionID string
+}
+
+func (s *memorySession) SessionID() string {
+	return s.sessionID
+}
+
+func (s *memorySession) ReceiveMessages(ctx context.Context, maxMessages int) ([]*Message, error) {
+	for {
+		if msgs := s.tryReceive(maxMessages); len(msgs) > 0 {
+			return msgs, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(s.broker.pollInterval):
+		}
+	}
+}
+
+func (s *memorySession) tryReceive(maxMessages int) []*Message {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	now := time.Now()
+	state := s.state()
+	if state == nil {
+		return nil
+	}
+
+	var msgs []*Message
+	for _, m := range state.messages {
+		if len(msgs) >= maxMessages {
+			break
+		}
+		if m.inFlight || m.availableAt.After(now) {
+			continue
+		}
+
+		m.inFlight = true
+		m.deliveryCount++
+		msgs = append(msgs, &Message{
+			ID:            strconv.FormatInt(m.seq, 10),
+			SessionID:     m.sessionID,
+			Body:          m.body,
+			DeliveryCount: m.deliveryCount,
+			EnqueuedAt:    m.enqueuedAt,
+			raw:           m,
+		})
+	}
+
+	return msgs
+}
+
+func (s *memorySession) CompleteMessage(ctx context.Context, msg *Message) error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	m, err := s.locked(msg)
+	if err != nil {
+		return err
+	}
+
+	s.remove(m)
+	m.inFlight = false
+	return nil
+}
+
+func (s *memorySession) AbandonMessage(ctx context.Context, msg *Message) error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	m, err := s.locked(msg)
+	if err != nil {
+		return err
+	}
+
+	m.inFlight = false
+	if m.deliveryCount >= s.broker.maxDeliveryCount {
+		s.deadLetter(m, "MaxDeliveryCountExceeded", "message was abandoned too many times")
+	}
+	return nil
+}
+
+func (s *memorySession) DeadLetterMessage(ctx context.Context, msg *Message, reason, description string) error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	m, err := s.locked(msg)
+	if err != nil {
+		return err
+	}
+
+	s.deadLetter(m, reason, description)
+	return nil
+}
+
+func (s *memorySession) Close(ctx context.Context) error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	state := s.state()
+	if state == nil {
+		return nil
+	}
+
+	// message ที่ยังไม่ได้ complete จะถูกส่งใหม่ให้ receiver ถัดไป
+	for _, m := range state.messages {
+		m.inFlight = false
+	}
+	state.locked = false
+	if len(state.messages) == 0 {
+		delete(s.broker.queue(s.queue).sessions, s.sessionID)
+	}
+	return nil
+}
+
+func (s *memorySession) state() *memorySessionState {
+	return s.broker.queue(s.queue).sessions[s.sessionID]
+}
+
+func (s *memorySession) locked(msg *Message) (*memoryMessage, error) {
+	m, ok := msg.raw.(*memoryMessage)
+	if !ok || !m.inFlight || m.sessionID != s.sessionID {
+		return nil, ErrMessageNotLocked
+	}
+	return m, nil
+}
+
+func (s *memorySession) remove(m *memoryMessage) {
+	state := s.state()
+	for i, existing := range state.messages {
+		if existing == m {
+			state.messages = append(state.messages[:i], state.messages[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *memorySession) deadLetter(m *memoryMessage, reason, description string) {
+	s.remove(m)
+	m.inFlight = false
+	m.deadLetterReason = reason
+	m.deadLetterDescription = description
+
+	q := s.broker.queue(s.queue)
+	q.deadLetters = append(q.deadLetters, m)
+}
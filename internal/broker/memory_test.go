@@ -0,0 +1,167 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const testQueue = "automation"
+
+func newTestBroker() *MemoryBroker {
+	b := NewMemoryBroker()
+	b.pollInterval = time.Millisecond
+	return b
+}
+
+func receive(t *testing.T, b *MemoryBroker, maxMessages int) (Session, []*Message) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	session, err := b.AcceptNextSession(ctx, testQueue)
+	if err != nil {
+		t.Fatalf("AcceptNextSession() error = %v", err)
+	}
+	msgs, err := session.ReceiveMessages(ctx, maxMessages)
+	if err != nil {
+		t.Fatalf("ReceiveMessages() error = %v", err)
+	}
+	return session, msgs
+}
+
+func TestMemoryBrokerRoundTrip(t *testing.T) {
+	b := newTestBroker()
+	ctx := context.Background()
+
+	for _, body := range []string{"first", "second"} {
+		if err := b.Send(ctx, testQueue, "S1", []byte(body)); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	session, msgs := receive(t, b, 10)
+	if session.SessionID() != "S1" {
+		t.Fatalf("SessionID() = %s, want S1", session.SessionID())
+	}
+	if len(msgs) != 2 || string(msgs[0].Body) != "first" || string(msgs[1].Body) != "second" {
+		t.Fatalf("messages are not in FIFO order: %v", msgs)
+	}
+	for _, msg := range msgs {
+		if msg.DeliveryCount != 1 {
+			t.Errorf("DeliveryCount = %d, want 1", msg.DeliveryCount)
+		}
+		if err := session.CompleteMessage(ctx, msg); err != nil {
+			t.Fatalf("CompleteMessage() error = %v", err)
+		}
+	}
+	if err := session.CompleteMessage(ctx, msgs[0]); !errors.Is(err, ErrMessageNotLocked) {
+		t.Errorf("second CompleteMessage() error = %v, want %v", err, ErrMessageNotLocked)
+	}
+	session.Close(ctx)
+
+	if s := b.tryAcceptSession(testQueue); s != nil {
+		t.Errorf("session %s still has messages", s.SessionID())
+	}
+}
+
+func TestMemoryBrokerSessionLock(t *testing.T) {
+	b := newTestBroker()
+	ctx := context.Background()
+	b.Send(ctx, testQueue, "S1", []byte("a"))
+	b.Send(ctx, testQueue, "S2", []byte("b"))
+
+	first, _ := receive(t, b, 1)
+	if first.SessionID() != "S1" {
+		t.Fatalf("first session = %s, want S1 (oldest message)", first.SessionID())
+	}
+
+	// S1 ถูกล็อกอยู่ ส่งเพิ่มก็ต้องได้ S2
+	b.Send(ctx, testQueue, "S1", []byte("c"))
+	second, _ := receive(t, b, 1)
+	if second.SessionID() != "S2" {
+		t.Fatalf("second session = %s, want S2", second.SessionID())
+	}
+	if s := b.tryAcceptSession(testQueue); s != nil {
+		t.Fatalf("accepted locked session %s", s.SessionID())
+	}
+
+	// ปิด session โดยไม่ complete: message กลับมาให้ receiver ถัดไป
+	first.Close(ctx)
+	again, msgs := receive(t, b, 10)
+	if again.SessionID() != "S1" || len(msgs) != 2 || msgs[0].DeliveryCount != 2 {
+		t.Fatalf("redelivery = %s %d messages, want S1 with 2 messages", again.SessionID(), len(msgs))
+	}
+}
+
+func TestMemoryBrokerSchedule(t *testing.T) {
+	b := newTestBroker()
+	ctx := context.Background()
+	b.Schedule(ctx, testQueue, "S1", []byte("later"), time.Now().Add(time.Hour))
+
+	if s := b.tryAcceptSession(testQueue); s != nil {
+		t.Fatal("scheduled message was visible before its time")
+	}
+
+	b.Schedule(ctx, testQueue, "S1", []byte("soon"), time.Now().Add(20*time.Millisecond))
+	_, msgs := receive(t, b, 10)
+	if len(msgs) != 1 || string(msgs[0].Body) != "soon" {
+		t.Fatalf("messages = %v, want only the due one", msgs)
+	}
+}
+
+func TestMemoryBrokerDeadLetter(t *testing.T) {
+	b := newTestBroker()
+	b.maxDeliveryCount = 2
+	ctx := context.Background()
+	b.Send(ctx, testQueue, "S1", []byte("poison"))
+	b.Send(ctx, testQueue, "S2", []byte("bad"))
+
+	// abandon ครบจำนวนแล้วเข้า dead-letter
+	for i := 0; i < 2; i++ {
+		session, msgs := receive(t, b, 1)
+		if session.SessionID() != "S1" {
+			session.Close(ctx)
+			session, msgs = receive(t, b, 1)
+		}
+		if err := session.AbandonMessage(ctx, msgs[0]); err != nil {
+			t.Fatalf("AbandonMessage() error = %v", err)
+		}
+		session.Close(ctx)
+	}
+
+	session, msgs := receive(t, b, 1)
+	if session.SessionID() != "S2" {
+		t.Fatalf("session = %s, want S2 (S1 was dead-lettered)", session.SessionID())
+	}
+	if err := session.DeadLetterMessage(ctx, msgs[0], "InvalidMessage", "cannot decode"); err != nil {
+		t.Fatalf("DeadLetterMessage() error = %v", err)
+	}
+
+	deadLetters := b.queue(testQueue).deadLetters
+	if len(deadLetters) != 2 {
+		t.Fatalf("dead letters = %d, want 2", len(deadLetters))
+	}
+	if deadLetters[0].deadLetterReason != "MaxDeliveryCountExceeded" || deadLetters[1].deadLetterReason != "InvalidMessage" {
+		t.Errorf("reasons = %s, %s", deadLetters[0].deadLetterReason, deadLetters[1].deadLetterReason)
+	}
+}
+
+func TestMemoryBrokerAcceptRespectsContext(t *testing.T) {
+	b := newTestBroker()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := b.AcceptNextSession(ctx, testQueue); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("AcceptNextSession() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestOpenRejectsUnknownBackend(t *testing.T) {
+	for _, backend := range []string{"memory", "kafka"} {
+		if _, err := Open(backend, "", nil); err == nil {
+			t.Errorf("Open(%q) succeeded", backend)
+		}
+	}
+}
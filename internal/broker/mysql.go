@@ -0,0 +1,297 @@
+package broker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrSessionLockLost = errors.New("session lock lost")
+
+const (
+	messageStatusActive     = "ACTIVE"
+	messageStatusDeadLetter = "DEAD_LETTER"
+)
+
+// brokerMessage mapped from table <broker_messages>
+type brokerMessage struct {
+	MessageID             int64      `gorm:"column:message_id;primaryKey;autoIncrement"`
+	QueueName             string     `gorm:"column:queue_name"`
+	SessionID             string     `gorm:"column:session_id"`
+	Body                  []byte     `gorm:"column:body"`
+	DeliveryCount         int        `gorm:"column:delivery_count"`
+	Status                string     `gorm:"column:status"`
+	EnqueuedAt            time.Time  `gorm:"column:enqueued_at"`
+	AvailableAt           time.Time  `gorm:"column:available_at"`
+	LockedUntil           *time.Time `gorm:"column:locked_until"`
+	DeadLetterReason      string     `gorm:"column:dead_letter_reason"`
+	DeadLetterDescription string     `gorm:"column:dead_letter_description"`
+	DeadLetteredAt        *time.Time `gorm:"column:dead_lettered_at"`
+}
+
+func (*brokerMessage) TableName() string {
+	return "broker_messages"
+}
+
+// MySQLBroker stores messages in broker_messages and session ownership in broker_session_locks.
+// It lets scheduler and worker run as separate processes without Azure (local development).
+type MySQLBroker struct {
+	db               *gorm.DB
+	lockDuration     time.Duration
+	maxDeliveryCount int
+	pollInterval     time.Duration
+}
+
+func NewMySQLBroker(db *gorm.DB) *MySQLBroker {
+	return &MySQLBroker{
+		db:               db,
+		lockDuration:     5 * time.Minute,
+		maxDeliveryCount: DefaultMaxDeliveryCount,
+		pollInterval:     time.Second,
+	}
+}
+
+func (b *MySQLBroker) Send(ctx context.Context, queue, sessionID string, body []byte) error {
+	return b.Schedule(ctx, queue, sessionID, body, time.Now())
+}
+
+func (b *MySQLBroker) Schedule(ctx context.Context, queue, sessionID string, body []byte, at time.Time) error {
+	return b.db.WithContext(ctx).Create(&brokerMessage{
+		QueueName:   queue,
+		SessionID:   sessionID,
+		Body:        body,
+		Status:      messageStatusActive,
+		EnqueuedAt:  time.Now(),
+		AvailableAt: at,
+	}).Error
+}
+
+func (b *MySQLBroker) AcceptNextSession(ctx context.Context, queue string) (Session, error) {
+	for {
+		session, err := b.tryAcceptSession(ctx, queue)
+		if err != nil {
+			return nil, err
+		}
+		if session != nil {
+			return session, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(b.pollInterval):
+		}
+	}
+}
+
+// tryAcceptSession claims the unlocked session whose oldest visible message was sent first
+func (b *MySQLBroker) tryAcceptSession(ctx context.Context, queue string) (Session, error) {
+	now := time.Now()
+
+	var candidates []string
+	err := b.db.WithContext(ctx).Raw(`
+		SELECT m.session_id
+		FROM broker_messages m
+		LEFT JOIN broker_session_locks l
+			ON l.queue_name = m.queue_name AND l.session_id = m.session_id AND l.locked_until > ?
+		WHERE m.queue_name = ?
+			AND m.status = ?
+			AND m.available_at <= ?
+			AND (m.locked_until IS NULL OR m.locked_until <= ?)
+			AND l.session_id IS NULL
+		GROUP BY m.session_id
+		ORDER BY MIN(m.message_id)
+		LIMIT 10`,
+		now, queue, messageStatusActive, now, now,
+	).Scan(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sessionID := range candidates {
+		token, err := newLockToken()
+		if err != nil {
+			return nil, err
+		}
+
+		// ได้ lock เมื่อยังไม่มีแถว หรือ lock เดิมหมดอายุแล้ว (RowsAffected = 0 แปลว่ามีคนอื่นถืออยู่)
+		res := b.db.WithContext(ctx).Exec(`
+			INSERT INTO broker_session_locks (queue_name, session_id, locked_by, locked_until)
+			VALUES (?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE
+				locked_by = IF(locked_until <= ?, VALUES(locked_by), locked_by),
+				locked_until = IF(locked_by = VALUES(locked_by), VALUES(locked_until), locked_until)`,
+			queue, sessionID, token, now.Add(b.lockDuration), now,
+		)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected > 0 {
+			return &mysqlSession{broker: b, queue: queue, sessionID: sessionID, token: token}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (b *MySQLBroker) Close(ctx context.Context) error {
+	return nil
+}
+
+type mysqlSession struct {
+	broker    *MySQLBroker
+	queue     string
+	sessionID string
+	token     string
+}
+
+func (s *mysqlSession) SessionID() string {
+	return s.sessionID
+}
+
+func (s *mysqlSession) ReceiveMessages(ctx context.Context, maxMessages int) ([]*Message, error) {
+	for {
+		msgs, err := s.tryReceive(ctx, maxMessages)
+		if err != nil {
+			return nil, err
+		}
+		if len(msgs) > 0 {
+			return msgs, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(s.broker.pollInterval):
+		}
+	}
+}
+
+func (s *mysqlSession) tryReceive(ctx context.Context, maxMessages int) ([]*Message, error) {
+	var msgs []*Message
+
+	err := s.broker.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		lockedUntil := now.Add(s.broker.lockDuration)
+
+		// ต่ออายุ session lock ทุกครั้งที่รับ message
+		res := tx.Exec(`UPDATE broker_session_locks SET locked_until = ? WHERE queue_name = ? AND session_id = ? AND locked_by = ?`,
+			lockedUntil, s.queue, s.sessionID, s.token)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrSessionLockLost
+		}
+
+		var rows []*brokerMessage
+		err := tx.Raw(`
+			SELECT * FROM broker_messages
+			WHERE queue_name = ? AND session_id = ? AND status = ?
+				AND available_at <= ?
+				AND (locked_until IS NULL OR locked_until <= ?)
+			ORDER BY available_at, message_id
+			LIMIT ?
+			FOR UPDATE`,
+			s.queue, s.sessionID, messageStatusActive, now, now, maxMessages,
+		).Scan(&rows).Error
+		if err != nil || len(rows) == 0 {
+			return err
+		}
+
+		ids := make([]int64, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.MessageID)
+		}
+		err = tx.Exec(`UPDATE broker_messages SET locked_until = ?, delivery_count = delivery_count + 1 WHERE message_id IN ?`,
+			lockedUntil, ids).Error
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			msgs = append(msgs, &Message{
+				ID:            strconv.FormatInt(row.MessageID, 10),
+				SessionID:     row.SessionID,
+				Body:          row.Body,
+				DeliveryCount: row.DeliveryCount + 1,
+				EnqueuedAt:    row.EnqueuedAt,
+				raw:           row.MessageID,
+			})
+		}
+		return nil
+	})
+
+	return msgs, err
+}
+
+func (s *mysqlSession) CompleteMessage(ctx context.Context, msg *Message) error {
+	messageID, ok := msg.raw.(int64)
+	if !ok {
+		return ErrMessageNotLocked
+	}
+
+	res := s.broker.db.WithContext(ctx).Exec(`DELETE FROM broker_messages WHERE message_id = ? AND locked_until IS NOT NULL`, messageID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrMessageNotLocked
+	}
+	return nil
+}
+
+func (s *mysqlSession) AbandonMessage(ctx context.Context, msg *Message) error {
+	if msg.DeliveryCount >= s.broker.maxDeliveryCount {
+		return s.DeadLetterMessage(ctx, msg, "MaxDeliveryCountExceeded", "message was abandoned too many times")
+	}
+
+	messageID, ok := msg.raw.(int64)
+	if !ok {
+		return ErrMessageNotLocked
+	}
+
+	return s.broker.db.WithContext(ctx).
+		Exec(`UPDATE broker_messages SET locked_until = NULL WHERE message_id = ?`, messageID).Error
+}
+
+func (s *mysqlSession) DeadLetterMessage(ctx context.Context, msg *Message, reason, description string) error {
+	messageID, ok := msg.raw.(int64)
+	if !ok {
+		return ErrMessageNotLocked
+	}
+
+	return s.broker.db.WithContext(ctx).Exec(`
+		UPDATE broker_messages
+		SET status = ?, locked_until = NULL, dead_letter_reason = ?, dead_letter_description = ?, dead_lettered_at = ?
+		WHERE message_id = ?`,
+		messageStatusDeadLetter, reason, description, time.Now(), messageID,
+	).Error
+}
+
+func (s *mysqlSession) Close(ctx context.Context) error {
+	return s.broker.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// message ที่ยังไม่ได้ complete จะถูกส่งใหม่ให้ receiver ถัดไป
+		err := tx.Exec(`UPDATE broker_messages SET locked_until = NULL WHERE queue_name = ? AND session_id = ? AND status = ? AND locked_until IS NOT NULL`,
+			s.queue, s.sessionID, messageStatusActive).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`DELETE FROM broker_session_locks WHERE queue_name = ? AND session_id = ? AND locked_by = ?`,
+			s.queue, s.sessionID, s.token).Error
+	})
+}
+
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,51 @@
+package callback
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// Headers sent to an async action so it knows where and how to report back
+	URLHeader   = "X-Callback-URL"
+	TokenHeader = "X-Callback-Token"
+)
+
+// Signer issues and verifies callback tokens for async action steps.
+// The token is HMAC-SHA256(secret, step_id) so the server needs no extra storage to verify it.
+type Signer struct {
+	secret  []byte
+	baseURL string
+}
+
+// NewSigner creates a signer. baseURL is the public API base, e.g. https://automation.example.com/api/v1
+func NewSigner(secret, baseURL string) *Signer {
+	return &Signer{
+		secret:  []byte(secret),
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Enabled reports whether async callbacks are configured
+func (s *Signer) Enabled() bool {
+	return s != nil && len(s.secret) > 0 && s.baseURL != ""
+}
+
+func (s *Signer) Token(stepID string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(stepID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Signer) Verify(stepID, token string) bool {
+	if s == nil || len(s.secret) == 0 || token == "" {
+		return false
+	}
+	return hmac.Equal([]byte(s.Token(stepID)), []byte(token))
+}
+
+func (s *Signer) URL(stepID string) string {
+	return s.baseURL + "/callbacks/action-executions/" + stepID
+}
@@ -0,0 +1,72 @@
+package callback
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestSignerToken(t *testing.T) {
+	signer := NewSigner("secret", "https://automation.example.com/api/v1/")
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("STEP001"))
+	if want := hex.EncodeToString(mac.Sum(nil)); signer.Token("STEP001") != want {
+		t.Errorf("Token() = %s, want HMAC-SHA256 %s", signer.Token("STEP001"), want)
+	}
+	if signer.Token("STEP001") == signer.Token("STEP002") {
+		t.Error("different steps got the same token")
+	}
+	if want := "https://automation.example.com/api/v1/callbacks/action-executions/STEP001"; signer.URL("STEP001") != want {
+		t.Errorf("URL() = %s, want %s", signer.URL("STEP001"), want)
+	}
+}
+
+func TestSignerVerify(t *testing.T) {
+	signer := NewSigner("secret", "https://automation.example.com")
+	token := signer.Token("STEP001")
+
+	tests := []struct {
+		name   string
+		signer *Signer
+		stepID string
+		token  string
+		want   bool
+	}{
+		{name: "valid token", signer: signer, stepID: "STEP001", token: token, want: true},
+		{name: "token of another step", signer: signer, stepID: "STEP002", token: token, want: false},
+		{name: "token signed with another secret", signer: NewSigner("other", "https://automation.example.com"), stepID: "STEP001", token: token, want: false},
+		{name: "empty token", signer: signer, stepID: "STEP001", token: "", want: false},
+		{name: "signer without secret", signer: NewSigner("", "https://automation.example.com"), stepID: "STEP001", token: NewSigner("", "").Token("STEP001"), want: false},
+		{name: "nil signer", signer: nil, stepID: "STEP001", token: token, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.signer.Verify(tt.stepID, tt.token); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignerEnabled(t *testing.T) {
+	var nilSigner *Signer
+	tests := []struct {
+		name   string
+		signer *Signer
+		want   bool
+	}{
+		{name: "configured", signer: NewSigner("secret", "https://automation.example.com"), want: true},
+		{name: "no secret", signer: NewSigner("", "https://automation.example.com"), want: false},
+		{name: "no base url", signer: NewSigner("secret", ""), want: false},
+		{name: "nil", signer: nilSigner, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.signer.Enabled(); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
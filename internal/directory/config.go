@@ -0,0 +1,50 @@
+package directory
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ProviderConfig describes DIRECTORY_PROVIDER, e.g.
+//
+//	{"kind":"http","url":"http://hr/api/employees/search"}
+//	{"kind":"sql","query":"SELECT employee_id, employee_name, email FROM employees WHERE ..."}
+//	{"kind":"static","employees":[{"employee_id":"E001","company_id":"C1"}]}
+type ProviderConfig struct {
+	Kind      string      `json:"kind"`
+	URL       string      `json:"url"`
+	Query     string      `json:"query"`
+	Employees []Recipient `json:"employees"`
+}
+
+// NewFromConfig builds the provider described by a JSON ProviderConfig. An empty config returns nil (no directory).
+func NewFromConfig(raw string, db *gorm.DB) (Provider, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var cfg ProviderConfig
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return nil, fmt.Errorf("invalid directory provider config: %w", err)
+	}
+
+	switch strings.ToLower(cfg.Kind) {
+	case "http":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("directory provider: url is required")
+		}
+		return NewHTTPProvider(cfg.URL), nil
+	case "sql":
+		if cfg.Query == "" {
+			return nil, fmt.Errorf("directory provider: query is required")
+		}
+		return NewSQLProvider(db, cfg.Query), nil
+	case "static":
+		return NewStaticProvider(cfg.Employees), nil
+	default:
+		return nil, fmt.Errorf("unsupported directory provider kind %q", cfg.Kind)
+	}
+}
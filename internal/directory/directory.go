@@ -0,0 +1,105 @@
+package directory
+
+import (
+	"automation-engine/internal/domain/model"
+	"context"
+	"errors"
+)
+
+// ErrNoProvider is returned when a selector needs the employee directory but none is configured
+var ErrNoProvider = errors.New("no directory provider configured")
+
+// Selector is one RunAutomationTarget row. Empty fields match every employee, filled fields must all match.
+type Selector struct {
+	CompanyID        string `json:"company_id,omitempty"`
+	BranchID         string `json:"branch_id,omitempty"`
+	DepartmentID     string `json:"department_id,omitempty"`
+	DivisionID       string `json:"division_id,omitempty"`
+	SectionID        string `json:"section_id,omitempty"`
+	SectionLv01ID    string `json:"section_lv01_id,omitempty"`
+	SectionLv02ID    string `json:"section_lv02_id,omitempty"`
+	SectionLv03ID    string `json:"section_lv03_id,omitempty"`
+	SectionLv04ID    string `json:"section_lv04_id,omitempty"`
+	SectionLv05ID    string `json:"section_lv05_id,omitempty"`
+	PositionID       string `json:"position_id,omitempty"`
+	EmployeeTypeCode string `json:"employee_type_code,omitempty"`
+	EmployeeID       string `json:"employee_id,omitempty"`
+}
+
+// Recipient is a concrete employee returned by the directory
+type Recipient struct {
+	EmployeeID       string `json:"employee_id"`
+	EmployeeName     string `json:"employee_name,omitempty"`
+	Email            string `json:"email,omitempty"`
+	CompanyID        string `json:"company_id,omitempty"`
+	BranchID         string `json:"branch_id,omitempty"`
+	DepartmentID     string `json:"department_id,omitempty"`
+	DivisionID       string `json:"division_id,omitempty"`
+	SectionID        string `json:"section_id,omitempty"`
+	SectionLv01ID    string `json:"section_lv01_id,omitempty"`
+	SectionLv02ID    string `json:"section_lv02_id,omitempty"`
+	SectionLv03ID    string `json:"section_lv03_id,omitempty"`
+	SectionLv04ID    string `json:"section_lv04_id,omitempty"`
+	SectionLv05ID    string `json:"section_lv05_id,omitempty"`
+	PositionID       string `json:"position_id,omitempty"`
+	EmployeeTypeCode string `json:"employee_type_code,omitempty"`
+}
+
+// Provider looks up the employees matching a selector (HR system, database view, static list)
+type Provider interface {
+	FindEmployees(ctx context.Context, selector Selector) ([]Recipient, error)
+}
+
+func SelectorFromTarget(target *model.RunAutomationTarget) Selector {
+	return Selector{
+		CompanyID:        target.CompanyID,
+		BranchID:         target.BranchID,
+		DepartmentID:     target.DepartmentID,
+		DivisionID:       target.DivisionID,
+		SectionID:        target.SectionID,
+		SectionLv01ID:    target.SectionLv01ID,
+		SectionLv02ID:    target.SectionLv02ID,
+		SectionLv03ID:    target.SectionLv03ID,
+		SectionLv04ID:    target.SectionLv04ID,
+		SectionLv05ID:    target.SectionLv05ID,
+		PositionID:       target.PositionID,
+		EmployeeTypeCode: target.EmployeeTypeCode,
+		EmployeeID:       target.EmployeeID,
+	}
+}
+
+// IsEmpty reports whether the selector has no field set (it would match the whole directory)
+func (s Selector) IsEmpty() bool {
+	return s == Selector{}
+}
+
+// IsEmployeeOnly reports whether the selector names a single employee and nothing else,
+// such a selector can be resolved without a directory
+func (s Selector) IsEmployeeOnly() bool {
+	return s.EmployeeID != "" && s == Selector{EmployeeID: s.EmployeeID}
+}
+
+// Matches reports whether the recipient belongs to every level set on the selector
+func (s Selector) Matches(r Recipient) bool {
+	pairs := [][2]string{
+		{s.CompanyID, r.CompanyID},
+		{s.BranchID, r.BranchID},
+		{s.DepartmentID, r.DepartmentID},
+		{s.DivisionID, r.DivisionID},
+		{s.SectionID, r.SectionID},
+		{s.SectionLv01ID, r.SectionLv01ID},
+		{s.SectionLv02ID, r.SectionLv02ID},
+		{s.SectionLv03ID, r.SectionLv03ID},
+		{s.SectionLv04ID, r.SectionLv04ID},
+		{s.SectionLv05ID, r.SectionLv05ID},
+		{s.PositionID, r.PositionID},
+		{s.EmployeeTypeCode, r.EmployeeTypeCode},
+		{s.EmployeeID, r.EmployeeID},
+	}
+	for _, pair := range pairs {
+		if pair[0] != "" && pair[0] != pair[1] {
+			return false
+		}
+	}
+	return true
+}
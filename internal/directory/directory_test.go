@@ -0,0 +1,121 @@
+package directory
+
+import (
+	"automation-engine/internal/domain/model"
+	"context"
+	"testing"
+)
+
+func TestSelectorMatches(t *testing.T) {
+	employee := Recipient{
+		EmployeeID:       "E001",
+		CompanyID:        "C1",
+		BranchID:         "B1",
+		DepartmentID:     "D1",
+		SectionLv02ID:    "S2",
+		PositionID:       "P1",
+		EmployeeTypeCode: "FT",
+	}
+
+	tests := []struct {
+		name     string
+		selector Selector
+		want     bool
+	}{
+		{name: "empty selector matches everyone", selector: Selector{}, want: true},
+		{name: "single level", selector: Selector{CompanyID: "C1"}, want: true},
+		{name: "every set level matches", selector: Selector{CompanyID: "C1", DepartmentID: "D1", SectionLv02ID: "S2", EmployeeTypeCode: "FT"}, want: true},
+		{name: "one level differs", selector: Selector{CompanyID: "C1", DepartmentID: "D2"}, want: false},
+		{name: "level the employee does not have", selector: Selector{DivisionID: "V1"}, want: false},
+		{name: "employee id", selector: Selector{EmployeeID: "E001"}, want: true},
+		{name: "other employee id", selector: Selector{CompanyID: "C1", EmployeeID: "E002"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.selector.Matches(employee); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectorKinds(t *testing.T) {
+	tests := []struct {
+		name         string
+		selector     Selector
+		empty        bool
+		employeeOnly bool
+	}{
+		{name: "empty", selector: Selector{}, empty: true},
+		{name: "employee only", selector: Selector{EmployeeID: "E001"}, employeeOnly: true},
+		{name: "employee within company", selector: Selector{CompanyID: "C1", EmployeeID: "E001"}},
+		{name: "organisation level", selector: Selector{DepartmentID: "D1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.selector.IsEmpty(); got != tt.empty {
+				t.Errorf("IsEmpty() = %v, want %v", got, tt.empty)
+			}
+			if got := tt.selector.IsEmployeeOnly(); got != tt.employeeOnly {
+				t.Errorf("IsEmployeeOnly() = %v, want %v", got, tt.employeeOnly)
+			}
+		})
+	}
+}
+
+func TestSelectorFromTarget(t *testing.T) {
+	target := &model.RunAutomationTarget{
+		AutomationTargetID: "T001",
+		TargetMode:         "INCLUDE",
+		CompanyID:          "C1",
+		SectionLv05ID:      "S5",
+		EmployeeID:         "E001",
+	}
+
+	want := Selector{CompanyID: "C1", SectionLv05ID: "S5", EmployeeID: "E001"}
+	if got := SelectorFromTarget(target); got != want {
+		t.Errorf("SelectorFromTarget() = %+v, want %+v", got, want)
+	}
+}
+
+func TestStaticProviderFindEmployees(t *testing.T) {
+	provider := NewStaticProvider([]Recipient{
+		{EmployeeID: "E001", CompanyID: "C1", DepartmentID: "D1"},
+		{EmployeeID: "E002", CompanyID: "C1", DepartmentID: "D2"},
+		{EmployeeID: "E003", CompanyID: "C2", DepartmentID: "D1"},
+	})
+
+	got, err := provider.FindEmployees(context.Background(), Selector{DepartmentID: "D1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].EmployeeID != "E001" || got[1].EmployeeID != "E003" {
+		t.Errorf("FindEmployees() = %+v, want E001 and E003", got)
+	}
+
+	got, err = provider.FindEmployees(context.Background(), Selector{CompanyID: "C9"})
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("FindEmployees() = %+v, %v, want an empty list", got, err)
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	provider, err := NewFromConfig("  ", nil)
+	if err != nil || provider != nil {
+		t.Errorf("empty config = %v, %v, want no provider", provider, err)
+	}
+
+	provider, err = NewFromConfig(`{"kind":"static","employees":[{"employee_id":"E001"}]}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := provider.(*StaticProvider); !ok {
+		t.Errorf("static config built %T", provider)
+	}
+
+	for _, raw := range []string{`{`, `{"kind":"http"}`, `{"kind":"sql"}`, `{"kind":"ldap"}`} {
+		if _, err := NewFromConfig(raw, nil); err == nil {
+			t.Errorf("NewFromConfig(%s) succeeded, want error", raw)
+		}
+	}
+}
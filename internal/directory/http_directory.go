@@ -0,0 +1,43 @@
+package directory
+
+import (
+	"automation-engine/internal/httpclient"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// HTTPProvider posts the selector to an HR service which answers {"data": [recipient, ...]}
+type HTTPProvider struct {
+	url string
+}
+
+func NewHTTPProvider(url string) *HTTPProvider {
+	return &HTTPProvider{url: url}
+}
+
+type httpProviderResponse struct {
+	Data []Recipient `json:"data"`
+}
+
+func (p *HTTPProvider) FindEmployees(ctx context.Context, selector Selector) ([]Recipient, error) {
+	body, err := json.Marshal(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpclient.NewRequest(http.MethodPost, p.url).WithJSONBody(body).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("directory provider responded with status %d", resp.StatusCode)
+	}
+
+	var result httpProviderResponse
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return nil, fmt.Errorf("invalid directory provider response: %w", err)
+	}
+	return result.Data, nil
+}
@@ -0,0 +1,50 @@
+package directory
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// SQLProvider runs a query whose columns are named after the Recipient fields (employee_id, employee_name, email, company_id, ...).
+// Every selector field is passed as a named parameter (@company_id, @branch_id, ..., @employee_id), empty when not set, e.g.
+//
+//	SELECT employee_id, employee_name, email FROM employees
+//	WHERE (@company_id = '' OR company_id = @company_id) AND (@employee_id = '' OR employee_id = @employee_id)
+type SQLProvider struct {
+	db    *gorm.DB
+	query string
+}
+
+func NewSQLProvider(db *gorm.DB, query string) *SQLProvider {
+	return &SQLProvider{
+		db:    db,
+		query: query,
+	}
+}
+
+func (p *SQLProvider) FindEmployees(ctx context.Context, selector Selector) ([]Recipient, error) {
+	var recipients []Recipient
+
+	err := p.db.WithContext(ctx).Raw(p.query, map[string]interface{}{
+		"company_id":         selector.CompanyID,
+		"branch_id":          selector.BranchID,
+		"department_id":      selector.DepartmentID,
+		"division_id":        selector.DivisionID,
+		"section_id":         selector.SectionID,
+		"section_lv01_id":    selector.SectionLv01ID,
+		"section_lv02_id":    selector.SectionLv02ID,
+		"section_lv03_id":    selector.SectionLv03ID,
+		"section_lv04_id":    selector.SectionLv04ID,
+		"section_lv05_id":    selector.SectionLv05ID,
+		"position_id":        selector.PositionID,
+		"employee_type_code": selector.EmployeeTypeCode,
+		"employee_id":        selector.EmployeeID,
+	}).Scan(&recipients).Error
+	if err != nil {
+		return nil, fmt.Errorf("directory query failed: %w", err)
+	}
+
+	return recipients, nil
+}
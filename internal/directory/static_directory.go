@@ -0,0 +1,22 @@
+package directory
+
+import "context"
+
+// StaticProvider matches selectors against a fixed employee list (useful for testing and small deployments)
+type StaticProvider struct {
+	employees []Recipient
+}
+
+func NewStaticProvider(employees []Recipient) *StaticProvider {
+	return &StaticProvider{employees: employees}
+}
+
+func (p *StaticProvider) FindEmployees(ctx context.Context, selector Selector) ([]Recipient, error) {
+	result := make([]Recipient, 0)
+	for _, employee := range p.employees {
+		if selector.Matches(employee) {
+			result = append(result, employee)
+		}
+	}
+	return result, nil
+}
@@ -12,18 +12,25 @@ const TableNameDefAction = "def_actions"
 
 // DefAction mapped from table <def_actions>
 type DefAction struct {
-	ActionID     string    `gorm:"column:action_id;primaryKey" json:"action_id"`
-	ActionCode   string    `gorm:"column:action_code" json:"action_code"`
-	ActionName   string    `gorm:"column:action_name" json:"action_name"`
-	ActionType   string    `gorm:"column:action_type;not null" json:"action_type"`
-	InvokeURL    string    `gorm:"column:invoke_url" json:"invoke_url"`
-	InvokeMethod string    `gorm:"column:invoke_method;not null;default:POST" json:"invoke_method"`
-	InvokeType   string    `gorm:"column:invoke_type;not null;default:sync" json:"invoke_type"`
-	Status       string    `gorm:"column:status;not null" json:"status"`
-	Created      time.Time `gorm:"column:created;not null;default:CURRENT_TIMESTAMP" json:"created"`
-	CreatedBy    string    `gorm:"column:created_by" json:"created_by"`
-	LastUpd      time.Time `gorm:"column:last_upd;not null;default:CURRENT_TIMESTAMP" json:"last_upd"`
-	LastUpdBy    string    `gorm:"column:last_upd_by" json:"last_upd_by"`
+	ActionID               string    `gorm:"column:action_id;primaryKey" json:"action_id"`
+	ActionCode             string    `gorm:"column:action_code" json:"action_code"`
+	ActionName             string    `gorm:"column:action_name" json:"action_name"`
+	ActionType             string    `gorm:"column:action_type;not null" json:"action_type"`
+	InvokeURL              string    `gorm:"column:invoke_url" json:"invoke_url"`
+	InvokeMethod           string    `gorm:"column:invoke_method;not null;default:POST" json:"invoke_method"`
+	InvokeType             string    `gorm:"column:invoke_type;not null;default:sync" json:"invoke_type"`
+	Status                 string    `gorm:"column:status;not null" json:"status"`
+	Created                time.Time `gorm:"column:created;not null;default:CURRENT_TIMESTAMP" json:"created"`
+	CreatedBy              string    `gorm:"column:created_by" json:"created_by"`
+	LastUpd                time.Time `gorm:"column:last_upd;not null;default:CURRENT_TIMESTAMP" json:"last_upd"`
+	LastUpdBy              string    `gorm:"column:last_upd_by" json:"last_upd_by"`
+	InvokeHeaders          string    `gorm:"column:invoke_headers" json:"invoke_headers"`
+	InvokeTimeout          int32     `gorm:"column:invoke_timeout;not null;default:0" json:"invoke_timeout"`
+	RetryMaxAttempts       int32     `gorm:"column:retry_max_attempts;not null;default:1" json:"retry_max_attempts"`
+	RetryBackoffSeconds    int32     `gorm:"column:retry_backoff_seconds;not null;default:0" json:"retry_backoff_seconds"`
+	RetryMaxBackoffSeconds int32     `gorm:"column:retry_max_backoff_seconds;not null;default:0" json:"retry_max_backoff_seconds"`
+	RetryStatusCodes       string    `gorm:"column:retry_status_codes" json:"retry_status_codes"`
+	PayloadTemplate        string    `gorm:"column:payload_template" json:"payload_template"`
 }
 
 // TableName DefAction's table name
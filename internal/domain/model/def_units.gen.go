@@ -20,6 +20,8 @@ type DefUnit struct {
 	CreatedBy string    `gorm:"column:created_by" json:"created_by"`
 	LastUpd   time.Time `gorm:"column:last_upd;not null;default:CURRENT_TIMESTAMP" json:"last_upd"`
 	LastUpdBy string    `gorm:"column:last_upd_by" json:"last_upd_by"`
+	Dimension string    `gorm:"column:dimension" json:"dimension"`
+	Factor    float64   `gorm:"column:factor" json:"factor"`
 }
 
 // TableName DefUnit's table name
@@ -12,13 +12,29 @@ const TableNameLogAutomationExecution = "log_automation_executions"
 
 // LogAutomationExecution mapped from table <log_automation_executions>
 type LogAutomationExecution struct {
-	LogID          string    `gorm:"column:log_id;primaryKey" json:"log_id"`
-	AutomationID   string    `gorm:"column:automation_id" json:"automation_id"`
-	Status         string    `gorm:"column:status;not null;default:FAILED" json:"status"`
-	TriggeredAt    time.Time `gorm:"column:triggered_at;not null;default:CURRENT_TIMESTAMP" json:"triggered_at"`
-	FinishedAt     time.Time `gorm:"column:finished_at;not null" json:"finished_at"`
-	ConfigSnapshot string    `gorm:"column:config_snapshot" json:"config_snapshot"`
-	ErrorMessage   string    `gorm:"column:error_message" json:"error_message"`
+	LogID            string    `gorm:"column:log_id;primaryKey" json:"log_id"`
+	AutomationID     string    `gorm:"column:automation_id" json:"automation_id"`
+	Status           string    `gorm:"column:status;not null;default:FAILED" json:"status"`
+	TriggeredAt      time.Time `gorm:"column:triggered_at;not null;default:CURRENT_TIMESTAMP" json:"triggered_at"`
+	FinishedAt       time.Time `gorm:"column:finished_at;not null" json:"finished_at"`
+	ConfigSnapshot   string    `gorm:"column:config_snapshot" json:"config_snapshot"`
+	ErrorMessage     string    `gorm:"column:error_message" json:"error_message"`
+	ScheduledFor     time.Time `gorm:"column:scheduled_for" json:"scheduled_for"`
+	IsCatchUp        bool      `gorm:"column:is_catch_up;not null;default:0" json:"is_catch_up"`
+	TriggerType      string    `gorm:"column:trigger_type;not null;default:SCHEDULE" json:"trigger_type"`
+	TriggeredBy      string    `gorm:"column:triggered_by" json:"triggered_by"`
+	IsDryRun         bool      `gorm:"column:is_dry_run;not null;default:0" json:"is_dry_run"`
+	Attempt          int32     `gorm:"column:attempt;not null;default:1" json:"attempt"`
+	MessagePayload   string    `gorm:"column:message_payload" json:"message_payload"`
+	DeadLetterReason string    `gorm:"column:dead_letter_reason" json:"dead_letter_reason"`
+	DeadLetteredAt   time.Time `gorm:"column:dead_lettered_at" json:"dead_lettered_at"`
+	ReplayOfLogID    string    `gorm:"column:replay_of_log_id" json:"replay_of_log_id"`
+	ReplayedByLogID  string    `gorm:"column:replayed_by_log_id" json:"replayed_by_log_id"`
+	ClaimedUntil     time.Time `gorm:"column:claimed_until" json:"claimed_until"`
+	TargetTotal      int32     `gorm:"column:target_total;not null;default:0" json:"target_total"`
+	TargetSucceeded  int32     `gorm:"column:target_succeeded;not null;default:0" json:"target_succeeded"`
+	TargetFailed     int32     `gorm:"column:target_failed;not null;default:0" json:"target_failed"`
+	TargetSkipped    int32     `gorm:"column:target_skipped;not null;default:0" json:"target_skipped"`
 }
 
 // TableName LogAutomationExecution's table name
@@ -21,6 +21,8 @@ type RunAutomationAction struct {
 	CreatedBy          string    `gorm:"column:created_by" json:"created_by"`
 	LastUpd            time.Time `gorm:"column:last_upd;not null;default:CURRENT_TIMESTAMP" json:"last_upd"`
 	LastUpdBy          string    `gorm:"column:last_upd_by" json:"last_upd_by"`
+	ExecutionMode      string    `gorm:"column:execution_mode;not null;default:SINGLE" json:"execution_mode"`
+	FanOutConcurrency  int32     `gorm:"column:fan_out_concurrency;not null;default:0" json:"fan_out_concurrency"`
 }
 
 // TableName RunAutomationAction's table name
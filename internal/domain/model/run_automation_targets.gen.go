@@ -31,6 +31,7 @@ type RunAutomationTarget struct {
 	CreatedBy          string    `gorm:"column:created_by" json:"created_by"`
 	LastUpd            time.Time `gorm:"column:last_upd;not null;default:CURRENT_TIMESTAMP" json:"last_upd"`
 	LastUpdBy          string    `gorm:"column:last_upd_by" json:"last_upd_by"`
+	TargetMode         string    `gorm:"column:target_mode;not null;default:INCLUDE" json:"target_mode"`
 }
 
 // TableName RunAutomationTarget's table name
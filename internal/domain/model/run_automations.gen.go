@@ -28,6 +28,17 @@ type RunAutomation struct {
 	CreatedBy               string    `gorm:"column:created_by" json:"created_by"`
 	LastUpd                 time.Time `gorm:"column:last_upd;not null;default:CURRENT_TIMESTAMP" json:"last_upd"`
 	LastUpdBy               string    `gorm:"column:last_upd_by" json:"last_upd_by"`
+	LockedBy                string    `gorm:"column:locked_by" json:"locked_by"`
+	LockExpiresAt           time.Time `gorm:"column:lock_expires_at" json:"lock_expires_at"`
+	MisfirePolicy           string    `gorm:"column:misfire_policy;not null;default:fire_once_now" json:"misfire_policy"`
+	CronExpression          string    `gorm:"column:cron_expression" json:"cron_expression"`
+	TimeZone                string    `gorm:"column:time_zone" json:"time_zone"`
+	EndDate                 time.Time `gorm:"column:end_date" json:"end_date"`
+	MaxRuns                 int32     `gorm:"column:max_runs;not null;default:0" json:"max_runs"`
+	RunCount                int32     `gorm:"column:run_count;not null;default:0" json:"run_count"`
+	BlackoutCalendar        string    `gorm:"column:blackout_calendar" json:"blackout_calendar"`
+	BlackoutRule            string    `gorm:"column:blackout_rule;not null;default:skip" json:"blackout_rule"`
+	LifecycleState          string    `gorm:"column:lifecycle_state;not null;default:ACTIVE" json:"lifecycle_state"`
 }
 
 // TableName RunAutomation's table name
@@ -40,6 +40,13 @@ func newDefAction(db *gorm.DB, opts ...gen.DOOption) defAction {
 	_defAction.CreatedBy = field.NewString(tableName, "created_by")
 	_defAction.LastUpd = field.NewTime(tableName, "last_upd")
 	_defAction.LastUpdBy = field.NewString(tableName, "last_upd_by")
+	_defAction.InvokeHeaders = field.NewString(tableName, "invoke_headers")
+	_defAction.InvokeTimeout = field.NewInt32(tableName, "invoke_timeout")
+	_defAction.RetryMaxAttempts = field.NewInt32(tableName, "retry_max_attempts")
+	_defAction.RetryBackoffSeconds = field.NewInt32(tableName, "retry_backoff_seconds")
+	_defAction.RetryMaxBackoffSeconds = field.NewInt32(tableName, "retry_max_backoff_seconds")
+	_defAction.RetryStatusCodes = field.NewString(tableName, "retry_status_codes")
+	_defAction.PayloadTemplate = field.NewString(tableName, "payload_template")
 
 	_defAction.fillFieldMap()
 
@@ -49,19 +56,26 @@ func newDefAction(db *gorm.DB, opts ...gen.DOOption) defAction {
 type defAction struct {
 	defActionDo defActionDo
 
-	ALL          field.Asterisk
-	ActionID     field.String
-	ActionCode   field.String
-	ActionName   field.String
-	ActionType   field.String
-	InvokeURL    field.String
-	InvokeMethod field.String
-	InvokeType   field.String
-	Status       field.String
-	Created      field.Time
-	CreatedBy    field.String
-	LastUpd      field.Time
-	LastUpdBy    field.String
+	ALL                    field.Asterisk
+	ActionID               field.String
+	ActionCode             field.String
+	ActionName             field.String
+	ActionType             field.String
+	InvokeURL              field.String
+	InvokeMethod           field.String
+	InvokeType             field.String
+	Status                 field.String
+	Created                field.Time
+	CreatedBy              field.String
+	LastUpd                field.Time
+	LastUpdBy              field.String
+	InvokeHeaders          field.String
+	InvokeTimeout          field.Int32
+	RetryMaxAttempts       field.Int32
+	RetryBackoffSeconds    field.Int32
+	RetryMaxBackoffSeconds field.Int32
+	RetryStatusCodes       field.String
+	PayloadTemplate        field.String
 
 	fieldMap map[string]field.Expr
 }
@@ -90,6 +104,13 @@ func (d *defAction) updateTableName(table string) *defAction {
 	d.CreatedBy = field.NewString(table, "created_by")
 	d.LastUpd = field.NewTime(table, "last_upd")
 	d.LastUpdBy = field.NewString(table, "last_upd_by")
+	d.InvokeHeaders = field.NewString(table, "invoke_headers")
+	d.InvokeTimeout = field.NewInt32(table, "invoke_timeout")
+	d.RetryMaxAttempts = field.NewInt32(table, "retry_max_attempts")
+	d.RetryBackoffSeconds = field.NewInt32(table, "retry_backoff_seconds")
+	d.RetryMaxBackoffSeconds = field.NewInt32(table, "retry_max_backoff_seconds")
+	d.RetryStatusCodes = field.NewString(table, "retry_status_codes")
+	d.PayloadTemplate = field.NewString(table, "payload_template")
 
 	d.fillFieldMap()
 
@@ -104,7 +125,9 @@ func (d defAction) TableName() string { return d.defActionDo.TableName() }
 
 func (d defAction) Alias() string { return d.defActionDo.Alias() }
 
-func (d defAction) Columns(cols ...field.Expr) gen.Columns { return d.defActionDo.Columns(cols...) }
+func (d defAction) Columns(cols ...field.Expr) gen.Columns {
+	return d.defActionDo.Columns(cols...)
+}
 
 func (d *defAction) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 	_f, ok := d.fieldMap[fieldName]
@@ -116,7 +139,7 @@ func (d *defAction) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (d *defAction) fillFieldMap() {
-	d.fieldMap = make(map[string]field.Expr, 12)
+	d.fieldMap = make(map[string]field.Expr, 19)
 	d.fieldMap["action_id"] = d.ActionID
 	d.fieldMap["action_code"] = d.ActionCode
 	d.fieldMap["action_name"] = d.ActionName
@@ -129,6 +152,13 @@ func (d *defAction) fillFieldMap() {
 	d.fieldMap["created_by"] = d.CreatedBy
 	d.fieldMap["last_upd"] = d.LastUpd
 	d.fieldMap["last_upd_by"] = d.LastUpdBy
+	d.fieldMap["invoke_headers"] = d.InvokeHeaders
+	d.fieldMap["invoke_timeout"] = d.InvokeTimeout
+	d.fieldMap["retry_max_attempts"] = d.RetryMaxAttempts
+	d.fieldMap["retry_backoff_seconds"] = d.RetryBackoffSeconds
+	d.fieldMap["retry_max_backoff_seconds"] = d.RetryMaxBackoffSeconds
+	d.fieldMap["retry_status_codes"] = d.RetryStatusCodes
+	d.fieldMap["payload_template"] = d.PayloadTemplate
 }
 
 func (d defAction) clone(db *gorm.DB) defAction {
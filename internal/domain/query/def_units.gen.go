@@ -36,6 +36,8 @@ func newDefUnit(db *gorm.DB, opts ...gen.DOOption) defUnit {
 	_defUnit.CreatedBy = field.NewString(tableName, "created_by")
 	_defUnit.LastUpd = field.NewTime(tableName, "last_upd")
 	_defUnit.LastUpdBy = field.NewString(tableName, "last_upd_by")
+	_defUnit.Dimension = field.NewString(tableName, "dimension")
+	_defUnit.Factor = field.NewFloat64(tableName, "factor")
 
 	_defUnit.fillFieldMap()
 
@@ -54,6 +56,8 @@ type defUnit struct {
 	CreatedBy field.String
 	LastUpd   field.Time
 	LastUpdBy field.String
+	Dimension field.String
+	Factor    field.Float64
 
 	fieldMap map[string]field.Expr
 }
@@ -78,6 +82,8 @@ func (d *defUnit) updateTableName(table string) *defUnit {
 	d.CreatedBy = field.NewString(table, "created_by")
 	d.LastUpd = field.NewTime(table, "last_upd")
 	d.LastUpdBy = field.NewString(table, "last_upd_by")
+	d.Dimension = field.NewString(table, "dimension")
+	d.Factor = field.NewFloat64(table, "factor")
 
 	d.fillFieldMap()
 
@@ -102,7 +108,7 @@ func (d *defUnit) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (d *defUnit) fillFieldMap() {
-	d.fieldMap = make(map[string]field.Expr, 8)
+	d.fieldMap = make(map[string]field.Expr, 10)
 	d.fieldMap["unit_id"] = d.UnitID
 	d.fieldMap["unit_code"] = d.UnitCode
 	d.fieldMap["unit_name"] = d.UnitName
@@ -111,6 +117,8 @@ func (d *defUnit) fillFieldMap() {
 	d.fieldMap["created_by"] = d.CreatedBy
 	d.fieldMap["last_upd"] = d.LastUpd
 	d.fieldMap["last_upd_by"] = d.LastUpdBy
+	d.fieldMap["dimension"] = d.Dimension
+	d.fieldMap["factor"] = d.Factor
 }
 
 func (d defUnit) clone(db *gorm.DB) defUnit {
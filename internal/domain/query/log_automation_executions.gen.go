@@ -35,6 +35,22 @@ func newLogAutomationExecution(db *gorm.DB, opts ...gen.DOOption) logAutomationE
 	_logAutomationExecution.FinishedAt = field.NewTime(tableName, "finished_at")
 	_logAutomationExecution.ConfigSnapshot = field.NewString(tableName, "config_snapshot")
 	_logAutomationExecution.ErrorMessage = field.NewString(tableName, "error_message")
+	_logAutomationExecution.ScheduledFor = field.NewTime(tableName, "scheduled_for")
+	_logAutomationExecution.IsCatchUp = field.NewBool(tableName, "is_catch_up")
+	_logAutomationExecution.TriggerType = field.NewString(tableName, "trigger_type")
+	_logAutomationExecution.TriggeredBy = field.NewString(tableName, "triggered_by")
+	_logAutomationExecution.IsDryRun = field.NewBool(tableName, "is_dry_run")
+	_logAutomationExecution.Attempt = field.NewInt32(tableName, "attempt")
+	_logAutomationExecution.MessagePayload = field.NewString(tableName, "message_payload")
+	_logAutomationExecution.DeadLetterReason = field.NewString(tableName, "dead_letter_reason")
+	_logAutomationExecution.DeadLetteredAt = field.NewTime(tableName, "dead_lettered_at")
+	_logAutomationExecution.ReplayOfLogID = field.NewString(tableName, "replay_of_log_id")
+	_logAutomationExecution.ReplayedByLogID = field.NewString(tableName, "replayed_by_log_id")
+	_logAutomationExecution.ClaimedUntil = field.NewTime(tableName, "claimed_until")
+	_logAutomationExecution.TargetTotal = field.NewInt32(tableName, "target_total")
+	_logAutomationExecution.TargetSucceeded = field.NewInt32(tableName, "target_succeeded")
+	_logAutomationExecution.TargetFailed = field.NewInt32(tableName, "target_failed")
+	_logAutomationExecution.TargetSkipped = field.NewInt32(tableName, "target_skipped")
 
 	_logAutomationExecution.fillFieldMap()
 
@@ -44,14 +60,30 @@ func newLogAutomationExecution(db *gorm.DB, opts ...gen.DOOption) logAutomationE
 type logAutomationExecution struct {
 	logAutomationExecutionDo logAutomationExecutionDo
 
-	ALL            field.Asterisk
-	LogID          field.String
-	AutomationID   field.String
-	Status         field.String
-	TriggeredAt    field.Time
-	FinishedAt     field.Time
-	ConfigSnapshot field.String
-	ErrorMessage   field.String
+	ALL              field.Asterisk
+	LogID            field.String
+	AutomationID     field.String
+	Status           field.String
+	TriggeredAt      field.Time
+	FinishedAt       field.Time
+	ConfigSnapshot   field.String
+	ErrorMessage     field.String
+	ScheduledFor     field.Time
+	IsCatchUp        field.Bool
+	TriggerType      field.String
+	TriggeredBy      field.String
+	IsDryRun         field.Bool
+	Attempt          field.Int32
+	MessagePayload   field.String
+	DeadLetterReason field.String
+	DeadLetteredAt   field.Time
+	ReplayOfLogID    field.String
+	ReplayedByLogID  field.String
+	ClaimedUntil     field.Time
+	TargetTotal      field.Int32
+	TargetSucceeded  field.Int32
+	TargetFailed     field.Int32
+	TargetSkipped    field.Int32
 
 	fieldMap map[string]field.Expr
 }
@@ -75,6 +107,22 @@ func (l *logAutomationExecution) updateTableName(table string) *logAutomationExe
 	l.FinishedAt = field.NewTime(table, "finished_at")
 	l.ConfigSnapshot = field.NewString(table, "config_snapshot")
 	l.ErrorMessage = field.NewString(table, "error_message")
+	l.ScheduledFor = field.NewTime(table, "scheduled_for")
+	l.IsCatchUp = field.NewBool(table, "is_catch_up")
+	l.TriggerType = field.NewString(table, "trigger_type")
+	l.TriggeredBy = field.NewString(table, "triggered_by")
+	l.IsDryRun = field.NewBool(table, "is_dry_run")
+	l.Attempt = field.NewInt32(table, "attempt")
+	l.MessagePayload = field.NewString(table, "message_payload")
+	l.DeadLetterReason = field.NewString(table, "dead_letter_reason")
+	l.DeadLetteredAt = field.NewTime(table, "dead_lettered_at")
+	l.ReplayOfLogID = field.NewString(table, "replay_of_log_id")
+	l.ReplayedByLogID = field.NewString(table, "replayed_by_log_id")
+	l.ClaimedUntil = field.NewTime(table, "claimed_until")
+	l.TargetTotal = field.NewInt32(table, "target_total")
+	l.TargetSucceeded = field.NewInt32(table, "target_succeeded")
+	l.TargetFailed = field.NewInt32(table, "target_failed")
+	l.TargetSkipped = field.NewInt32(table, "target_skipped")
 
 	l.fillFieldMap()
 
@@ -103,7 +151,7 @@ func (l *logAutomationExecution) GetFieldByName(fieldName string) (field.OrderEx
 }
 
 func (l *logAutomationExecution) fillFieldMap() {
-	l.fieldMap = make(map[string]field.Expr, 7)
+	l.fieldMap = make(map[string]field.Expr, 23)
 	l.fieldMap["log_id"] = l.LogID
 	l.fieldMap["automation_id"] = l.AutomationID
 	l.fieldMap["status"] = l.Status
@@ -111,6 +159,22 @@ func (l *logAutomationExecution) fillFieldMap() {
 	l.fieldMap["finished_at"] = l.FinishedAt
 	l.fieldMap["config_snapshot"] = l.ConfigSnapshot
 	l.fieldMap["error_message"] = l.ErrorMessage
+	l.fieldMap["scheduled_for"] = l.ScheduledFor
+	l.fieldMap["is_catch_up"] = l.IsCatchUp
+	l.fieldMap["trigger_type"] = l.TriggerType
+	l.fieldMap["triggered_by"] = l.TriggeredBy
+	l.fieldMap["is_dry_run"] = l.IsDryRun
+	l.fieldMap["attempt"] = l.Attempt
+	l.fieldMap["message_payload"] = l.MessagePayload
+	l.fieldMap["dead_letter_reason"] = l.DeadLetterReason
+	l.fieldMap["dead_lettered_at"] = l.DeadLetteredAt
+	l.fieldMap["replay_of_log_id"] = l.ReplayOfLogID
+	l.fieldMap["replayed_by_log_id"] = l.ReplayedByLogID
+	l.fieldMap["claimed_until"] = l.ClaimedUntil
+	l.fieldMap["target_total"] = l.TargetTotal
+	l.fieldMap["target_succeeded"] = l.TargetSucceeded
+	l.fieldMap["target_failed"] = l.TargetFailed
+	l.fieldMap["target_skipped"] = l.TargetSkipped
 }
 
 func (l logAutomationExecution) clone(db *gorm.DB) logAutomationExecution {
@@ -37,6 +37,8 @@ func newRunAutomationAction(db *gorm.DB, opts ...gen.DOOption) runAutomationActi
 	_runAutomationAction.CreatedBy = field.NewString(tableName, "created_by")
 	_runAutomationAction.LastUpd = field.NewTime(tableName, "last_upd")
 	_runAutomationAction.LastUpdBy = field.NewString(tableName, "last_upd_by")
+	_runAutomationAction.ExecutionMode = field.NewString(tableName, "execution_mode")
+	_runAutomationAction.FanOutConcurrency = field.NewInt32(tableName, "fan_out_concurrency")
 
 	_runAutomationAction.fillFieldMap()
 
@@ -56,6 +58,8 @@ type runAutomationAction struct {
 	CreatedBy          field.String
 	LastUpd            field.Time
 	LastUpdBy          field.String
+	ExecutionMode      field.String
+	FanOutConcurrency  field.Int32
 
 	fieldMap map[string]field.Expr
 }
@@ -81,6 +85,8 @@ func (r *runAutomationAction) updateTableName(table string) *runAutomationAction
 	r.CreatedBy = field.NewString(table, "created_by")
 	r.LastUpd = field.NewTime(table, "last_upd")
 	r.LastUpdBy = field.NewString(table, "last_upd_by")
+	r.ExecutionMode = field.NewString(table, "execution_mode")
+	r.FanOutConcurrency = field.NewInt32(table, "fan_out_concurrency")
 
 	r.fillFieldMap()
 
@@ -109,7 +115,7 @@ func (r *runAutomationAction) GetFieldByName(fieldName string) (field.OrderExpr,
 }
 
 func (r *runAutomationAction) fillFieldMap() {
-	r.fieldMap = make(map[string]field.Expr, 9)
+	r.fieldMap = make(map[string]field.Expr, 11)
 	r.fieldMap["automation_action_id"] = r.AutomationActionID
 	r.fieldMap["automation_id"] = r.AutomationID
 	r.fieldMap["action_id"] = r.ActionID
@@ -119,6 +125,8 @@ func (r *runAutomationAction) fillFieldMap() {
 	r.fieldMap["created_by"] = r.CreatedBy
 	r.fieldMap["last_upd"] = r.LastUpd
 	r.fieldMap["last_upd_by"] = r.LastUpdBy
+	r.fieldMap["execution_mode"] = r.ExecutionMode
+	r.fieldMap["fan_out_concurrency"] = r.FanOutConcurrency
 }
 
 func (r runAutomationAction) clone(db *gorm.DB) runAutomationAction {
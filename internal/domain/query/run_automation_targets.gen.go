@@ -47,6 +47,7 @@ func newRunAutomationTarget(db *gorm.DB, opts ...gen.DOOption) runAutomationTarg
 	_runAutomationTarget.CreatedBy = field.NewString(tableName, "created_by")
 	_runAutomationTarget.LastUpd = field.NewTime(tableName, "last_upd")
 	_runAutomationTarget.LastUpdBy = field.NewString(tableName, "last_upd_by")
+	_runAutomationTarget.TargetMode = field.NewString(tableName, "target_mode")
 
 	_runAutomationTarget.fillFieldMap()
 
@@ -76,6 +77,7 @@ type runAutomationTarget struct {
 	CreatedBy          field.String
 	LastUpd            field.Time
 	LastUpdBy          field.String
+	TargetMode         field.String
 
 	fieldMap map[string]field.Expr
 }
@@ -111,6 +113,7 @@ func (r *runAutomationTarget) updateTableName(table string) *runAutomationTarget
 	r.CreatedBy = field.NewString(table, "created_by")
 	r.LastUpd = field.NewTime(table, "last_upd")
 	r.LastUpdBy = field.NewString(table, "last_upd_by")
+	r.TargetMode = field.NewString(table, "target_mode")
 
 	r.fillFieldMap()
 
@@ -139,7 +142,7 @@ func (r *runAutomationTarget) GetFieldByName(fieldName string) (field.OrderExpr,
 }
 
 func (r *runAutomationTarget) fillFieldMap() {
-	r.fieldMap = make(map[string]field.Expr, 19)
+	r.fieldMap = make(map[string]field.Expr, 20)
 	r.fieldMap["automation_target_id"] = r.AutomationTargetID
 	r.fieldMap["automation_id"] = r.AutomationID
 	r.fieldMap["company_id"] = r.CompanyID
@@ -159,6 +162,7 @@ func (r *runAutomationTarget) fillFieldMap() {
 	r.fieldMap["created_by"] = r.CreatedBy
 	r.fieldMap["last_upd"] = r.LastUpd
 	r.fieldMap["last_upd_by"] = r.LastUpdBy
+	r.fieldMap["target_mode"] = r.TargetMode
 }
 
 func (r runAutomationTarget) clone(db *gorm.DB) runAutomationTarget {
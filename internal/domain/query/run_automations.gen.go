@@ -44,6 +44,17 @@ func newRunAutomation(db *gorm.DB, opts ...gen.DOOption) runAutomation {
 	_runAutomation.CreatedBy = field.NewString(tableName, "created_by")
 	_runAutomation.LastUpd = field.NewTime(tableName, "last_upd")
 	_runAutomation.LastUpdBy = field.NewString(tableName, "last_upd_by")
+	_runAutomation.LockedBy = field.NewString(tableName, "locked_by")
+	_runAutomation.LockExpiresAt = field.NewTime(tableName, "lock_expires_at")
+	_runAutomation.MisfirePolicy = field.NewString(tableName, "misfire_policy")
+	_runAutomation.CronExpression = field.NewString(tableName, "cron_expression")
+	_runAutomation.TimeZone = field.NewString(tableName, "time_zone")
+	_runAutomation.EndDate = field.NewTime(tableName, "end_date")
+	_runAutomation.MaxRuns = field.NewInt32(tableName, "max_runs")
+	_runAutomation.RunCount = field.NewInt32(tableName, "run_count")
+	_runAutomation.BlackoutCalendar = field.NewString(tableName, "blackout_calendar")
+	_runAutomation.BlackoutRule = field.NewString(tableName, "blackout_rule")
+	_runAutomation.LifecycleState = field.NewString(tableName, "lifecycle_state")
 
 	_runAutomation.fillFieldMap()
 
@@ -70,6 +81,17 @@ type runAutomation struct {
 	CreatedBy               field.String
 	LastUpd                 field.Time
 	LastUpdBy               field.String
+	LockedBy                field.String
+	LockExpiresAt           field.Time
+	MisfirePolicy           field.String
+	CronExpression          field.String
+	TimeZone                field.String
+	EndDate                 field.Time
+	MaxRuns                 field.Int32
+	RunCount                field.Int32
+	BlackoutCalendar        field.String
+	BlackoutRule            field.String
+	LifecycleState          field.String
 
 	fieldMap map[string]field.Expr
 }
@@ -102,6 +124,17 @@ func (r *runAutomation) updateTableName(table string) *runAutomation {
 	r.CreatedBy = field.NewString(table, "created_by")
 	r.LastUpd = field.NewTime(table, "last_upd")
 	r.LastUpdBy = field.NewString(table, "last_upd_by")
+	r.LockedBy = field.NewString(table, "locked_by")
+	r.LockExpiresAt = field.NewTime(table, "lock_expires_at")
+	r.MisfirePolicy = field.NewString(table, "misfire_policy")
+	r.CronExpression = field.NewString(table, "cron_expression")
+	r.TimeZone = field.NewString(table, "time_zone")
+	r.EndDate = field.NewTime(table, "end_date")
+	r.MaxRuns = field.NewInt32(table, "max_runs")
+	r.RunCount = field.NewInt32(table, "run_count")
+	r.BlackoutCalendar = field.NewString(table, "blackout_calendar")
+	r.BlackoutRule = field.NewString(table, "blackout_rule")
+	r.LifecycleState = field.NewString(table, "lifecycle_state")
 
 	r.fillFieldMap()
 
@@ -130,7 +163,7 @@ func (r *runAutomation) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (r *runAutomation) fillFieldMap() {
-	r.fieldMap = make(map[string]field.Expr, 16)
+	r.fieldMap = make(map[string]field.Expr, 27)
 	r.fieldMap["automation_id"] = r.AutomationID
 	r.fieldMap["instance_server_id"] = r.InstanceServerID
 	r.fieldMap["instance_server_channel_id"] = r.InstanceServerChannelID
@@ -147,6 +180,17 @@ func (r *runAutomation) fillFieldMap() {
 	r.fieldMap["created_by"] = r.CreatedBy
 	r.fieldMap["last_upd"] = r.LastUpd
 	r.fieldMap["last_upd_by"] = r.LastUpdBy
+	r.fieldMap["locked_by"] = r.LockedBy
+	r.fieldMap["lock_expires_at"] = r.LockExpiresAt
+	r.fieldMap["misfire_policy"] = r.MisfirePolicy
+	r.fieldMap["cron_expression"] = r.CronExpression
+	r.fieldMap["time_zone"] = r.TimeZone
+	r.fieldMap["end_date"] = r.EndDate
+	r.fieldMap["max_runs"] = r.MaxRuns
+	r.fieldMap["run_count"] = r.RunCount
+	r.fieldMap["blackout_calendar"] = r.BlackoutCalendar
+	r.fieldMap["blackout_rule"] = r.BlackoutRule
+	r.fieldMap["lifecycle_state"] = r.LifecycleState
 }
 
 func (r runAutomation) clone(db *gorm.DB) runAutomation {
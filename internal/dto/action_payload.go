@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"automation-engine/internal/directory"
+	"automation-engine/internal/domain/model"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Reserved keys of an action payload. The worker always sets them and they override the same keys in ConfigJSON.
+const (
+	PayloadKeyLogID              = "log_id"
+	PayloadKeyAutomationActionID = "automation_action_id"
+	PayloadKeyAutomation         = "automation"
+	PayloadKeyTargets            = "targets"
+	PayloadKeyRecipients         = "recipients"
+	PayloadKeyRecipient          = "recipient"
+	PayloadKeyTriggeredAt        = "triggered_at"
+	PayloadKeyOutputs            = "outputs"
+)
+
+// ActionContext is the automation context merged into every action payload
+type ActionContext struct {
+	LogID      string
+	Automation *model.RunAutomation
+	Targets    []*model.RunAutomationTarget
+	// Recipients are the employees resolved from Targets (nil when no directory provider is configured)
+	Recipients []directory.Recipient
+	// Recipient is the single employee of a fan-out sub-execution
+	Recipient   *directory.Recipient
+	TriggeredAt time.Time
+	// Outputs is the execution context: the decoded responses of the actions called before this one (in SortOrder),
+	// keyed by automation_action_id. Every step records the context it was called with.
+	Outputs map[string]interface{}
+}
+
+// ParseActionConfig decodes RunAutomationAction.ConfigJSON. An empty config is an empty object.
+func ParseActionConfig(configJSON string) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	if configJSON == "" {
+		return config, nil
+	}
+
+	if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+		return nil, fmt.Errorf("config_json must be a JSON object: %w", err)
+	}
+	if config == nil {
+		// "null"
+		config = map[string]interface{}{}
+	}
+	return config, nil
+}
+
+// BuildActionPayload builds the request body of an action call. The payload template (ConfigJSON payload_template,
+// else DefAction.PayloadTemplate) renders the whole body when there is one, otherwise ConfigJSON is merged with the automation context.
+func BuildActionPayload(defAction *model.DefAction, action *model.RunAutomationAction, actionCtx ActionContext) ([]byte, error) {
+	payload, err := ParseActionConfig(action.ConfigJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := ConfigPayloadTemplate(payload)
+	if err != nil {
+		return nil, err
+	}
+	delete(payload, ConfigKeyPayloadTemplate)
+	if tmpl == "" && defAction != nil {
+		tmpl = defAction.PayloadTemplate
+	}
+
+	if tmpl != "" {
+		return RenderPayloadTemplate(tmpl, TemplateData{
+			LogID:              actionCtx.LogID,
+			AutomationActionID: action.AutomationActionID,
+			Automation:         actionCtx.Automation,
+			TriggeredAt:        actionCtx.TriggeredAt,
+			Targets:            actionCtx.Targets,
+			Recipients:         actionCtx.Recipients,
+			Recipient:          actionCtx.Recipient,
+			Config:             payload,
+			Outputs:            actionCtx.Outputs,
+		})
+	}
+
+	payload[PayloadKeyLogID] = actionCtx.LogID
+	payload[PayloadKeyAutomationActionID] = action.AutomationActionID
+	payload[PayloadKeyAutomation] = actionCtx.Automation
+	payload[PayloadKeyTargets] = actionCtx.Targets
+	if actionCtx.Recipients != nil {
+		payload[PayloadKeyRecipients] = actionCtx.Recipients
+	}
+	if actionCtx.Recipient != nil {
+		payload[PayloadKeyRecipient] = actionCtx.Recipient
+	}
+	payload[PayloadKeyTriggeredAt] = actionCtx.TriggeredAt
+	if len(actionCtx.Outputs) > 0 {
+		payload[PayloadKeyOutputs] = actionCtx.Outputs
+	}
+
+	return json.Marshal(payload)
+}
@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"automation-engine/internal/directory"
+	"automation-engine/internal/domain/model"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ConfigKeyPayloadTemplate is the ConfigJSON key that overrides DefAction.PayloadTemplate for one automation action
+const ConfigKeyPayloadTemplate = "payload_template"
+
+// TemplateData is what a payload template can read, e.g.
+//
+//	{"title": {{ json .Automation.AutomationName }}, "to": {{ json .Recipient.Email }}, "doc": {{ json (index .Outputs "AA001").document_id }}}
+type TemplateData struct {
+	LogID              string
+	AutomationActionID string
+	Automation         *model.RunAutomation
+	TriggeredAt        time.Time
+	Targets            []*model.RunAutomationTarget
+	Recipients         []directory.Recipient
+	// Recipient is only set for a fan-out call
+	Recipient *directory.Recipient
+	// Config is ConfigJSON without payload_template
+	Config map[string]interface{}
+	// Outputs are the decoded responses of the earlier actions, keyed by automation_action_id
+	Outputs map[string]interface{}
+}
+
+var templateFuncs = template.FuncMap{
+	// json writes any value as a JSON literal (quoted string, number, object, null)
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	// default returns fallback when v is empty
+	"default": func(fallback interface{}, v interface{}) interface{} {
+		if v == nil || v == "" {
+			return fallback
+		}
+		return v
+	},
+	// formatTime formats t with a Go layout, e.g. formatTime "2006-01-02" .TriggeredAt
+	"formatTime": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// ParsePayloadTemplate checks the syntax of a payload template
+func ParsePayloadTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("payload").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// RenderPayloadTemplate executes the template and checks that the result is JSON
+func RenderPayloadTemplate(text string, data TemplateData) ([]byte, error) {
+	tmpl, err := ParsePayloadTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render payload template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("payload template must render valid JSON, got: %s", buf.String())
+	}
+	return buf.Bytes(), nil
+}
+
+// ConfigPayloadTemplate returns the payload_template of a parsed ConfigJSON ("" when not set)
+func ConfigPayloadTemplate(config map[string]interface{}) (string, error) {
+	value, ok := config[ConfigKeyPayloadTemplate]
+	if !ok || value == nil {
+		return "", nil
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", ConfigKeyPayloadTemplate)
+	}
+	return text, nil
+}
@@ -0,0 +1,150 @@
+package dto
+
+import (
+	"automation-engine/internal/directory"
+	"automation-engine/internal/domain/model"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func decodePayload(t *testing.T, body []byte) map[string]interface{} {
+	t.Helper()
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("payload is not a JSON object: %v (%s)", err, body)
+	}
+	return payload
+}
+
+func TestRenderPayloadTemplate(t *testing.T) {
+	data := TemplateData{
+		LogID:              "LOG001",
+		AutomationActionID: "AA002",
+		Automation:         &model.RunAutomation{AutomationID: "AUTO001", AutomationName: `Daily "report"`},
+		TriggeredAt:        time.Date(2024, 1, 15, 2, 0, 0, 0, time.UTC),
+		Recipient:          &directory.Recipient{EmployeeID: "E001", Email: "e001@example.com"},
+		Config:             map[string]interface{}{"channel": "email"},
+		Outputs:            map[string]interface{}{"AA001": map[string]interface{}{"document_id": "DOC9"}},
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "json escapes values",
+			text: `{"title": {{ json .Automation.AutomationName }}, "log": {{ json .LogID }}}`,
+			want: map[string]interface{}{"title": `Daily "report"`, "log": "LOG001"},
+		},
+		{
+			name: "recipient, config and earlier outputs",
+			text: `{"to": {{ json .Recipient.Email }}, "channel": {{ json .Config.channel }}, "doc": {{ json (index .Outputs "AA001").document_id }}}`,
+			want: map[string]interface{}{"to": "e001@example.com", "channel": "email", "doc": "DOC9"},
+		},
+		{
+			name: "helper functions",
+			text: `{"day": {{ json (formatTime "2006-01-02" .TriggeredAt) }}, "channel": {{ json (upper .Config.channel) }}, "priority": {{ json (default "normal" .Config.priority) }}}`,
+			want: map[string]interface{}{"day": "2024-01-15", "channel": "EMAIL", "priority": "normal"},
+		},
+		{name: "syntax error", text: `{"title": {{ .Automation.AutomationName }`, wantErr: true},
+		{name: "execution error", text: `{"x": {{ json (index .Outputs "AA001").document_id.missing }}}`, wantErr: true},
+		{name: "not JSON", text: `{"title": {{ .Automation.AutomationName }}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := RenderPayloadTemplate(tt.text, data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("RenderPayloadTemplate() = %s, want error", body)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := decodePayload(t, body)
+			for key, want := range tt.want {
+				if got[key] != want {
+					t.Errorf("%s = %v, want %v", key, got[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigPayloadTemplate(t *testing.T) {
+	if text, err := ConfigPayloadTemplate(map[string]interface{}{}); err != nil || text != "" {
+		t.Errorf("missing key = %q, %v", text, err)
+	}
+	if text, err := ConfigPayloadTemplate(map[string]interface{}{ConfigKeyPayloadTemplate: `{}`}); err != nil || text != `{}` {
+		t.Errorf("string key = %q, %v", text, err)
+	}
+	if _, err := ConfigPayloadTemplate(map[string]interface{}{ConfigKeyPayloadTemplate: 1}); err == nil {
+		t.Error("non-string payload_template accepted")
+	}
+}
+
+func TestBuildActionPayload(t *testing.T) {
+	actionCtx := ActionContext{
+		LogID:       "LOG001",
+		Automation:  &model.RunAutomation{AutomationID: "AUTO001", AutomationName: "Report"},
+		TriggeredAt: time.Date(2024, 1, 15, 2, 0, 0, 0, time.UTC),
+	}
+	defAction := &model.DefAction{ActionID: "ACT001", PayloadTemplate: `{"source": "definition", "log": {{ json .LogID }}}`}
+
+	t.Run("definition template", func(t *testing.T) {
+		action := &model.RunAutomationAction{AutomationActionID: "AA001", ConfigJSON: `{"channel": "email"}`}
+		body, err := BuildActionPayload(defAction, action, actionCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := decodePayload(t, body)
+		if got["source"] != "definition" || got["log"] != "LOG001" || len(got) != 2 {
+			t.Errorf("payload = %v", got)
+		}
+	})
+
+	t.Run("config template overrides definition", func(t *testing.T) {
+		action := &model.RunAutomationAction{
+			AutomationActionID: "AA001",
+			ConfigJSON:         `{"channel": "email", "payload_template": "{\"source\": \"config\", \"config\": {{ json .Config }}}"}`,
+		}
+		body, err := BuildActionPayload(defAction, action, actionCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := decodePayload(t, body)
+		config, _ := got["config"].(map[string]interface{})
+		if got["source"] != "config" || config["channel"] != "email" {
+			t.Errorf("payload = %v", got)
+		}
+		if _, ok := config[ConfigKeyPayloadTemplate]; ok {
+			t.Error("payload_template leaked into .Config")
+		}
+	})
+
+	t.Run("merge without template", func(t *testing.T) {
+		action := &model.RunAutomationAction{AutomationActionID: "AA001", ConfigJSON: `{"channel": "email", "log_id": "spoofed"}`}
+		body, err := BuildActionPayload(&model.DefAction{ActionID: "ACT001"}, action, actionCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := decodePayload(t, body)
+		if got["channel"] != "email" || got[PayloadKeyLogID] != "LOG001" || got[PayloadKeyAutomationActionID] != "AA001" {
+			t.Errorf("payload = %v", got)
+		}
+		if _, ok := got[PayloadKeyRecipients]; ok {
+			t.Error("recipients set without a directory")
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		action := &model.RunAutomationAction{AutomationActionID: "AA001", ConfigJSON: `[1, 2]`}
+		if _, err := BuildActionPayload(defAction, action, actionCtx); err == nil {
+			t.Error("non-object config_json accepted")
+		}
+	})
+}
@@ -5,10 +5,37 @@ import (
 	"time"
 )
 
+// Trigger types of a run (log_automation_executions.trigger_type)
+const (
+	TriggerSchedule = "SCHEDULE"
+	TriggerManual   = "MANUAL"
+	TriggerReplay   = "REPLAY"
+)
+
 type MessageServiceBus struct {
 	LogID        string    `json:"log_id" validate:"required"`
 	AutomationID string    `json:"automation_id" validate:"required"`
 	TriggeredAt  time.Time `json:"triggered_at" validate:"required"`
+	// ScheduledFor is the fire time the run belongs to, IsCatchUp marks a run fired late by the misfire policy
+	ScheduledFor time.Time `json:"scheduled_for"`
+	IsCatchUp    bool      `json:"is_catch_up"`
+	// TriggerType is SCHEDULE (empty for messages sent before manual triggers existed), MANUAL or REPLAY
+	TriggerType string `json:"trigger_type"`
+	TriggeredBy string `json:"triggered_by"`
+	// DryRun evaluates conditions and renders payloads but does not call the actions
+	DryRun bool `json:"dry_run"`
+	// Attempt is the 1-based execution attempt, retries re-send the message with the next attempt (0 = first)
+	Attempt int32 `json:"attempt"`
+	// ReplayOfLogID is the dead-lettered execution this message replays
+	ReplayOfLogID string `json:"replay_of_log_id"`
+}
+
+// CurrentAttempt returns Attempt with messages sent before retries existed counted as the first attempt
+func (m *MessageServiceBus) CurrentAttempt() int32 {
+	if m.Attempt < 1 {
+		return 1
+	}
+	return m.Attempt
 }
 
 func (m *MessageServiceBus) Validate() error {
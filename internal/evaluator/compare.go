@@ -0,0 +1,112 @@
+package evaluator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Compare applies a def_operators symbol to the left (resolved) and right (configured) values.
+// Values are compared as numbers when both sides are numeric, then as dates, otherwise as strings.
+func Compare(left, symbol, right string) (bool, error) {
+	left = strings.TrimSpace(left)
+	right = strings.TrimSpace(right)
+
+	switch strings.ToLower(strings.TrimSpace(symbol)) {
+	case "=", "==":
+		return compareOrdered(left, right, func(c int) bool { return c == 0 })
+	case "!=", "<>":
+		return compareOrdered(left, right, func(c int) bool { return c != 0 })
+	case ">":
+		return compareOrdered(left, right, func(c int) bool { return c > 0 })
+	case ">=":
+		return compareOrdered(left, right, func(c int) bool { return c >= 0 })
+	case "<":
+		return compareOrdered(left, right, func(c int) bool { return c < 0 })
+	case "<=":
+		return compareOrdered(left, right, func(c int) bool { return c <= 0 })
+	case "in":
+		return inList(left, right), nil
+	case "not in":
+		return !inList(left, right), nil
+	case "contains":
+		return strings.Contains(strings.ToLower(left), strings.ToLower(right)), nil
+	case "not contains":
+		return !strings.Contains(strings.ToLower(left), strings.ToLower(right)), nil
+	case "between":
+		bounds := splitList(right)
+		if len(bounds) != 2 {
+			return false, fmt.Errorf("between expects 2 values, got %q", right)
+		}
+		lower, err := compareOrdered(left, bounds[0], func(c int) bool { return c >= 0 })
+		if err != nil || !lower {
+			return false, err
+		}
+		return compareOrdered(left, bounds[1], func(c int) bool { return c <= 0 })
+	default:
+		return false, fmt.Errorf("unsupported operator symbol: %s", symbol)
+	}
+}
+
+func compareOrdered(left, right string, match func(int) bool) (bool, error) {
+	if l, err := strconv.ParseFloat(left, 64); err == nil {
+		if r, err := strconv.ParseFloat(right, 64); err == nil {
+			return match(compareFloat(l, r)), nil
+		}
+	}
+
+	if l, ok := parseDate(left); ok {
+		if r, ok := parseDate(right); ok {
+			return match(l.Compare(r)), nil
+		}
+	}
+
+	return match(strings.Compare(left, right)), nil
+}
+
+func compareFloat(l, r float64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func parseDate(v string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func inList(left, right string) bool {
+	for _, item := range splitList(right) {
+		if ok, _ := compareOrdered(left, item, func(c int) bool { return c == 0 }); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(v string) []string {
+	parts := strings.Split(v, ",")
+	items := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			items = append(items, p)
+		}
+	}
+	return items
+}
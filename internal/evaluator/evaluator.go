@@ -0,0 +1,203 @@
+package evaluator
+
+import (
+	"automation-engine/internal/directory"
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/dto"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrNoResolver   = errors.New("no condition value resolver configured")
+	ErrUnknownGroup = errors.New("unknown condition group")
+)
+
+// ResolveInput describes which left-hand value the resolver has to fetch
+type ResolveInput struct {
+	Automation *model.RunAutomation
+	Condition  *model.RunAutomationCondition
+	Targets    []*model.RunAutomationTarget
+	// Recipient is the employee being evaluated by a fan-out action (nil for the automation as a whole)
+	Recipient *directory.Recipient
+}
+
+// ValueResolver fetches the current (left-hand) value of a condition
+type ValueResolver interface {
+	Resolve(ctx context.Context, input ResolveInput) (string, error)
+}
+
+type Evaluator struct {
+	operators map[string]string // operator_id -> operator_symbol
+	resolver  ValueResolver
+}
+
+func New(operators []*model.DefOperator, resolver ValueResolver) *Evaluator {
+	symbols := make(map[string]string, len(operators))
+	for _, op := range operators {
+		symbols[op.OperatorID] = op.OperatorSymbol
+	}
+
+	return &Evaluator{
+		operators: symbols,
+		resolver:  resolver,
+	}
+}
+
+// Evaluate returns true when the condition groups of the snapshot allow the automation to run.
+// An automation without any condition always runs.
+func (e *Evaluator) Evaluate(ctx context.Context, snapshot *dto.AutomationSnapshot) (bool, error) {
+	return e.evaluate(ctx, snapshot, nil)
+}
+
+// EvaluateFor evaluates the condition groups for one recipient of a fan-out action.
+// The recipient is passed to the resolver so providers can return the value of that employee.
+func (e *Evaluator) EvaluateFor(ctx context.Context, snapshot *dto.AutomationSnapshot, recipient *directory.Recipient) (bool, error) {
+	return e.evaluate(ctx, snapshot, recipient)
+}
+
+func (e *Evaluator) evaluate(ctx context.Context, snapshot *dto.AutomationSnapshot, recipient *directory.Recipient) (bool, error) {
+	if len(snapshot.Conditions) == 0 {
+		return true, nil
+	}
+
+	groups := make([]*model.RunAutomationConditionGroup, len(snapshot.ConditionGroups))
+	copy(groups, snapshot.ConditionGroups)
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].SortOrder < groups[j].SortOrder
+	})
+
+	knownGroups := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		knownGroups[group.AutomationConditionGroupID] = true
+	}
+
+	conditionsByGroup := make(map[string][]*model.RunAutomationCondition)
+	for _, cond := range snapshot.Conditions {
+		if !knownGroups[cond.AutomationConditionGroupID] {
+			return false, fmt.Errorf("condition %s: %w %s", cond.AutomationConditionID, ErrUnknownGroup, cond.AutomationConditionGroupID)
+		}
+		conditionsByGroup[cond.AutomationConditionGroupID] = append(conditionsByGroup[cond.AutomationConditionGroupID], cond)
+	}
+
+	// กลุ่มที่ไม่มีเงื่อนไขถูกข้าม จึงนับจากกลุ่มที่ประเมินจริง ไม่ใช่ index
+	result, evaluated := true, false
+	for _, group := range groups {
+		conditions := conditionsByGroup[group.AutomationConditionGroupID]
+		if len(conditions) == 0 {
+			continue
+		}
+
+		op, err := logicalOperator(group.GroupOperator)
+		if err != nil {
+			return false, fmt.Errorf("condition group %s: %w", group.AutomationConditionGroupID, err)
+		}
+
+		// กลุ่มแรกไม่มีตัวเชื่อมกับกลุ่มก่อนหน้า
+		if evaluated && shortCircuit(op, result) {
+			continue
+		}
+
+		groupResult, err := e.evaluateGroup(ctx, snapshot, recipient, conditions)
+		if err != nil {
+			return false, err
+		}
+
+		if !evaluated {
+			result = groupResult
+			evaluated = true
+		} else {
+			result = combine(op, result, groupResult)
+		}
+	}
+
+	return result, nil
+}
+
+// evaluateGroup folds the conditions of one group from left to right using each condition's ComparisonOperator
+func (e *Evaluator) evaluateGroup(ctx context.Context, snapshot *dto.AutomationSnapshot, recipient *directory.Recipient, conditions []*model.RunAutomationCondition) (bool, error) {
+	sorted := make([]*model.RunAutomationCondition, len(conditions))
+	copy(sorted, conditions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].AutomationConditionID < sorted[j].AutomationConditionID
+	})
+
+	result := true
+	for i, cond := range sorted {
+		op, err := logicalOperator(cond.ComparisonOperator)
+		if err != nil {
+			return false, fmt.Errorf("condition %s: %w", cond.AutomationConditionID, err)
+		}
+
+		if i > 0 && shortCircuit(op, result) {
+			continue
+		}
+
+		condResult, err := e.evaluateCondition(ctx, snapshot, recipient, cond)
+		if err != nil {
+			return false, err
+		}
+
+		if i == 0 {
+			result = condResult
+		} else {
+			result = combine(op, result, condResult)
+		}
+	}
+
+	return result, nil
+}
+
+func (e *Evaluator) evaluateCondition(ctx context.Context, snapshot *dto.AutomationSnapshot, recipient *directory.Recipient, cond *model.RunAutomationCondition) (bool, error) {
+	symbol, ok := e.operators[cond.OperatorID]
+	if !ok {
+		return false, fmt.Errorf("condition %s: unknown operator_id %s", cond.AutomationConditionID, cond.OperatorID)
+	}
+
+	if e.resolver == nil {
+		return false, ErrNoResolver
+	}
+
+	left, err := e.resolver.Resolve(ctx, ResolveInput{
+		Automation: snapshot.Automation,
+		Condition:  cond,
+		Targets:    snapshot.Targets,
+		Recipient:  recipient,
+	})
+	if err != nil {
+		return false, fmt.Errorf("condition %s: failed to resolve value: %w", cond.AutomationConditionID, err)
+	}
+
+	matched, err := Compare(left, symbol, cond.Value)
+	if err != nil {
+		return false, fmt.Errorf("condition %s: %w", cond.AutomationConditionID, err)
+	}
+
+	return matched, nil
+}
+
+func logicalOperator(op string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(op)) {
+	case "", "AND", "&&":
+		return "AND", nil
+	case "OR", "||":
+		return "OR", nil
+	default:
+		return "", fmt.Errorf("unsupported logical operator: %s", op)
+	}
+}
+
+// shortCircuit reports whether the next operand can no longer change the result
+func shortCircuit(op string, current bool) bool {
+	return (op == "AND" && !current) || (op == "OR" && current)
+}
+
+func combine(op string, left, right bool) bool {
+	if op == "OR" {
+		return left || right
+	}
+	return left && right
+}
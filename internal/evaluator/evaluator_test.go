@@ -0,0 +1,164 @@
+package evaluator
+
+import (
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/dto"
+	"context"
+	"errors"
+	"testing"
+)
+
+// stubResolver returns the value configured per condition_id
+type stubResolver map[string]string
+
+func (r stubResolver) Resolve(_ context.Context, input ResolveInput) (string, error) {
+	return r[input.Condition.ConditionID], nil
+}
+
+func group(id string, operator string, sortOrder int32) *model.RunAutomationConditionGroup {
+	return &model.RunAutomationConditionGroup{AutomationConditionGroupID: id, GroupOperator: operator, SortOrder: sortOrder}
+}
+
+// cond matches when the resolver returns "1" for conditionID
+func cond(id string, groupID string, conditionID string, operator string) *model.RunAutomationCondition {
+	return &model.RunAutomationCondition{
+		AutomationConditionID:      id,
+		AutomationConditionGroupID: groupID,
+		ConditionID:                conditionID,
+		OperatorID:                 "EQ",
+		Value:                      "1",
+		ComparisonOperator:         operator,
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	values := stubResolver{"TRUE": "1", "FALSE": "0"}
+
+	tests := []struct {
+		name       string
+		groups     []*model.RunAutomationConditionGroup
+		conditions []*model.RunAutomationCondition
+		want       bool
+		wantErr    error
+	}{
+		{
+			name: "no conditions",
+			want: true,
+		},
+		{
+			name:       "single group",
+			groups:     []*model.RunAutomationConditionGroup{group("G1", "AND", 1)},
+			conditions: []*model.RunAutomationCondition{cond("C1", "G1", "FALSE", "")},
+			want:       false,
+		},
+		{
+			name:       "empty first group then OR group",
+			groups:     []*model.RunAutomationConditionGroup{group("G1", "AND", 1), group("G2", "OR", 2)},
+			conditions: []*model.RunAutomationCondition{cond("C1", "G2", "FALSE", "")},
+			want:       false,
+		},
+		{
+			name:       "empty first group then AND group",
+			groups:     []*model.RunAutomationConditionGroup{group("G1", "OR", 1), group("G2", "AND", 2)},
+			conditions: []*model.RunAutomationCondition{cond("C1", "G2", "TRUE", "")},
+			want:       true,
+		},
+		{
+			name:   "false AND true",
+			groups: []*model.RunAutomationConditionGroup{group("G1", "AND", 1), group("G2", "AND", 2)},
+			conditions: []*model.RunAutomationCondition{
+				cond("C1", "G1", "FALSE", ""),
+				cond("C2", "G2", "TRUE", ""),
+			},
+			want: false,
+		},
+		{
+			name:   "false OR true",
+			groups: []*model.RunAutomationConditionGroup{group("G1", "AND", 1), group("G2", "OR", 2)},
+			conditions: []*model.RunAutomationCondition{
+				cond("C1", "G1", "FALSE", ""),
+				cond("C2", "G2", "TRUE", ""),
+			},
+			want: true,
+		},
+		{
+			name:   "groups follow sort order",
+			groups: []*model.RunAutomationConditionGroup{group("G2", "AND", 2), group("G1", "OR", 1)},
+			conditions: []*model.RunAutomationCondition{
+				cond("C1", "G1", "TRUE", ""),
+				cond("C2", "G2", "FALSE", ""),
+			},
+			want: false,
+		},
+		{
+			name:   "OR inside a group",
+			groups: []*model.RunAutomationConditionGroup{group("G1", "AND", 1)},
+			conditions: []*model.RunAutomationCondition{
+				cond("C1", "G1", "FALSE", ""),
+				cond("C2", "G1", "TRUE", "OR"),
+			},
+			want: true,
+		},
+		{
+			name:   "(true OR false) AND false",
+			groups: []*model.RunAutomationConditionGroup{group("G1", "AND", 1), group("G2", "AND", 2)},
+			conditions: []*model.RunAutomationCondition{
+				cond("C1", "G1", "TRUE", ""),
+				cond("C2", "G1", "FALSE", "OR"),
+				cond("C3", "G2", "FALSE", ""),
+			},
+			want: false,
+		},
+		{
+			name:       "orphan condition",
+			groups:     []*model.RunAutomationConditionGroup{group("G1", "AND", 1)},
+			conditions: []*model.RunAutomationCondition{cond("C1", "G9", "TRUE", "")},
+			wantErr:    ErrUnknownGroup,
+		},
+		{
+			name:       "orphan condition without groups",
+			conditions: []*model.RunAutomationCondition{cond("C1", "G9", "FALSE", "")},
+			wantErr:    ErrUnknownGroup,
+		},
+		{
+			name:       "unsupported group operator",
+			groups:     []*model.RunAutomationConditionGroup{group("G1", "XOR", 1)},
+			conditions: []*model.RunAutomationCondition{cond("C1", "G1", "TRUE", "")},
+			wantErr:    errAny,
+		},
+	}
+
+	e := New([]*model.DefOperator{{OperatorID: "EQ", OperatorSymbol: "="}}, values)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := &dto.AutomationSnapshot{ConditionGroups: tt.groups, Conditions: tt.conditions}
+			got, err := e.Evaluate(context.Background(), snapshot)
+			if tt.wantErr != nil {
+				if err == nil || (tt.wantErr != errAny && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("Evaluate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// errAny accepts any error
+var errAny = errors.New("any error")
+
+func TestEvaluateWithoutResolver(t *testing.T) {
+	e := New([]*model.DefOperator{{OperatorID: "EQ", OperatorSymbol: "="}}, nil)
+	snapshot := &dto.AutomationSnapshot{
+		ConditionGroups: []*model.RunAutomationConditionGroup{group("G1", "AND", 1)},
+		Conditions:      []*model.RunAutomationCondition{cond("C1", "G1", "TRUE", "")},
+	}
+	if _, err := e.Evaluate(context.Background(), snapshot); !errors.Is(err, ErrNoResolver) {
+		t.Fatalf("Evaluate() error = %v, want %v", err, ErrNoResolver)
+	}
+}
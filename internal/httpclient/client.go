@@ -2,39 +2,245 @@ package httpclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
-var client = &http.Client{
-	Timeout: 60 * time.Second,
+// DefaultTimeout applies when the request does not set its own timeout.
+// The client itself has no timeout so a longer per-request timeout is not cut short.
+const DefaultTimeout = 60 * time.Second
+
+var client = &http.Client{}
+
+// Request builds an outgoing HTTP call. The zero timeout falls back to DefaultTimeout.
+type Request struct {
+	method  string
+	url     string
+	query   url.Values
+	headers http.Header
+	body    []byte
+	timeout time.Duration
+}
+
+// Response keeps the raw body and, when the body is a JSON object, the decoded map
+type Response struct {
+	StatusCode int
+	Body       []byte
+	Data       map[string]interface{}
+}
+
+func NewRequest(method, rawURL string) *Request {
+	return &Request{
+		method:  strings.ToUpper(method),
+		url:     rawURL,
+		query:   url.Values{},
+		headers: http.Header{},
+	}
+}
+
+func (r *Request) WithJSONBody(body []byte) *Request {
+	r.body = body
+	r.headers.Set("Content-Type", "application/json")
+	return r
+}
+
+func (r *Request) WithQuery(values url.Values) *Request {
+	for key, vs := range values {
+		for _, v := range vs {
+			r.query.Add(key, v)
+		}
+	}
+	return r
+}
+
+func (r *Request) WithHeader(key, value string) *Request {
+	r.headers.Set(key, value)
+	return r
+}
+
+func (r *Request) WithHeaders(headers map[string]string) *Request {
+	for key, value := range headers {
+		r.headers.Set(key, value)
+	}
+	return r
 }
 
-func PostRequest(url string, body []byte) (int, map[string]interface{}, error) {
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+func (r *Request) WithTimeout(timeout time.Duration) *Request {
+	r.timeout = timeout
+	return r
+}
+
+func (r *Request) Do(ctx context.Context) (*Response, error) {
+	timeout := r.timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	target, err := r.targetURL()
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = r.headers.Clone()
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result map[string]interface{}
-	if resp.ContentLength != 0 {
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			// ตรวจสอบว่า Error ไม่ใช่ EOF (ซึ่งมักเกิดเมื่อ Response Body ว่างเปล่า)
-			if err.Error() != "EOF" {
-				return resp.StatusCode, nil, errors.New("failed to decode response body")
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &Response{StatusCode: resp.StatusCode}, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	result := &Response{
+		StatusCode: resp.StatusCode,
+		Body:       respBody,
+	}
+
+	// Response ที่ไม่ใช่ JSON object (ว่าง, text, array) จะไม่มี Data
+	if len(bytes.TrimSpace(respBody)) > 0 {
+		var data map[string]interface{}
+		if err := json.Unmarshal(respBody, &data); err == nil {
+			result.Data = data
+		}
+	}
+
+	return result, nil
+}
+
+// targetURL appends the query values to the request URL
+func (r *Request) targetURL() (*url.URL, error) {
+	target, err := url.Parse(r.url)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.query) > 0 {
+		q := target.Query()
+		for key, vs := range r.query {
+			for _, v := range vs {
+				q.Add(key, v)
 			}
 		}
+		target.RawQuery = q.Encode()
+	}
+	return target, nil
+}
+
+// Preview describes a request without sending it
+type Preview struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// Preview returns what Do would send. Values of credential-like headers are masked.
+func (r *Request) Preview() (*Preview, error) {
+	target, err := r.targetURL()
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &Preview{
+		Method:  r.method,
+		URL:     target.String(),
+		Headers: make(map[string]string, len(r.headers)),
+	}
+	for key := range r.headers {
+		value := r.headers.Get(key)
+		if isSecretHeader(key) {
+			value = "***"
+		}
+		preview.Headers[key] = value
+	}
+
+	if len(r.body) > 0 {
+		if json.Valid(r.body) {
+			preview.Body = json.RawMessage(r.body)
+		} else {
+			// body ที่ไม่ใช่ JSON เก็บเป็น string
+			preview.Body, _ = json.Marshal(string(r.body))
+		}
+	}
+	return preview, nil
+}
+
+func isSecretHeader(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range []string{"authorization", "token", "secret", "key", "password", "cookie"} {
+		if strings.Contains(key, word) {
+			return true
+		}
 	}
+	return false
+}
 
-	return resp.StatusCode, result, nil
+// IsSuccess reports whether the status code is 2xx
+func (r *Response) IsSuccess() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// EncodeQuery flattens a JSON document into query-string values.
+// Nested keys are joined with "." and array items use their index, e.g. targets.0.company_id=C01.
+func EncodeQuery(body []byte) (url.Values, error) {
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to encode query: %w", err)
+	}
+
+	values := url.Values{}
+	flatten(values, "", doc)
+	return values, nil
+}
+
+func flatten(values url.Values, prefix string, v interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			flatten(values, joinKey(prefix, k), t[k])
+		}
+	case []interface{}:
+		for i, item := range t {
+			flatten(values, joinKey(prefix, strconv.Itoa(i)), item)
+		}
+	case nil:
+		values.Add(prefix, "")
+	default:
+		values.Add(prefix, fmt.Sprint(t))
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
 }
@@ -0,0 +1,175 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeQuery(t *testing.T) {
+	values, err := EncodeQuery([]byte(`{
+		"log_id": "LOG001",
+		"amount": 12.50,
+		"count": 12345678901234567890,
+		"dry_run": false,
+		"note": null,
+		"automation": {"automation_id": "AUTO001", "channel": {"id": "CH1"}},
+		"targets": [{"company_id": "C01"}, {"company_id": "C02"}],
+		"tags": ["a", "b"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := url.Values{
+		"log_id":                   {"LOG001"},
+		"amount":                   {"12.50"},
+		"count":                    {"12345678901234567890"},
+		"dry_run":                  {"false"},
+		"note":                     {""},
+		"automation.automation_id": {"AUTO001"},
+		"automation.channel.id":    {"CH1"},
+		"targets.0.company_id":     {"C01"},
+		"targets.1.company_id":     {"C02"},
+		"tags.0":                   {"a"},
+		"tags.1":                   {"b"},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("EncodeQuery() = %v, want %v", values, want)
+	}
+
+	if _, err := EncodeQuery([]byte(`{`)); err == nil {
+		t.Error("EncodeQuery() accepted invalid JSON")
+	}
+}
+
+func TestRequestDoGetWithQuery(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"document_id":"DOC9"}`)
+	}))
+	defer server.Close()
+
+	query, err := EncodeQuery([]byte(`{"log_id":"LOG001","targets":[{"company_id":"C 01"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := NewRequest("get", server.URL+"/employees?source=hr").WithQuery(query).Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Method != http.MethodGet || got.URL.Path != "/employees" {
+		t.Errorf("request = %s %s", got.Method, got.URL.Path)
+	}
+	wantQuery := url.Values{"source": {"hr"}, "log_id": {"LOG001"}, "targets.0.company_id": {"C 01"}}
+	if !reflect.DeepEqual(got.URL.Query(), wantQuery) {
+		t.Errorf("query = %v, want %v", got.URL.Query(), wantQuery)
+	}
+	if !resp.IsSuccess() || resp.Data["document_id"] != "DOC9" {
+		t.Errorf("response = %d %v", resp.StatusCode, resp.Data)
+	}
+}
+
+func TestRequestDoHeaders(t *testing.T) {
+	var got http.Header
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, `accepted`)
+	}))
+	defer server.Close()
+
+	resp, err := NewRequest(http.MethodPost, server.URL).
+		WithJSONBody([]byte(`{"log_id":"LOG001"}`)).
+		WithHeaders(map[string]string{"content-type": "application/vnd.hr+json", "X-Api-Key": "k-123"}).
+		WithHeader("Idempotency-Key", "LOG001-AA001").
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Get("Content-Type") != "application/vnd.hr+json" {
+		t.Errorf("Content-Type = %q, want the action header to override the JSON default", got.Get("Content-Type"))
+	}
+	if got.Get("X-Api-Key") != "k-123" || got.Get("Idempotency-Key") != "LOG001-AA001" {
+		t.Errorf("headers = %v", got)
+	}
+	if body != `{"log_id":"LOG001"}` {
+		t.Errorf("body = %s", body)
+	}
+	if resp.StatusCode != http.StatusAccepted || string(resp.Body) != "accepted" || resp.Data != nil {
+		t.Errorf("response = %d %q %v, want a non-JSON body without Data", resp.StatusCode, resp.Body, resp.Data)
+	}
+}
+
+func TestRequestDoTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	start := time.Now()
+	_, err := NewRequest(http.MethodPost, server.URL).WithTimeout(50 * time.Millisecond).Do(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Do() error = %v, want the per-request timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Do() returned after %s", elapsed)
+	}
+}
+
+func TestRequestPreview(t *testing.T) {
+	preview, err := NewRequest(http.MethodGet, "https://hr.example.com/api?x=1").
+		WithQuery(url.Values{"log_id": {"LOG001"}}).
+		WithHeaders(map[string]string{
+			"Authorization":   "Bearer abc",
+			"X-Api-Key":       "k-123",
+			"X-Client-Secret": "s",
+			"Cookie":          "session=1",
+			"X-Tenant":        "T01",
+		}).
+		Preview()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if preview.Method != http.MethodGet || !strings.Contains(preview.URL, "log_id=LOG001") || !strings.Contains(preview.URL, "x=1") {
+		t.Errorf("preview = %s %s", preview.Method, preview.URL)
+	}
+	want := map[string]string{
+		"Authorization":   "***",
+		"X-Api-Key":       "***",
+		"X-Client-Secret": "***",
+		"Cookie":          "***",
+		"X-Tenant":        "T01",
+	}
+	if !reflect.DeepEqual(preview.Headers, want) {
+		t.Errorf("preview headers = %v, want %v", preview.Headers, want)
+	}
+
+	preview, err = NewRequest(http.MethodPost, "https://hr.example.com").WithJSONBody([]byte(`not json`)).Preview()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(preview.Body) != `"not json"` {
+		t.Errorf("preview body = %s, want the raw body as a JSON string", preview.Body)
+	}
+}
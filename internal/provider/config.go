@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ProviderConfig describes one entry of CONDITION_PROVIDERS, e.g.
+//
+//	[{"condition_type":"EMPLOYEE","kind":"http","url":"http://hr/api/condition-value"},
+//	 {"condition_type":"LEAVE","kind":"sql","query":"SELECT ...","unit":"DAY"},
+//	 {"condition_type":"FLAG","kind":"static","values":{"FEATURE_X":"1"}}]
+type ProviderConfig struct {
+	ConditionType string            `json:"condition_type"`
+	Kind          string            `json:"kind"`
+	URL           string            `json:"url"`
+	Query         string            `json:"query"`
+	Unit          string            `json:"unit"`
+	Values        map[string]string `json:"values"`
+}
+
+// RegisterFromConfig registers the providers described by a JSON array of ProviderConfig
+func (r *Registry) RegisterFromConfig(raw string, db *gorm.DB) error {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+
+	var configs []ProviderConfig
+	if err := json.Unmarshal([]byte(raw), &configs); err != nil {
+		return fmt.Errorf("invalid condition provider config: %w", err)
+	}
+
+	for _, cfg := range configs {
+		if cfg.ConditionType == "" {
+			return fmt.Errorf("condition provider config requires condition_type")
+		}
+
+		switch strings.ToLower(cfg.Kind) {
+		case "http":
+			if cfg.URL == "" {
+				return fmt.Errorf("condition_type %s: url is required", cfg.ConditionType)
+			}
+			r.Register(cfg.ConditionType, NewHTTPProvider(cfg.URL))
+		case "sql":
+			if cfg.Query == "" {
+				return fmt.Errorf("condition_type %s: query is required", cfg.ConditionType)
+			}
+			r.Register(cfg.ConditionType, NewSQLProvider(db, cfg.Query, cfg.Unit))
+		case "static":
+			values := make(map[string]Value, len(cfg.Values))
+			for code, v := range cfg.Values {
+				values[code] = Value{Value: v, UnitCode: cfg.Unit}
+			}
+			r.Register(cfg.ConditionType, NewStaticProvider(values))
+		default:
+			return fmt.Errorf("condition_type %s: unsupported provider kind %q", cfg.ConditionType, cfg.Kind)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"automation-engine/internal/directory"
+	"automation-engine/internal/httpclient"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// HTTPProvider posts the condition context to an external service which answers {"value": ..., "unit": "..."}
+type HTTPProvider struct {
+	url string
+}
+
+func NewHTTPProvider(url string) *HTTPProvider {
+	return &HTTPProvider{url: url}
+}
+
+type httpProviderRequest struct {
+	ConditionID   string      `json:"condition_id"`
+	ConditionCode string      `json:"condition_code"`
+	ConditionType string      `json:"condition_type"`
+	AutomationID  string      `json:"automation_id"`
+	Targets       interface{} `json:"targets"`
+	// Recipient คือพนักงานที่กำลังประเมิน (เฉพาะ action แบบ fan-out)
+	Recipient *directory.Recipient `json:"recipient,omitempty"`
+}
+
+func (p *HTTPProvider) Fetch(ctx context.Context, req Request) (Value, error) {
+	body, err := json.Marshal(httpProviderRequest{
+		ConditionID:   req.Condition.ConditionID,
+		ConditionCode: req.Condition.ConditionCode,
+		ConditionType: req.Condition.ConditionType,
+		AutomationID:  req.Automation.AutomationID,
+		Targets:       req.Targets,
+		Recipient:     req.Recipient,
+	})
+	if err != nil {
+		return Value{}, err
+	}
+
+	resp, err := httpclient.NewRequest(http.MethodPost, p.url).WithJSONBody(body).Do(ctx)
+	if err != nil {
+		return Value{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Value{}, fmt.Errorf("condition provider responded with status %d", resp.StatusCode)
+	}
+
+	value, ok := resp.Data["value"]
+	if !ok || value == nil {
+		return Value{}, fmt.Errorf("condition provider response has no value")
+	}
+
+	unit, _ := resp.Data["unit"].(string)
+	return Value{Value: fmt.Sprint(value), UnitCode: unit}, nil
+}
@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"automation-engine/internal/directory"
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/evaluator"
+	"automation-engine/internal/service"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UnitCacheTTL is how long Resolve reuses def_units before reading the table again
+const UnitCacheTTL = 5 * time.Minute
+
+// Request is passed to a ConditionProvider to fetch the left-hand value of a condition
+type Request struct {
+	Condition           *model.DefCondition
+	AutomationCondition *model.RunAutomationCondition
+	Automation          *model.RunAutomation
+	Targets             []*model.RunAutomationTarget
+	// Recipient is set when the condition is evaluated per employee (fan-out action)
+	Recipient *directory.Recipient
+}
+
+// Value is the raw value returned by a provider together with the unit it is expressed in
+type Value struct {
+	Value    string
+	UnitCode string
+}
+
+// ConditionProvider fetches the current value of a DefCondition
+type ConditionProvider interface {
+	Fetch(ctx context.Context, req Request) (Value, error)
+}
+
+// Registry maps DefCondition.ConditionType to a ConditionProvider and implements evaluator.ValueResolver
+type Registry struct {
+	mu                sync.RWMutex
+	providers         map[string]ConditionProvider
+	definitionService service.DefinitionService
+
+	unitsMu       sync.Mutex
+	units         []*model.DefUnit
+	unitsLoadedAt time.Time
+}
+
+func NewRegistry(definitionService service.DefinitionService) *Registry {
+	return &Registry{
+		providers:         make(map[string]ConditionProvider),
+		definitionService: definitionService,
+	}
+}
+
+// Register binds a provider to a condition type. Registering the same type twice replaces the provider.
+func (r *Registry) Register(conditionType string, p ConditionProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.providers[normalizeType(conditionType)] = p
+}
+
+func (r *Registry) Provider(conditionType string) (ConditionProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.providers[normalizeType(conditionType)]
+	return p, ok
+}
+
+// Resolve fetches the value of the condition and converts it into the unit configured on the automation condition
+func (r *Registry) Resolve(ctx context.Context, input evaluator.ResolveInput) (string, error) {
+	condition, err := r.definitionService.GetConditionByID(ctx, input.Condition.ConditionID)
+	if err != nil {
+		return "", fmt.Errorf("condition %s not found: %w", input.Condition.ConditionID, err)
+	}
+
+	p, ok := r.Provider(condition.ConditionType)
+	if !ok {
+		return "", fmt.Errorf("no provider registered for condition_type %s", condition.ConditionType)
+	}
+
+	value, err := p.Fetch(ctx, Request{
+		Condition:           condition,
+		AutomationCondition: input.Condition,
+		Automation:          input.Automation,
+		Targets:             input.Targets,
+		Recipient:           input.Recipient,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if input.Condition.UnitID == "" || value.UnitCode == "" {
+		return value.Value, nil
+	}
+
+	units, err := r.listUnits(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var target *model.DefUnit
+	for _, unit := range units {
+		if unit.UnitID == input.Condition.UnitID {
+			target = unit
+			break
+		}
+	}
+	if target == nil {
+		return "", fmt.Errorf("unit %s not found", input.Condition.UnitID)
+	}
+	if sameUnit(value.UnitCode, target.UnitCode) {
+		return value.Value, nil
+	}
+
+	// หน่วยที่ provider ส่งมาต้องมีใน def_units ด้วย จึงจะรู้ factor
+	source, ok := FindUnitByCode(units, value.UnitCode)
+	if !ok {
+		return "", fmt.Errorf("unsupported unit: %s", value.UnitCode)
+	}
+	return ConvertUnit(value.Value, source, target)
+}
+
+// listUnits returns def_units, read at most once per UnitCacheTTL because every condition of every fan-out
+// recipient is converted
+func (r *Registry) listUnits(ctx context.Context) ([]*model.DefUnit, error) {
+	r.unitsMu.Lock()
+	defer r.unitsMu.Unlock()
+
+	if r.units != nil && time.Since(r.unitsLoadedAt) < UnitCacheTTL {
+		return r.units, nil
+	}
+
+	units, err := r.definitionService.ListUnits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.units = units
+	r.unitsLoadedAt = time.Now()
+	return units, nil
+}
+
+func normalizeType(conditionType string) string {
+	return strings.ToUpper(strings.TrimSpace(conditionType))
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/evaluator"
+	"automation-engine/internal/service"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeDefinitions serves the conditions and units Resolve reads and counts ListUnits calls
+type fakeDefinitions struct {
+	service.DefinitionService
+	listUnitsCalls int
+	listUnitsErr   error
+}
+
+func (d *fakeDefinitions) GetConditionByID(ctx context.Context, conditionID string) (*model.DefCondition, error) {
+	return &model.DefCondition{ConditionID: conditionID, ConditionCode: "TENURE", ConditionType: "static"}, nil
+}
+
+func (d *fakeDefinitions) ListUnits(ctx context.Context) ([]*model.DefUnit, error) {
+	d.listUnitsCalls++
+	if d.listUnitsErr != nil {
+		return nil, d.listUnitsErr
+	}
+	return testUnits, nil
+}
+
+func TestRegistryResolveConvertsUnits(t *testing.T) {
+	definitions := &fakeDefinitions{}
+	registry := NewRegistry(definitions)
+	registry.Register("STATIC", NewStaticProvider(map[string]Value{"TENURE": {Value: "730", UnitCode: "DAY"}}))
+
+	resolve := func(unitID string) (string, error) {
+		return registry.Resolve(context.Background(), evaluator.ResolveInput{
+			Condition: &model.RunAutomationCondition{ConditionID: "C1", UnitID: unitID},
+		})
+	}
+
+	for _, tt := range []struct{ unitID, want string }{{"U2", "2"}, {"U1", "730"}, {"", "730"}} {
+		got, err := resolve(tt.unitID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Resolve() in unit %q = %s, want %s", tt.unitID, got, tt.want)
+		}
+	}
+	if _, err := resolve("U4"); err == nil {
+		t.Error("Resolve() converted days into a weight unit")
+	}
+	if _, err := resolve("U9"); err == nil {
+		t.Error("Resolve() accepted an unknown unit")
+	}
+}
+
+func TestRegistryCachesUnits(t *testing.T) {
+	definitions := &fakeDefinitions{listUnitsErr: errors.New("db down")}
+	registry := NewRegistry(definitions)
+	registry.Register("STATIC", NewStaticProvider(map[string]Value{"TENURE": {Value: "730", UnitCode: "DAY"}}))
+	input := evaluator.ResolveInput{Condition: &model.RunAutomationCondition{ConditionID: "C1", UnitID: "U2"}}
+
+	// error ไม่ถูก cache
+	if _, err := registry.Resolve(context.Background(), input); err == nil {
+		t.Fatal("Resolve() ignored the ListUnits error")
+	}
+	definitions.listUnitsErr = nil
+
+	for i := 0; i < 3; i++ {
+		if _, err := registry.Resolve(context.Background(), input); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if definitions.listUnitsCalls != 2 {
+		t.Errorf("ListUnits called %d times, want once after the failed call", definitions.listUnitsCalls)
+	}
+
+	registry.unitsLoadedAt = time.Now().Add(-UnitCacheTTL)
+	if _, err := registry.Resolve(context.Background(), input); err != nil {
+		t.Fatal(err)
+	}
+	if definitions.listUnitsCalls != 3 {
+		t.Errorf("ListUnits called %d times, want a reload after UnitCacheTTL", definitions.listUnitsCalls)
+	}
+}
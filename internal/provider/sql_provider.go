@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// SQLProvider runs a query whose first column of the first row is the condition value.
+// The query may use the named parameters @condition_id, @condition_code, @automation_id and
+// @employee_id (the recipient of a fan-out action, empty otherwise).
+type SQLProvider struct {
+	db       *gorm.DB
+	query    string
+	unitCode string
+}
+
+func NewSQLProvider(db *gorm.DB, query string, unitCode string) *SQLProvider {
+	return &SQLProvider{
+		db:       db,
+		query:    query,
+		unitCode: unitCode,
+	}
+}
+
+func (p *SQLProvider) Fetch(ctx context.Context, req Request) (Value, error) {
+	var value sql.NullString
+
+	employeeID := ""
+	if req.Recipient != nil {
+		employeeID = req.Recipient.EmployeeID
+	}
+
+	err := p.db.WithContext(ctx).Raw(p.query, map[string]interface{}{
+		"condition_id":   req.Condition.ConditionID,
+		"condition_code": req.Condition.ConditionCode,
+		"automation_id":  req.Automation.AutomationID,
+		"employee_id":    employeeID,
+	}).Row().Scan(&value)
+	if err != nil {
+		return Value{}, fmt.Errorf("condition query failed: %w", err)
+	}
+	if !value.Valid {
+		return Value{}, fmt.Errorf("condition query returned NULL")
+	}
+
+	return Value{Value: value.String, UnitCode: p.unitCode}, nil
+}
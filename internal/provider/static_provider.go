@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+)
+
+// StaticProvider returns fixed values keyed by DefCondition.ConditionCode (useful for testing and feature flags)
+type StaticProvider struct {
+	values map[string]Value
+}
+
+func NewStaticProvider(values map[string]Value) *StaticProvider {
+	return &StaticProvider{values: values}
+}
+
+func (p *StaticProvider) Fetch(ctx context.Context, req Request) (Value, error) {
+	value, ok := p.values[req.Condition.ConditionCode]
+	if !ok {
+		return Value{}, fmt.Errorf("no static value for condition_code %s", req.Condition.ConditionCode)
+	}
+	return value, nil
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"automation-engine/internal/domain/model"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ConvertUnit converts a numeric value between two def_units rows (e.g. DAY -> YEAR) using their dimension and factor.
+// Values in the same unit are returned unchanged.
+func ConvertUnit(value string, from, to *model.DefUnit) (string, error) {
+	if sameUnit(from.UnitCode, to.UnitCode) {
+		return value, nil
+	}
+
+	for _, unit := range []*model.DefUnit{from, to} {
+		if unit.Dimension == "" || unit.Factor <= 0 {
+			return "", fmt.Errorf("unit %s has no conversion factor", unit.UnitCode)
+		}
+	}
+	if !strings.EqualFold(from.Dimension, to.Dimension) {
+		return "", fmt.Errorf("cannot convert %s to %s", from.UnitCode, to.UnitCode)
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return "", fmt.Errorf("value %q is not numeric: %w", value, err)
+	}
+
+	converted := n * from.Factor / to.Factor
+	return strconv.FormatFloat(converted, 'f', -1, 64), nil
+}
+
+// FindUnitByCode returns the def_units row of a unit code reported by a provider.
+// Codes are matched case-insensitively and singular/plural alike (DAY, DAYS, day).
+func FindUnitByCode(units []*model.DefUnit, code string) (*model.DefUnit, bool) {
+	for _, unit := range units {
+		if sameUnit(unit.UnitCode, code) {
+			return unit, true
+		}
+	}
+	return nil, false
+}
+
+func sameUnit(a, b string) bool {
+	return normalizeUnit(a) == normalizeUnit(b)
+}
+
+func normalizeUnit(code string) string {
+	return strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(code)), "S")
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"automation-engine/internal/domain/model"
+	"testing"
+)
+
+var testUnits = []*model.DefUnit{
+	{UnitID: "U1", UnitCode: "DAY", Dimension: "time", Factor: 86400},
+	{UnitID: "U2", UnitCode: "YEAR", Dimension: "time", Factor: 31536000},
+	{UnitID: "U3", UnitCode: "HOURS", Dimension: "time", Factor: 3600},
+	{UnitID: "U4", UnitCode: "KG", Dimension: "weight", Factor: 1},
+	{UnitID: "U5", UnitCode: "POINT"},
+}
+
+func unitByCode(t *testing.T, code string) *model.DefUnit {
+	t.Helper()
+	unit, ok := FindUnitByCode(testUnits, code)
+	if !ok {
+		t.Fatalf("unit %s not found", code)
+	}
+	return unit
+}
+
+func TestConvertUnit(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		from    string
+		to      string
+		want    string
+		wantErr bool
+	}{
+		{name: "days to years", value: "730", from: "DAY", to: "YEAR", want: "2"},
+		{name: "years to days", value: "1.5", from: "YEAR", to: "DAY", want: "547.5"},
+		{name: "plural code", value: "48", from: "HOUR", to: "DAYS", want: "2"},
+		{name: "same unit keeps the value", value: "abc", from: "POINT", to: "point", want: "abc"},
+		{name: "different dimension", value: "1", from: "DAY", to: "KG", wantErr: true},
+		{name: "unit without factor", value: "1", from: "POINT", to: "DAY", wantErr: true},
+		{name: "not numeric", value: "ten", from: "DAY", to: "YEAR", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertUnit(tt.value, unitByCode(t, tt.from), unitByCode(t, tt.to))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ConvertUnit() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConvertUnit() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ConvertUnit() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindUnitByCode(t *testing.T) {
+	if _, ok := FindUnitByCode(testUnits, "MONTH"); ok {
+		t.Error("found a unit that is not in def_units")
+	}
+	if unit, ok := FindUnitByCode(testUnits, " days "); !ok || unit.UnitID != "U1" {
+		t.Errorf("FindUnitByCode(days) = %v, %v", unit, ok)
+	}
+}
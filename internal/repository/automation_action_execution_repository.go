@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/domain/query"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AutomationActionExecutionRepository interface {
+	GenerateStepID() string
+	GetByIDForUpdate(ctx context.Context, stepID string) (*model.LogAutomationActionExecution, error)
+	Create(ctx context.Context, step *model.LogAutomationActionExecution) error
+	Save(ctx context.Context, step *model.LogAutomationActionExecution) error
+	ListExpired(ctx context.Context, status string, now time.Time, limit int) ([]*model.LogAutomationActionExecution, error)
+	ListByLogID(ctx context.Context, logID string) ([]*model.LogAutomationActionExecution, error)
+	DeleteBefore(ctx context.Context, t time.Time) error
+}
+
+type automationActionExecutionRepository struct {
+	BaseRepository
+}
+
+func NewAutomationActionExecutionRepository(db *gorm.DB) AutomationActionExecutionRepository {
+	return &automationActionExecutionRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *automationActionExecutionRepository) GenerateStepID() string {
+	return r.GenerateSortableID(20)
+}
+
+func (r *automationActionExecutionRepository) GetByIDForUpdate(ctx context.Context, stepID string) (*model.LogAutomationActionExecution, error) {
+	q := query.Use(r.Executor(ctx)).LogAutomationActionExecution
+	return q.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(q.StepID.Eq(stepID)).
+		First()
+}
+
+func (r *automationActionExecutionRepository) Create(ctx context.Context, step *model.LogAutomationActionExecution) error {
+	if step.StepID == "" {
+		step.StepID = r.GenerateStepID()
+	}
+
+	q := query.Use(r.Executor(ctx)).LogAutomationActionExecution
+	return q.WithContext(ctx).Create(step)
+}
+
+func (r *automationActionExecutionRepository) Save(ctx context.Context, step *model.LogAutomationActionExecution) error {
+	q := query.Use(r.Executor(ctx)).LogAutomationActionExecution
+	return q.WithContext(ctx).Save(step)
+}
+
+// ListExpired returns steps still in the given status whose deadline has passed
+func (r *automationActionExecutionRepository) ListExpired(ctx context.Context, status string, now time.Time, limit int) ([]*model.LogAutomationActionExecution, error) {
+	q := query.Use(r.Executor(ctx)).LogAutomationActionExecution
+	return q.WithContext(ctx).
+		Where(q.Status.Eq(status), q.DeadlineAt.Lt(now)).
+		Order(q.DeadlineAt).
+		Limit(limit).
+		Find()
+}
+
+func (r *automationActionExecutionRepository) ListByLogID(ctx context.Context, logID string) ([]*model.LogAutomationActionExecution, error) {
+	q := query.Use(r.Executor(ctx)).LogAutomationActionExecution
+	return q.WithContext(ctx).
+		Where(q.LogID.Eq(logID)).
+		Order(q.StepID).
+		Find()
+}
+
+func (r *automationActionExecutionRepository) DeleteBefore(ctx context.Context, t time.Time) error {
+	q := query.Use(r.Executor(ctx)).LogAutomationActionExecution
+	_, err := q.WithContext(ctx).
+		Unscoped().
+		Where(q.StartedAt.Lt(t)).
+		Delete()
+	return err
+}
@@ -9,7 +9,10 @@ import (
 )
 
 type AutomationActionRepository interface {
+	GenerateID() string
 	ListByAutomationID(ctx context.Context, automationID string) ([]*model.RunAutomationAction, error)
+	BulkCreate(ctx context.Context, rows []*model.RunAutomationAction) error
+	DeleteByAutomationID(ctx context.Context, automationID string) error
 }
 
 type automationActionRepository struct {
@@ -22,6 +25,10 @@ func NewAutomationActionRepository(db *gorm.DB) AutomationActionRepository {
 	}
 }
 
+func (r *automationActionRepository) GenerateID() string {
+	return r.GenerateSortableID(20)
+}
+
 func (r *automationActionRepository) ListByAutomationID(ctx context.Context, automationID string) ([]*model.RunAutomationAction, error) {
 	q := query.Use(r.Executor(ctx)).RunAutomationAction
 	db := q.WithContext(ctx)
@@ -30,3 +37,18 @@ func (r *automationActionRepository) ListByAutomationID(ctx context.Context, aut
 
 	return db.Find()
 }
+
+func (r *automationActionRepository) BulkCreate(ctx context.Context, rows []*model.RunAutomationAction) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return r.Executor(ctx).
+		Create(&rows).Error
+}
+
+func (r *automationActionRepository) DeleteByAutomationID(ctx context.Context, automationID string) error {
+	q := query.Use(r.Executor(ctx)).RunAutomationAction
+	_, err := q.WithContext(ctx).Where(q.AutomationID.Eq(automationID)).Delete()
+	return err
+}
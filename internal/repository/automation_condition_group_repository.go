@@ -9,7 +9,10 @@ import (
 )
 
 type AutomationConditionGroupRepository interface {
+	GenerateID() string
 	ListByAutomationID(ctx context.Context, automationID string) ([]*model.RunAutomationConditionGroup, error)
+	BulkCreate(ctx context.Context, rows []*model.RunAutomationConditionGroup) error
+	DeleteByAutomationID(ctx context.Context, automationID string) error
 }
 
 type automationConditionGroupRepository struct {
@@ -22,6 +25,10 @@ func NewAutomationConditionGroupRepository(db *gorm.DB) AutomationConditionGroup
 	}
 }
 
+func (r *automationConditionGroupRepository) GenerateID() string {
+	return r.GenerateSortableID(20)
+}
+
 func (r *automationConditionGroupRepository) ListByAutomationID(ctx context.Context, automationID string) ([]*model.RunAutomationConditionGroup, error) {
 	q := query.Use(r.Executor(ctx)).RunAutomationConditionGroup
 	db := q.WithContext(ctx)
@@ -30,3 +37,18 @@ func (r *automationConditionGroupRepository) ListByAutomationID(ctx context.Cont
 
 	return db.Find()
 }
+
+func (r *automationConditionGroupRepository) BulkCreate(ctx context.Context, rows []*model.RunAutomationConditionGroup) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return r.Executor(ctx).
+		Create(&rows).Error
+}
+
+func (r *automationConditionGroupRepository) DeleteByAutomationID(ctx context.Context, automationID string) error {
+	q := query.Use(r.Executor(ctx)).RunAutomationConditionGroup
+	_, err := q.WithContext(ctx).Where(q.AutomationID.Eq(automationID)).Delete()
+	return err
+}
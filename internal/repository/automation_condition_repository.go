@@ -9,7 +9,10 @@ import (
 )
 
 type AutomationConditionRepository interface {
+	GenerateID() string
 	ListByGroupIDs(ctx context.Context, groupIDs []string) ([]*model.RunAutomationCondition, error)
+	BulkCreate(ctx context.Context, rows []*model.RunAutomationCondition) error
+	DeleteByGroupIDs(ctx context.Context, groupIDs []string) error
 }
 
 type automationConditionRepository struct {
@@ -22,6 +25,10 @@ func NewAutomationConditionRepository(db *gorm.DB) AutomationConditionRepository
 	}
 }
 
+func (r *automationConditionRepository) GenerateID() string {
+	return r.GenerateSortableID(20)
+}
+
 func (r *automationConditionRepository) ListByGroupIDs(ctx context.Context, groupIDs []string) ([]*model.RunAutomationCondition, error) {
 	q := query.Use(r.Executor(ctx)).RunAutomationCondition
 	db := q.WithContext(ctx)
@@ -30,3 +37,22 @@ func (r *automationConditionRepository) ListByGroupIDs(ctx context.Context, grou
 
 	return db.Find()
 }
+
+func (r *automationConditionRepository) BulkCreate(ctx context.Context, rows []*model.RunAutomationCondition) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return r.Executor(ctx).
+		Create(&rows).Error
+}
+
+func (r *automationConditionRepository) DeleteByGroupIDs(ctx context.Context, groupIDs []string) error {
+	if len(groupIDs) == 0 {
+		return nil
+	}
+
+	q := query.Use(r.Executor(ctx)).RunAutomationCondition
+	_, err := q.WithContext(ctx).Where(q.AutomationConditionGroupID.In(groupIDs...)).Delete()
+	return err
+}
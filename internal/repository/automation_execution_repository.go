@@ -6,14 +6,38 @@ import (
 	"context"
 	"time"
 
+	"gorm.io/gen/field"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// ExecutionFilter filters log_automation_executions. Cursor is the last LogID of the previous page.
+type ExecutionFilter struct {
+	AutomationID  string
+	Status        string
+	TriggeredFrom time.Time
+	TriggeredTo   time.Time
+	Cursor        string
+	Limit         int
+	// Dead-letter filters (DeadLettered limits the result to executions whose message was dead-lettered)
+	DeadLettered     bool
+	DeadLetterReason string
+	DeadLetteredFrom time.Time
+	DeadLetteredTo   time.Time
+	ExcludeReplayed  bool
+}
+
 type AutomationExecutionRepository interface {
 	GenerateLogID() string
+	GetByID(ctx context.Context, logID string) (*model.LogAutomationExecution, error)
+	GetByIDForUpdate(ctx context.Context, logID string) (*model.LogAutomationExecution, error)
+	List(ctx context.Context, filter ExecutionFilter) ([]*model.LogAutomationExecution, error)
 	Create(ctx context.Context, log *model.LogAutomationExecution) error
 	Upsert(ctx context.Context, log *model.LogAutomationExecution) error
+	CreateIfNotExists(ctx context.Context, log *model.LogAutomationExecution) (bool, error)
+	Claim(ctx context.Context, logID string, attempt int32, claimedUntil time.Time) error
+	MarkReplayed(ctx context.Context, logID string, replayLogID string) (bool, error)
+	UnmarkReplayed(ctx context.Context, logID string, replayLogID string) error
 	DeleteBefore(ctx context.Context, t time.Time) error
 }
 
@@ -31,6 +55,62 @@ func (r *automationExecutionRepository) GenerateLogID() string {
 	return r.GenerateSortableID(20)
 }
 
+func (r *automationExecutionRepository) GetByID(ctx context.Context, logID string) (*model.LogAutomationExecution, error) {
+	q := query.Use(r.Executor(ctx)).LogAutomationExecution
+	return q.WithContext(ctx).Where(q.LogID.Eq(logID)).First()
+}
+
+func (r *automationExecutionRepository) GetByIDForUpdate(ctx context.Context, logID string) (*model.LogAutomationExecution, error) {
+	q := query.Use(r.Executor(ctx)).LogAutomationExecution
+	return q.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(q.LogID.Eq(logID)).
+		First()
+}
+
+// List returns executions newest first. LogID is sortable by creation time so it doubles as the cursor.
+func (r *automationExecutionRepository) List(ctx context.Context, filter ExecutionFilter) ([]*model.LogAutomationExecution, error) {
+	q := query.Use(r.Executor(ctx)).LogAutomationExecution
+	db := q.WithContext(ctx)
+
+	// Dynamic Filtering
+	if filter.AutomationID != "" {
+		db = db.Where(q.AutomationID.Eq(filter.AutomationID))
+	}
+	if filter.Status != "" {
+		db = db.Where(q.Status.Eq(filter.Status))
+	}
+	if !filter.TriggeredFrom.IsZero() {
+		db = db.Where(q.TriggeredAt.Gte(filter.TriggeredFrom))
+	}
+	if !filter.TriggeredTo.IsZero() {
+		db = db.Where(q.TriggeredAt.Lte(filter.TriggeredTo))
+	}
+	if filter.DeadLettered {
+		db = db.Where(q.DeadLetterReason.Neq(""))
+	}
+	if filter.DeadLetterReason != "" {
+		db = db.Where(q.DeadLetterReason.Eq(filter.DeadLetterReason))
+	}
+	if !filter.DeadLetteredFrom.IsZero() {
+		db = db.Where(q.DeadLetteredAt.Gte(filter.DeadLetteredFrom))
+	}
+	if !filter.DeadLetteredTo.IsZero() {
+		db = db.Where(q.DeadLetteredAt.Lte(filter.DeadLetteredTo))
+	}
+	if filter.ExcludeReplayed {
+		db = db.Where(field.Or(q.ReplayedByLogID.IsNull(), q.ReplayedByLogID.Eq("")))
+	}
+	if filter.Cursor != "" {
+		db = db.Where(q.LogID.Lt(filter.Cursor))
+	}
+	if filter.Limit > 0 {
+		db = db.Limit(filter.Limit)
+	}
+
+	return db.Order(q.LogID.Desc()).Find()
+}
+
 func (r *automationExecutionRepository) Create(ctx context.Context, log *model.LogAutomationExecution) error {
 	log.LogID = r.GenerateSortableID(20)
 
@@ -50,6 +130,48 @@ func (r *automationExecutionRepository) Upsert(ctx context.Context, log *model.L
 	}).Create(log).Error
 }
 
+// CreateIfNotExists inserts the log unless its LogID already exists, created is false when the row was already there
+func (r *automationExecutionRepository) CreateIfNotExists(ctx context.Context, log *model.LogAutomationExecution) (bool, error) {
+	result := r.Executor(ctx).WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(log)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Claim marks an existing execution RUNNING for the given attempt until claimedUntil
+func (r *automationExecutionRepository) Claim(ctx context.Context, logID string, attempt int32, claimedUntil time.Time) error {
+	q := query.Use(r.Executor(ctx)).LogAutomationExecution
+	_, err := q.WithContext(ctx).
+		Where(q.LogID.Eq(logID)).
+		Updates(&model.LogAutomationExecution{
+			Status:       "RUNNING",
+			Attempt:      attempt,
+			ClaimedUntil: claimedUntil,
+		})
+	return err
+}
+
+// MarkReplayed links a dead-lettered execution to the execution that replays it, unless it is already linked.
+// marked is false when another replay claimed the execution first.
+func (r *automationExecutionRepository) MarkReplayed(ctx context.Context, logID string, replayLogID string) (bool, error) {
+	q := query.Use(r.Executor(ctx)).LogAutomationExecution
+	result, err := q.WithContext(ctx).
+		Where(q.LogID.Eq(logID)).
+		Where(field.Or(q.ReplayedByLogID.IsNull(), q.ReplayedByLogID.Eq(""))).
+		Update(q.ReplayedByLogID, replayLogID)
+	return result.RowsAffected > 0, err
+}
+
+// UnmarkReplayed removes the link written by MarkReplayed when the replay could not be sent
+func (r *automationExecutionRepository) UnmarkReplayed(ctx context.Context, logID string, replayLogID string) error {
+	q := query.Use(r.Executor(ctx)).LogAutomationExecution
+	_, err := q.WithContext(ctx).
+		Where(q.LogID.Eq(logID)).
+		Where(q.ReplayedByLogID.Eq(replayLogID)).
+		Update(q.ReplayedByLogID, "")
+	return err
+}
+
 func (r *automationExecutionRepository) DeleteBefore(ctx context.Context, t time.Time) error {
 	q := query.Use(r.Executor(ctx)).LogAutomationExecution
 	_, err := q.WithContext(ctx).
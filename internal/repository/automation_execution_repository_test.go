@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMarkReplayed(t *testing.T) {
+	db, rec := newRecorderDB(t)
+	repo := NewAutomationExecutionRepository(db)
+
+	marked, err := repo.MarkReplayed(context.Background(), "LOG001", "LOG002")
+	if err != nil || !marked {
+		t.Fatalf("MarkReplayed() = %v, %v, want marked", marked, err)
+	}
+	update := rec.last(t, "UPDATE")
+	update.assertContains(t,
+		"SET `replayed_by_log_id`=?",
+		"`log_automation_executions`.`log_id` = ?",
+		"(`log_automation_executions`.`replayed_by_log_id` IS NULL OR `log_automation_executions`.`replayed_by_log_id` = ?)",
+	)
+	if !update.hasArg("LOG001") || !update.hasArg("LOG002") {
+		t.Errorf("MarkReplayed() args = %v", update.args)
+	}
+
+	rec.rowsAffected = 0
+	if marked, err := repo.MarkReplayed(context.Background(), "LOG001", "LOG003"); err != nil || marked {
+		t.Errorf("MarkReplayed() of a replayed execution = %v, %v, want not marked", marked, err)
+	}
+}
+
+func TestUnmarkReplayed(t *testing.T) {
+	db, rec := newRecorderDB(t)
+
+	if err := NewAutomationExecutionRepository(db).UnmarkReplayed(context.Background(), "LOG001", "LOG002"); err != nil {
+		t.Fatal(err)
+	}
+	update := rec.last(t, "UPDATE")
+	update.assertContains(t, "`log_automation_executions`.`log_id` = ?", "`log_automation_executions`.`replayed_by_log_id` = ?")
+	if !update.hasArg("LOG002") || !update.hasArg("") {
+		t.Errorf("UnmarkReplayed() args = %v", update.args)
+	}
+}
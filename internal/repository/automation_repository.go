@@ -6,16 +6,26 @@ import (
 	"context"
 	"time"
 
+	"gorm.io/gen/field"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type AutomationRepository interface {
+	GenerateID() string
 	GetByID(ctx context.Context, id string) (*model.RunAutomation, error)
+	List(ctx context.Context, filter model.RunAutomation) ([]*model.RunAutomation, error)
+	Create(ctx context.Context, automation *model.RunAutomation) error
+	Delete(ctx context.Context, id string) error
+	DeleteUnlessLocked(ctx context.Context, id string) (bool, error)
 	Update(ctx context.Context, action *model.RunAutomation) error
+	Save(ctx context.Context, automation *model.RunAutomation) error
+	UpdateColumns(ctx context.Context, automation *model.RunAutomation, expectedState string, columns ...string) (bool, error)
 	FetchAndLock(ctx context.Context, runTime time.Time, limit int) ([]*model.RunAutomation, error)
 	UpdateStatusBatch(ctx context.Context, ids []string, status string) error
-	BulkUpdateNextRun(ctx context.Context, tasks []*model.RunAutomation) error
+	AcquireLease(ctx context.Context, ids []string, lockedBy string, expiresAt time.Time) error
+	FetchExpiredLeases(ctx context.Context, now time.Time, limit int) ([]*model.RunAutomation, error)
+	ReleaseLease(ctx context.Context, task *model.RunAutomation, lockedBy string) (bool, error)
 }
 
 type automationRepository struct {
@@ -28,28 +38,102 @@ func NewAutomationRepository(db *gorm.DB) AutomationRepository {
 	}
 }
 
+func (r *automationRepository) GenerateID() string {
+	return r.GenerateSortableID(20)
+}
+
 func (r *automationRepository) GetByID(ctx context.Context, id string) (*model.RunAutomation, error) {
 	q := query.Use(r.Executor(ctx)).RunAutomation
 	return q.WithContext(ctx).Where(q.AutomationID.Eq(id)).First()
 }
 
+func (r *automationRepository) List(ctx context.Context, filter model.RunAutomation) ([]*model.RunAutomation, error) {
+	q := query.Use(r.Executor(ctx)).RunAutomation
+	db := q.WithContext(ctx)
+
+	// Dynamic Filtering
+	if filter.InstanceServerID != "" {
+		db = db.Where(q.InstanceServerID.Eq(filter.InstanceServerID))
+	}
+	if filter.InstanceServerChannelID != "" {
+		db = db.Where(q.InstanceServerChannelID.Eq(filter.InstanceServerChannelID))
+	}
+	if filter.Frequency != "" {
+		db = db.Where(q.Frequency.Eq(filter.Frequency))
+	}
+	if filter.Status != "" {
+		db = db.Where(q.Status.Eq(filter.Status))
+	}
+	if filter.IsActive != "" {
+		db = db.Where(q.IsActive.Eq(filter.IsActive))
+	}
+	if filter.LifecycleState != "" {
+		db = db.Where(q.LifecycleState.Eq(filter.LifecycleState))
+	}
+
+	return db.Order(q.AutomationID).Find()
+}
+
+func (r *automationRepository) Create(ctx context.Context, automation *model.RunAutomation) error {
+	q := query.Use(r.Executor(ctx)).RunAutomation
+	return q.WithContext(ctx).Create(automation)
+}
+
+func (r *automationRepository) Delete(ctx context.Context, id string) error {
+	q := query.Use(r.Executor(ctx)).RunAutomation
+	_, err := q.WithContext(ctx).Where(q.AutomationID.Eq(id)).Delete()
+	return err
+}
+
+// DeleteUnlessLocked deletes the automation unless a scheduler holds it LOCKED, deleted is false when no row was removed
+func (r *automationRepository) DeleteUnlessLocked(ctx context.Context, id string) (bool, error) {
+	q := query.Use(r.Executor(ctx)).RunAutomation
+	result, err := q.WithContext(ctx).
+		Where(q.AutomationID.Eq(id)).
+		Where(q.Status.Neq("LOCKED")).
+		Delete()
+	return result.RowsAffected > 0, err
+}
+
 func (r *automationRepository) Update(ctx context.Context, action *model.RunAutomation) error {
 	q := query.Use(r.Executor(ctx)).RunAutomation
 	_, err := q.WithContext(ctx).Where(q.AutomationID.Eq(action.AutomationID)).Updates(action)
 	return err
 }
 
+// Save updates every column including zero values (e.g. clearing day_of_week)
+func (r *automationRepository) Save(ctx context.Context, automation *model.RunAutomation) error {
+	return r.Executor(ctx).Save(automation).Error
+}
+
+// UpdateColumns writes only the given columns (zero values included) while the row is still in expectedState,
+// so columns owned by the scheduler (status, run_count, lease) are left alone.
+// It returns false when no row changed: the lifecycle state moved on, or the values were already the same.
+func (r *automationRepository) UpdateColumns(ctx context.Context, automation *model.RunAutomation, expectedState string, columns ...string) (bool, error) {
+	q := query.Use(r.Executor(ctx)).RunAutomation
+
+	result := r.Executor(ctx).WithContext(ctx).
+		Model(&model.RunAutomation{}).
+		Where(q.AutomationID.Eq(automation.AutomationID)).
+		Where(q.LifecycleState.Eq(expectedState)).
+		Select(columns).
+		Updates(automation)
+	return result.RowsAffected > 0, result.Error
+}
+
 func (r *automationRepository) FetchAndLock(ctx context.Context, runTime time.Time, limit int) ([]*model.RunAutomation, error) {
 	var results []*model.RunAutomation
 	q := query.Use(r.Executor(ctx)).RunAutomation
 
-	// ยอมให้ย้อนหลังได้แค่ 59 วินาที (เพื่อไม่ให้ข้ามนาทีปัจจุบันไป)
-	lookbackTime := runTime.Add(-59 * time.Second)
-
+	// ดึงทุกงานที่ถึงเวลาแล้ว รวมถึงรอบที่พลาดไป (misfire policy จะตัดสินว่าจะรันหรือไม่)
+	// next_run_time ที่เป็นค่าว่างคืองานที่ไม่มีรอบถัดไปแล้ว (เช่น once ที่รันไปแล้ว)
 	err := r.Executor(ctx).WithContext(ctx).
 		Model(&model.RunAutomation{}).
-		Where(q.NextRunTime.Between(lookbackTime, runTime)).
+		Where(q.NextRunTime.Lte(runTime)).
+		Where(q.NextRunTime.Gt(time.Time{})).
 		Where(q.Status.Eq("PENDING")).
+		Where(q.LifecycleState.Eq("ACTIVE")).
+		Order(q.NextRunTime).
 		Limit(limit).
 		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
 		Find(&results).Error
@@ -69,15 +153,60 @@ func (r *automationRepository) UpdateStatusBatch(ctx context.Context, ids []stri
 	return err
 }
 
-func (r *automationRepository) BulkUpdateNextRun(ctx context.Context, tasks []*model.RunAutomation) error {
-	return r.Executor(ctx).WithContext(ctx).
-		Clauses(clause.OnConflict{
-			Columns: []clause.Column{{Name: "automation_id"}}, // Primary Key
-			DoUpdates: clause.AssignmentColumns([]string{
-				"status",
-				"next_run_time",
-				"last_upd",
-			}),
-		}).
-		Create(&tasks).Error
+// AcquireLease marks the rows LOCKED by the given scheduler until expiresAt
+func (r *automationRepository) AcquireLease(ctx context.Context, ids []string, lockedBy string, expiresAt time.Time) error {
+	q := query.Use(r.Executor(ctx)).RunAutomation
+
+	_, err := q.WithContext(ctx).
+		Where(q.AutomationID.In(ids...)).
+		Updates(&model.RunAutomation{
+			Status:        "LOCKED",
+			LockedBy:      lockedBy,
+			LockExpiresAt: expiresAt,
+			LastUpd:       time.Now(),
+		})
+	return err
+}
+
+// FetchExpiredLeases locks LOCKED rows whose lease has expired (or that were locked before leases existed)
+func (r *automationRepository) FetchExpiredLeases(ctx context.Context, now time.Time, limit int) ([]*model.RunAutomation, error) {
+	var results []*model.RunAutomation
+	q := query.Use(r.Executor(ctx)).RunAutomation
+
+	err := r.Executor(ctx).WithContext(ctx).
+		Model(&model.RunAutomation{}).
+		Where(q.Status.Eq("LOCKED")).
+		Where(field.Or(q.LockExpiresAt.Lt(now), q.LockExpiresAt.IsNull())).
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Find(&results).Error
+
+	return results, err
+}
+
+// ReleaseLease writes status, next_run_time and run_count back and clears the lease, but only while the row is still
+// LOCKED by lockedBy ("" is a lease taken before locked_by existed). It returns false when the lease was lost:
+// it expired and was reclaimed or re-leased by another scheduler, or the automation was deleted.
+func (r *automationRepository) ReleaseLease(ctx context.Context, task *model.RunAutomation, lockedBy string) (bool, error) {
+	q := query.Use(r.Executor(ctx)).RunAutomation
+
+	db := r.Executor(ctx).WithContext(ctx).
+		Model(&model.RunAutomation{}).
+		Where(q.AutomationID.Eq(task.AutomationID)).
+		Where(q.Status.Eq("LOCKED"))
+	if lockedBy == "" {
+		db = db.Where(field.Or(q.LockedBy.IsNull(), q.LockedBy.Eq("")))
+	} else {
+		db = db.Where(q.LockedBy.Eq(lockedBy))
+	}
+
+	result := db.Updates(map[string]interface{}{
+		"status":          task.Status,
+		"next_run_time":   task.NextRunTime,
+		"run_count":       task.RunCount,
+		"last_upd":        task.LastUpd,
+		"locked_by":       nil,
+		"lock_expires_at": nil,
+	})
+	return result.RowsAffected > 0, result.Error
 }
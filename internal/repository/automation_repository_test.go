@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"automation-engine/internal/domain/model"
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestAcquireLease(t *testing.T) {
+	db, rec := newRecorderDB(t)
+	repo := NewAutomationRepository(db)
+	expiresAt := time.Date(2024, 1, 15, 2, 5, 0, 0, time.UTC)
+
+	if err := repo.AcquireLease(context.Background(), []string{"AUTO001", "AUTO002"}, "scheduler-1", expiresAt); err != nil {
+		t.Fatal(err)
+	}
+
+	update := rec.last(t, "UPDATE")
+	update.assertContains(t, "`status`=?", "`locked_by`=?", "`lock_expires_at`=?", "`automation_id` IN (?,?)")
+	for _, arg := range []driver.Value{"LOCKED", "scheduler-1", expiresAt, "AUTO001", "AUTO002"} {
+		if !update.hasArg(arg) {
+			t.Errorf("AcquireLease() did not bind %v: %v", arg, update.args)
+		}
+	}
+}
+
+func TestFetchExpiredLeases(t *testing.T) {
+	db, rec := newRecorderDB(t)
+	rec.columns = []string{"automation_id", "status", "locked_by"}
+	rec.rows = [][]driver.Value{{"AUTO001", "LOCKED", "scheduler-1"}}
+	repo := NewAutomationRepository(db)
+	now := time.Date(2024, 1, 15, 2, 0, 0, 0, time.UTC)
+
+	expired, err := repo.FetchExpiredLeases(context.Background(), now, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 1 || expired[0].AutomationID != "AUTO001" || expired[0].LockedBy != "scheduler-1" {
+		t.Errorf("FetchExpiredLeases() = %+v", expired)
+	}
+
+	query := rec.last(t, "SELECT")
+	query.assertContains(t, "`status` = ?", "`run_automations`.`lock_expires_at` < ? OR `run_automations`.`lock_expires_at` IS NULL", "LIMIT ?", "FOR UPDATE SKIP LOCKED")
+	if !query.hasArg("LOCKED") || !query.hasArg(now) {
+		t.Errorf("FetchExpiredLeases() args = %v", query.args)
+	}
+}
+
+func TestReleaseLease(t *testing.T) {
+	task := &model.RunAutomation{
+		AutomationID: "AUTO001",
+		Status:       "PENDING",
+		NextRunTime:  time.Date(2024, 1, 16, 2, 0, 0, 0, time.UTC),
+		RunCount:     3,
+		LastUpd:      time.Date(2024, 1, 15, 2, 0, 1, 0, time.UTC),
+	}
+
+	t.Run("still held", func(t *testing.T) {
+		db, rec := newRecorderDB(t)
+		released, err := NewAutomationRepository(db).ReleaseLease(context.Background(), task, "scheduler-1")
+		if err != nil || !released {
+			t.Fatalf("ReleaseLease() = %v, %v, want released", released, err)
+		}
+
+		update := rec.last(t, "UPDATE")
+		update.assertContains(t,
+			"`locked_by`=?", "`lock_expires_at`=?", "`next_run_time`=?", "`run_count`=?",
+			"`run_automations`.`automation_id` = ?", "`run_automations`.`status` = ?", "`run_automations`.`locked_by` = ?",
+		)
+		for _, arg := range []driver.Value{nil, "AUTO001", "LOCKED", "scheduler-1", "PENDING", task.NextRunTime, int64(3)} {
+			if !update.hasArg(arg) {
+				t.Errorf("ReleaseLease() did not bind %v: %v", arg, update.args)
+			}
+		}
+	})
+
+	t.Run("lease lost", func(t *testing.T) {
+		db, rec := newRecorderDB(t)
+		rec.rowsAffected = 0
+		released, err := NewAutomationRepository(db).ReleaseLease(context.Background(), task, "scheduler-1")
+		if err != nil || released {
+			t.Fatalf("ReleaseLease() = %v, %v, want not released", released, err)
+		}
+		for _, stmt := range rec.statements {
+			stmt.assertNotInsert(t)
+		}
+	})
+
+	t.Run("lease taken before locked_by existed", func(t *testing.T) {
+		db, rec := newRecorderDB(t)
+		if _, err := NewAutomationRepository(db).ReleaseLease(context.Background(), task, ""); err != nil {
+			t.Fatal(err)
+		}
+		rec.last(t, "UPDATE").assertContains(t, "`run_automations`.`locked_by` IS NULL OR `run_automations`.`locked_by` = ?")
+	})
+}
+
+func TestDeleteUnlessLocked(t *testing.T) {
+	db, rec := newRecorderDB(t)
+	repo := NewAutomationRepository(db)
+
+	deleted, err := repo.DeleteUnlessLocked(context.Background(), "AUTO001")
+	if err != nil || !deleted {
+		t.Fatalf("DeleteUnlessLocked() = %v, %v, want deleted", deleted, err)
+	}
+	del := rec.last(t, "DELETE")
+	del.assertContains(t, "`run_automations`.`automation_id` = ?", "`run_automations`.`status` <> ?")
+	if !del.hasArg("LOCKED") {
+		t.Errorf("DeleteUnlessLocked() args = %v", del.args)
+	}
+
+	rec.rowsAffected = 0
+	if deleted, err := repo.DeleteUnlessLocked(context.Background(), "AUTO001"); err != nil || deleted {
+		t.Errorf("DeleteUnlessLocked() of a locked row = %v, %v, want not deleted", deleted, err)
+	}
+}
@@ -9,7 +9,10 @@ import (
 )
 
 type AutomationTargetRepository interface {
+	GenerateID() string
 	ListByAutomationID(ctx context.Context, automationID string) ([]*model.RunAutomationTarget, error)
+	BulkCreate(ctx context.Context, rows []*model.RunAutomationTarget) error
+	DeleteByAutomationID(ctx context.Context, automationID string) error
 }
 
 type automationTargetRepository struct {
@@ -22,6 +25,10 @@ func NewAutomationTargetRepository(db *gorm.DB) AutomationTargetRepository {
 	}
 }
 
+func (r *automationTargetRepository) GenerateID() string {
+	return r.GenerateSortableID(20)
+}
+
 func (r *automationTargetRepository) ListByAutomationID(ctx context.Context, automationID string) ([]*model.RunAutomationTarget, error) {
 	q := query.Use(r.Executor(ctx)).RunAutomationTarget
 	db := q.WithContext(ctx)
@@ -30,3 +37,18 @@ func (r *automationTargetRepository) ListByAutomationID(ctx context.Context, aut
 
 	return db.Find()
 }
+
+func (r *automationTargetRepository) BulkCreate(ctx context.Context, rows []*model.RunAutomationTarget) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return r.Executor(ctx).
+		Create(&rows).Error
+}
+
+func (r *automationTargetRepository) DeleteByAutomationID(ctx context.Context, automationID string) error {
+	q := query.Use(r.Executor(ctx)).RunAutomationTarget
+	_, err := q.WithContext(ctx).Where(q.AutomationID.Eq(automationID)).Delete()
+	return err
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/domain/query"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type BlackoutDateRepository interface {
+	GenerateID() string
+	Create(ctx context.Context, blackout *model.DefBlackoutDate) error
+	List(ctx context.Context, filter model.DefBlackoutDate) ([]*model.DefBlackoutDate, error)
+	ListActiveByCalendarCodes(ctx context.Context, calendarCodes []string) ([]*model.DefBlackoutDate, error)
+}
+
+type blackoutDateRepository struct {
+	BaseRepository
+}
+
+func NewBlackoutDateRepository(db *gorm.DB) BlackoutDateRepository {
+	return &blackoutDateRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *blackoutDateRepository) GenerateID() string {
+	return r.GenerateSortableID(20)
+}
+
+func (r *blackoutDateRepository) Create(ctx context.Context, blackout *model.DefBlackoutDate) error {
+	if blackout.BlackoutID == "" {
+		blackout.BlackoutID = r.GenerateID()
+	}
+
+	q := query.Use(r.Executor(ctx)).DefBlackoutDate
+	return q.WithContext(ctx).Create(blackout)
+}
+
+func (r *blackoutDateRepository) List(ctx context.Context, filter model.DefBlackoutDate) ([]*model.DefBlackoutDate, error) {
+	q := query.Use(r.Executor(ctx)).DefBlackoutDate
+	db := q.WithContext(ctx)
+
+	// Dynamic Filtering
+	if filter.CalendarCode != "" {
+		db = db.Where(q.CalendarCode.Eq(filter.CalendarCode))
+	}
+	if filter.Status != "" {
+		db = db.Where(q.Status.Eq(filter.Status))
+	}
+
+	return db.Order(q.CalendarCode, q.StartDate).Find()
+}
+
+func (r *blackoutDateRepository) ListActiveByCalendarCodes(ctx context.Context, calendarCodes []string) ([]*model.DefBlackoutDate, error) {
+	q := query.Use(r.Executor(ctx)).DefBlackoutDate
+	return q.WithContext(ctx).
+		Where(q.CalendarCode.In(calendarCodes...)).
+		Where(q.Status.Eq("ACTIVE")).
+		Order(q.StartDate).
+		Find()
+}
@@ -10,6 +10,7 @@ import (
 
 type ConditionActionRepository interface {
 	List(ctx context.Context, filter model.PolicyConditionAction) ([]*model.PolicyConditionAction, error)
+	ListByConditionIDs(ctx context.Context, conditionIDs []string) ([]*model.PolicyConditionAction, error)
 	DeleteByConditionID(ctx context.Context, conditionID string) error
 	BulkCreate(ctx context.Context, ops []*model.PolicyConditionAction) error
 }
@@ -31,6 +32,15 @@ func (r *conditionActionRepository) List(ctx context.Context, filter model.Polic
 	return db.Find()
 }
 
+func (r *conditionActionRepository) ListByConditionIDs(ctx context.Context, conditionIDs []string) ([]*model.PolicyConditionAction, error) {
+	q := query.Use(r.Executor(ctx)).PolicyConditionAction
+	db := q.WithContext(ctx)
+
+	db = db.Where(q.ConditionID.In(conditionIDs...))
+
+	return db.Find()
+}
+
 func (r *conditionActionRepository) DeleteByConditionID(ctx context.Context, conditionID string) error {
 	return r.Executor(ctx).
 		Where("condition_id = ?", conditionID).
@@ -10,6 +10,7 @@ import (
 
 type ConditionOperatorRepository interface {
 	List(ctx context.Context, filter model.PolicyConditionOperator) ([]*model.PolicyConditionOperator, error)
+	ListByConditionIDs(ctx context.Context, conditionIDs []string) ([]*model.PolicyConditionOperator, error)
 	DeleteByConditionID(ctx context.Context, conditionID string) error
 	BulkCreate(ctx context.Context, ops []*model.PolicyConditionOperator) error
 	// WithTransaction(ctx context.Context, fn func(txRepo ConditionOperatorRepository) error) error
@@ -32,6 +33,15 @@ func (r *conditionOperatorRepository) List(ctx context.Context, filter model.Pol
 	return db.Find()
 }
 
+func (r *conditionOperatorRepository) ListByConditionIDs(ctx context.Context, conditionIDs []string) ([]*model.PolicyConditionOperator, error) {
+	q := query.Use(r.Executor(ctx)).PolicyConditionOperator
+	db := q.WithContext(ctx)
+
+	db = db.Where(q.ConditionID.In(conditionIDs...))
+
+	return db.Find()
+}
+
 func (r *conditionOperatorRepository) DeleteByConditionID(ctx context.Context, conditionID string) error {
 	return r.Executor(ctx).
 		Where("condition_id = ?", conditionID).
@@ -9,6 +9,8 @@ import (
 )
 
 type ConditionRepository interface {
+	GetByID(ctx context.Context, id string) (*model.DefCondition, error)
+	ListByConditionIDs(ctx context.Context, conditionIDs []string) ([]*model.DefCondition, error)
 	List(ctx context.Context, filter model.DefCondition) ([]*model.DefCondition, error)
 }
 
@@ -22,6 +24,20 @@ func NewConditionRepository(db *gorm.DB) ConditionRepository {
 	}
 }
 
+func (r *conditionRepository) GetByID(ctx context.Context, id string) (*model.DefCondition, error) {
+	q := query.Use(r.Executor(ctx)).DefCondition
+	return q.WithContext(ctx).Where(q.ConditionID.Eq(id)).First()
+}
+
+func (r *conditionRepository) ListByConditionIDs(ctx context.Context, conditionIDs []string) ([]*model.DefCondition, error) {
+	q := query.Use(r.Executor(ctx)).DefCondition
+	db := q.WithContext(ctx)
+
+	db = db.Where(q.ConditionID.In(conditionIDs...))
+
+	return db.Find()
+}
+
 func (r *conditionRepository) List(ctx context.Context, filter model.DefCondition) ([]*model.DefCondition, error) {
 	q := query.Use(r.Executor(ctx)).DefCondition
 	db := q.WithContext(ctx)
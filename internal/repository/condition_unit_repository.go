@@ -10,6 +10,7 @@ import (
 
 type ConditionUnitRepository interface {
 	List(ctx context.Context, filter model.PolicyConditionUnit) ([]*model.PolicyConditionUnit, error)
+	ListByConditionIDs(ctx context.Context, conditionIDs []string) ([]*model.PolicyConditionUnit, error)
 	DeleteByConditionID(ctx context.Context, conditionID string) error
 	BulkCreate(ctx context.Context, ops []*model.PolicyConditionUnit) error
 	WithTransaction(ctx context.Context, fn func(txRepo ConditionUnitRepository) error) error
@@ -32,6 +33,15 @@ func (r *conditionUnitRepository) List(ctx context.Context, filter model.PolicyC
 	return db.Find()
 }
 
+func (r *conditionUnitRepository) ListByConditionIDs(ctx context.Context, conditionIDs []string) ([]*model.PolicyConditionUnit, error) {
+	q := query.Use(r.Executor(ctx)).PolicyConditionUnit
+	db := q.WithContext(ctx)
+
+	db = db.Where(q.ConditionID.In(conditionIDs...))
+
+	return db.Find()
+}
+
 func (r *conditionUnitRepository) DeleteByConditionID(ctx context.Context, conditionID string) error {
 	return r.Executor(ctx).
 		Where("condition_id = ?", conditionID).
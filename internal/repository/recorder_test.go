@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// statement is one SQL statement sent to the recorder
+type statement struct {
+	query string
+	args  []driver.NamedValue
+}
+
+// recorder is a database/sql driver that records every statement instead of talking to MySQL.
+// Exec reports rowsAffected, Query returns columns/rows.
+type recorder struct {
+	statements   []statement
+	rowsAffected int64
+	columns      []string
+	rows         [][]driver.Value
+}
+
+// newRecorderDB opens a gorm MySQL connection on top of a recorder
+func newRecorderDB(t *testing.T) (*gorm.DB, *recorder) {
+	t.Helper()
+	rec := &recorder{rowsAffected: 1}
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sql.OpenDB(rec),
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, rec
+}
+
+// last returns the last statement whose SQL starts with the given verb (SELECT, UPDATE, ...)
+func (r *recorder) last(t *testing.T, verb string) statement {
+	t.Helper()
+	for i := len(r.statements) - 1; i >= 0; i-- {
+		if strings.HasPrefix(r.statements[i].query, verb) {
+			return r.statements[i]
+		}
+	}
+	t.Fatalf("no %s statement was executed, got %v", verb, r.statements)
+	return statement{}
+}
+
+// assertContains checks that the statement SQL contains every fragment
+func (s statement) assertContains(t *testing.T, fragments ...string) {
+	t.Helper()
+	for _, fragment := range fragments {
+		if !strings.Contains(s.query, fragment) {
+			t.Errorf("SQL %q does not contain %q", s.query, fragment)
+		}
+	}
+}
+
+// hasArg reports whether value was bound as one of the statement arguments
+func (s statement) hasArg(value driver.Value) bool {
+	for _, arg := range s.args {
+		if arg.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *recorder) Connect(ctx context.Context) (driver.Conn, error) {
+	return recorderConn{r}, nil
+}
+
+func (r *recorder) Driver() driver.Driver {
+	return nil
+}
+
+type recorderConn struct{ r *recorder }
+
+func (c recorderConn) Prepare(query string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c recorderConn) Close() error                              { return nil }
+func (c recorderConn) Begin() (driver.Tx, error)                 { return c, nil }
+func (c recorderConn) Commit() error                             { return nil }
+func (c recorderConn) Rollback() error                           { return nil }
+
+func (c recorderConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.r.statements = append(c.r.statements, statement{query: query, args: args})
+	return driver.RowsAffected(c.r.rowsAffected), nil
+}
+
+func (c recorderConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.r.statements = append(c.r.statements, statement{query: query, args: args})
+	return &recorderRows{columns: c.r.columns, rows: c.r.rows}, nil
+}
+
+type recorderRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *recorderRows) Columns() []string { return r.columns }
+func (r *recorderRows) Close() error      { return nil }
+
+func (r *recorderRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// assertNotInsert fails when the statement could create a row
+func (s statement) assertNotInsert(t *testing.T) {
+	t.Helper()
+	if strings.HasPrefix(s.query, "INSERT") {
+		t.Errorf("unexpected INSERT: %s", s.query)
+	}
+}
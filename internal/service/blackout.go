@@ -0,0 +1,53 @@
+package service
+
+import (
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/utils"
+	"time"
+)
+
+const (
+	BlackoutSkip  = "skip"
+	BlackoutShift = "shift"
+
+	// จำนวนวันสูงสุดที่เลื่อนหาวันที่ไม่ใช่วันหยุด
+	maxBlackoutShiftDays = 366
+)
+
+// BlackoutCalendar holds the active def_blackout_dates ranges per calendar code.
+// A nil calendar has no blackout days.
+type BlackoutCalendar map[string][]*model.DefBlackoutDate
+
+func NewBlackoutCalendar(rows []*model.DefBlackoutDate) BlackoutCalendar {
+	calendar := BlackoutCalendar{}
+	for _, row := range rows {
+		calendar[row.CalendarCode] = append(calendar[row.CalendarCode], row)
+	}
+	return calendar
+}
+
+// IsBlackout reports whether the local date of t falls in a blackout range of the calendar (dates are inclusive)
+func (c BlackoutCalendar) IsBlackout(calendarCode string, t time.Time) bool {
+	if calendarCode == "" {
+		return false
+	}
+
+	day := t.Format("2006-01-02")
+	for _, row := range c[calendarCode] {
+		if day >= row.StartDate.Format("2006-01-02") && day <= row.EndDate.Format("2006-01-02") {
+			return true
+		}
+	}
+	return false
+}
+
+// shift moves a local time to the same wall-clock time on the first day that is not a blackout day
+func (c BlackoutCalendar) shift(calendarCode string, local time.Time, loc *time.Location) (time.Time, bool) {
+	for i := 1; i <= maxBlackoutShiftDays; i++ {
+		candidate := utils.DateIn(local.Year(), local.Month(), local.Day()+i, local.Hour(), local.Minute(), local.Second(), loc)
+		if !c.IsBlackout(calendarCode, candidate) {
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
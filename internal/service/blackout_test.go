@@ -0,0 +1,121 @@
+package service
+
+import (
+	"automation-engine/internal/domain/model"
+	"testing"
+	"time"
+)
+
+func blackoutDay(calendarCode string, from, to string) *model.DefBlackoutDate {
+	start, _ := time.Parse("2006-01-02", from)
+	end, _ := time.Parse("2006-01-02", to)
+	return &model.DefBlackoutDate{CalendarCode: calendarCode, StartDate: start, EndDate: end}
+}
+
+func TestIsBlackout(t *testing.T) {
+	calendar := NewBlackoutCalendar([]*model.DefBlackoutDate{blackoutDay("TH", "2024-04-13", "2024-04-15")})
+	bangkok, _ := time.LoadLocation("Asia/Bangkok")
+
+	tests := []struct {
+		name     string
+		calendar string
+		at       time.Time
+		want     bool
+	}{
+		{name: "first day", calendar: "TH", at: time.Date(2024, 4, 13, 0, 0, 0, 0, bangkok), want: true},
+		{name: "last day is inclusive", calendar: "TH", at: time.Date(2024, 4, 15, 23, 59, 0, 0, bangkok), want: true},
+		{name: "day after", calendar: "TH", at: time.Date(2024, 4, 16, 0, 0, 0, 0, bangkok), want: false},
+		{name: "other calendar", calendar: "SG", at: time.Date(2024, 4, 14, 9, 0, 0, 0, bangkok), want: false},
+		{name: "no calendar", calendar: "", at: time.Date(2024, 4, 14, 9, 0, 0, 0, bangkok), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calendar.IsBlackout(tt.calendar, tt.at); got != tt.want {
+				t.Errorf("IsBlackout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextScheduledRunEndConditionsAndBlackouts(t *testing.T) {
+	calendar := NewBlackoutCalendar([]*model.DefBlackoutDate{
+		blackoutDay("HOL", "2024-03-11", "2024-03-12"),
+		blackoutDay("MON", "2024-03-18", "2024-03-18"),
+	})
+	after := mustTime(t, "2024-03-10T03:00:00Z")
+
+	tests := []struct {
+		name   string
+		modify func(task *model.RunAutomation)
+		after  time.Time
+		want   string
+	}{
+		{
+			name:   "no blackout",
+			modify: func(task *model.RunAutomation) {},
+			want:   "2024-03-11T02:00:00Z",
+		},
+		{
+			name: "skip blackout days",
+			modify: func(task *model.RunAutomation) {
+				task.BlackoutCalendar, task.BlackoutRule = "HOL", BlackoutSkip
+			},
+			want: "2024-03-13T02:00:00Z",
+		},
+		{
+			name: "shift that reaches the next regular run is dropped",
+			modify: func(task *model.RunAutomation) {
+				task.BlackoutCalendar, task.BlackoutRule = "HOL", BlackoutShift
+			},
+			want: "2024-03-13T02:00:00Z",
+		},
+		{
+			name: "weekly run shifts to the next working day",
+			modify: func(task *model.RunAutomation) {
+				task.Frequency, task.DayOfWeek = "weekly", "mon"
+				task.BlackoutCalendar, task.BlackoutRule = "MON", BlackoutShift
+			},
+			after: mustTime(t, "2024-03-12T00:00:00Z"),
+			want:  "2024-03-19T02:00:00Z",
+		},
+		{
+			name: "end date reached",
+			modify: func(task *model.RunAutomation) {
+				task.EndDate = mustTime(t, "2024-03-11T00:00:00Z")
+			},
+			want: "",
+		},
+		{
+			name: "skipped up to the end date",
+			modify: func(task *model.RunAutomation) {
+				task.BlackoutCalendar, task.BlackoutRule = "HOL", BlackoutSkip
+				task.EndDate = mustTime(t, "2024-03-12T23:00:00Z")
+			},
+			want: "",
+		},
+		{
+			name: "max runs reached",
+			modify: func(task *model.RunAutomation) {
+				task.MaxRuns, task.RunCount = 3, 3
+			},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := bangkokDaily(t)
+			tt.modify(task)
+			from := after
+			if !tt.after.IsZero() {
+				from = tt.after
+			}
+
+			got, err := NextScheduledRun(task, from, calendar)
+			if err != nil {
+				t.Fatalf("NextScheduledRun() error = %v", err)
+			}
+			assertTime(t, got, tt.want)
+		})
+	}
+}
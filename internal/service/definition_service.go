@@ -2,10 +2,15 @@ package service
 
 import (
 	"automation-engine/internal/domain/model"
+	"automation-engine/internal/dto"
 	"automation-engine/internal/repository"
 	"context"
+	"errors"
+	"fmt"
 )
 
+var ErrInvalidAction = errors.New("invalid action")
+
 type DefinitionService interface {
 	// Group CRUD
 	// CreateGroup(ctx context.Context, group *model.DefGroup) error
@@ -15,7 +20,7 @@ type DefinitionService interface {
 
 	// Condition CRUD
 	// CreateCondition(ctx context.Context, condition *model.DefCondition) error
-	// GetConditionByID(ctx context.Context, id string) (*model.DefCondition, error)
+	GetConditionByID(ctx context.Context, id string) (*model.DefCondition, error)
 	// UpdateCondition(ctx context.Context, condition *model.DefCondition) error
 	// DeleteCondition(ctx context.Context, id string) error
 
@@ -23,6 +28,16 @@ type DefinitionService interface {
 	CreateAction(ctx context.Context, action *model.DefAction) error
 	GetActionByID(ctx context.Context, id string) (*model.DefAction, error)
 	ListActionByIDs(ctx context.Context, actionIDs []string) ([]*model.DefAction, error)
+
+	// Operator
+	ListOperators(ctx context.Context) ([]*model.DefOperator, error)
+
+	// Unit
+	ListUnits(ctx context.Context) ([]*model.DefUnit, error)
+
+	// Blackout Calendar
+	CreateBlackoutDate(ctx context.Context, blackout *model.DefBlackoutDate) error
+	ListBlackoutDates(ctx context.Context, calendarCode string) ([]*model.DefBlackoutDate, error)
 }
 
 type definitionService struct {
@@ -31,6 +46,7 @@ type definitionService struct {
 	conditionRepo repository.ConditionRepository
 	operatorRepo  repository.OperatorRepository
 	unitRepo      repository.UnitRepository
+	blackoutRepo  repository.BlackoutDateRepository
 }
 
 func NewDefinitionService(
@@ -39,6 +55,7 @@ func NewDefinitionService(
 	conditionRepo repository.ConditionRepository,
 	operatorRepo repository.OperatorRepository,
 	unitRepo repository.UnitRepository,
+	blackoutRepo repository.BlackoutDateRepository,
 ) DefinitionService {
 	return &definitionService{
 		txManager:     txManager,
@@ -46,10 +63,20 @@ func NewDefinitionService(
 		conditionRepo: conditionRepo,
 		operatorRepo:  operatorRepo,
 		unitRepo:      unitRepo,
+		blackoutRepo:  blackoutRepo,
 	}
 }
 
+func (s *definitionService) GetConditionByID(ctx context.Context, conditionID string) (*model.DefCondition, error) {
+	return s.conditionRepo.GetByID(ctx, conditionID)
+}
+
 func (s *definitionService) CreateAction(ctx context.Context, action *model.DefAction) error {
+	if action.PayloadTemplate != "" {
+		if _, err := dto.ParsePayloadTemplate(action.PayloadTemplate); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAction, err)
+		}
+	}
 	return s.actionRepo.Create(ctx, action)
 }
 
@@ -60,3 +87,19 @@ func (s *definitionService) GetActionByID(ctx context.Context, actionID string)
 func (s *definitionService) ListActionByIDs(ctx context.Context, actionIDs []string) ([]*model.DefAction, error) {
 	return s.actionRepo.ListByActionIDs(ctx, actionIDs)
 }
+
+func (s *definitionService) ListOperators(ctx context.Context) ([]*model.DefOperator, error) {
+	return s.operatorRepo.List(ctx, model.DefOperator{})
+}
+
+func (s *definitionService) ListUnits(ctx context.Context) ([]*model.DefUnit, error) {
+	return s.unitRepo.List(ctx, model.DefUnit{})
+}
+
+func (s *definitionService) CreateBlackoutDate(ctx context.Context, blackout *model.DefBlackoutDate) error {
+	return s.blackoutRepo.Create(ctx, blackout)
+}
+
+func (s *definitionService) ListBlackoutDates(ctx context.Context, calendarCode string) ([]*model.DefBlackoutDate, error) {
+	return s.blackoutRepo.List(ctx, model.DefBlackoutDate{CalendarCode: calendarCode})
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Lifecycle states of an automation (run_automations.lifecycle_state).
+// Only ACTIVE automations are picked up by the scheduler.
+const (
+	StateDraft    = "DRAFT"
+	StateActive   = "ACTIVE"
+	StatePaused   = "PAUSED"
+	StateArchived = "ARCHIVED"
+)
+
+var (
+	ErrInvalidTransition  = errors.New("invalid lifecycle transition")
+	ErrAutomationArchived = errors.New("automation is archived")
+	ErrLifecycleChanged   = errors.New("automation lifecycle state was changed concurrently")
+)
+
+// Lifecycle transitions, one per lifecycle endpoint (activate and resume both lead to ACTIVE from different states)
+const (
+	TransitionActivate = "activate"
+	TransitionPause    = "pause"
+	TransitionResume   = "resume"
+	TransitionArchive  = "archive"
+)
+
+type lifecycleTransition struct {
+	from []string
+	to   string
+}
+
+// lifecycleTransitions lists the source states and the target state of each transition (DRAFT → ACTIVE ↔ PAUSED → ARCHIVED)
+var lifecycleTransitions = map[string]lifecycleTransition{
+	TransitionActivate: {from: []string{StateDraft}, to: StateActive},
+	TransitionPause:    {from: []string{StateActive}, to: StatePaused},
+	TransitionResume:   {from: []string{StatePaused}, to: StateActive},
+	TransitionArchive:  {from: []string{StateDraft, StatePaused}, to: StateArchived},
+}
+
+// CheckTransition returns the state an automation in state from moves to, or ErrInvalidTransition when the
+// transition is unknown or not allowed from that state
+func CheckTransition(transition, from string) (string, error) {
+	rule, ok := lifecycleTransitions[transition]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown transition %s", ErrInvalidTransition, transition)
+	}
+	for _, state := range rule.from {
+		if state == from {
+			return rule.to, nil
+		}
+	}
+	return "", fmt.Errorf("%w: cannot %s a %s automation", ErrInvalidTransition, transition, from)
+}
+
+// isActiveFlag keeps the legacy is_active column in sync with the lifecycle state
+func isActiveFlag(state string) string {
+	if state == StateActive {
+		return "Y"
+	}
+	return "N"
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckTransition(t *testing.T) {
+	// transition → source state → target state, every other pair is invalid
+	allowed := map[string]map[string]string{
+		TransitionActivate: {StateDraft: StateActive},
+		TransitionPause:    {StateActive: StatePaused},
+		TransitionResume:   {StatePaused: StateActive},
+		TransitionArchive:  {StateDraft: StateArchived, StatePaused: StateArchived},
+	}
+	states := []string{StateDraft, StateActive, StatePaused, StateArchived}
+
+	for transition, targets := range allowed {
+		for _, from := range states {
+			got, err := CheckTransition(transition, from)
+			if want, ok := targets[from]; ok {
+				if err != nil || got != want {
+					t.Errorf("CheckTransition(%s, %s) = %s, %v, want %s", transition, from, got, err, want)
+				}
+				continue
+			}
+			if !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("CheckTransition(%s, %s) error = %v, want %v", transition, from, err, ErrInvalidTransition)
+			}
+		}
+	}
+
+	if _, err := CheckTransition("restart", StatePaused); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("unknown transition error = %v, want %v", err, ErrInvalidTransition)
+	}
+}
+
+func TestIsActiveFlag(t *testing.T) {
+	for state, want := range map[string]string{StateDraft: "N", StateActive: "Y", StatePaused: "N", StateArchived: "N"} {
+		if got := isActiveFlag(state); got != want {
+			t.Errorf("isActiveFlag(%s) = %s, want %s", state, got, want)
+		}
+	}
+}
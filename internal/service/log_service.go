@@ -4,27 +4,76 @@ import (
 	"automation-engine/internal/domain/model"
 	"automation-engine/internal/repository"
 	"context"
+	"encoding/json"
+	"errors"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultExecutionPageSize = 50
+	MaxExecutionPageSize     = 200
+
+	// จำนวน step ที่ sweeper จัดการต่อรอบ
+	expireStepBatchSize = 100
+
+	// StatusPartial is an execution whose fan-out action succeeded for some recipients and failed for others
+	StatusPartial = "PARTIAL"
 )
 
+var ErrStepNotRunning = errors.New("action execution is not running")
+
+// ExecutionClaim is the outcome of ClaimExecution
+type ExecutionClaim struct {
+	// Claimed is true when this worker may run the execution
+	Claimed bool
+	// Resumed is true when the execution ran before (retry or redelivery), steps that already succeeded must not be invoked again
+	Resumed bool
+	// BusyUntil is set when another worker holds the execution, the message should be looked at again after this time
+	BusyUntil time.Time
+}
+
+// StepResult is the outcome reported by an async action through the callback endpoint
+type StepResult struct {
+	Status       string
+	ResponseBody string
+	ErrorMessage string
+}
+
 type LogService interface {
 	GenerateLogID() string
+	GetExecution(ctx context.Context, logID string) (*model.LogAutomationExecution, error)
+	ListExecutions(ctx context.Context, filter repository.ExecutionFilter) ([]*model.LogAutomationExecution, string, error)
 	Upsert(ctx context.Context, log *model.LogAutomationExecution) error
+	ClaimExecution(ctx context.Context, log *model.LogAutomationExecution, claimedUntil time.Time) (ExecutionClaim, error)
+	MarkReplayed(ctx context.Context, logID string, replayLogID string) (bool, error)
+	UnmarkReplayed(ctx context.Context, logID string, replayLogID string) error
+	GenerateStepID() string
+	RecordStep(ctx context.Context, step *model.LogAutomationActionExecution) error
+	FinishDispatch(ctx context.Context, step *model.LogAutomationActionExecution) (bool, error)
+	CompleteStep(ctx context.Context, stepID string, result StepResult) error
+	SettleExecution(ctx context.Context, logID string) error
+	ExpireRunningSteps(ctx context.Context, now time.Time) (int, error)
+	ListSteps(ctx context.Context, logID string) ([]*model.LogAutomationActionExecution, error)
 	DeleteLogsBefore(ctx context.Context, t time.Time) error
 }
 
 type logService struct {
-	txManager               repository.TransactionManager
-	automationExecutionRepo repository.AutomationExecutionRepository
+	txManager                     repository.TransactionManager
+	automationExecutionRepo       repository.AutomationExecutionRepository
+	automationActionExecutionRepo repository.AutomationActionExecutionRepository
 }
 
 func NewLogService(
 	txManager repository.TransactionManager,
 	automationExecutionRepo repository.AutomationExecutionRepository,
+	automationActionExecutionRepo repository.AutomationActionExecutionRepository,
 ) LogService {
 	return &logService{
-		txManager:               txManager,
-		automationExecutionRepo: automationExecutionRepo,
+		txManager:                     txManager,
+		automationExecutionRepo:       automationExecutionRepo,
+		automationActionExecutionRepo: automationActionExecutionRepo,
 	}
 }
 
@@ -32,10 +81,326 @@ func (s *logService) GenerateLogID() string {
 	return s.automationExecutionRepo.GenerateLogID()
 }
 
+func (s *logService) GetExecution(ctx context.Context, logID string) (*model.LogAutomationExecution, error) {
+	return s.automationExecutionRepo.GetByID(ctx, logID)
+}
+
+// ListExecutions returns one page of executions and the cursor of the next page ("" when there is no more data)
+func (s *logService) ListExecutions(ctx context.Context, filter repository.ExecutionFilter) ([]*model.LogAutomationExecution, string, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultExecutionPageSize
+	}
+	if filter.Limit > MaxExecutionPageSize {
+		filter.Limit = MaxExecutionPageSize
+	}
+
+	// ดึงเกินมา 1 แถวเพื่อดูว่ายังมีหน้าถัดไปหรือไม่
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	rows, err := s.automationExecutionRepo.List(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(rows) <= pageSize {
+		return rows, "", nil
+	}
+
+	rows = rows[:pageSize]
+	return rows, rows[pageSize-1].LogID, nil
+}
+
 func (s *logService) Upsert(ctx context.Context, log *model.LogAutomationExecution) error {
 	return s.automationExecutionRepo.Upsert(ctx, log)
 }
 
+// ClaimExecution marks the execution RUNNING for the calling worker until claimedUntil.
+// Messages are delivered at least once, so the same LogID may arrive again after the execution finished or while another worker still runs it.
+// The worker writes the log back with an empty claimed_until when it is done.
+func (s *logService) ClaimExecution(ctx context.Context, log *model.LogAutomationExecution, claimedUntil time.Time) (ExecutionClaim, error) {
+	// 1. LogID ใหม่: insert แล้วทำงานได้เลย
+	claim := *log
+	claim.Status = "RUNNING"
+	claim.ClaimedUntil = claimedUntil
+	created, err := s.automationExecutionRepo.CreateIfNotExists(ctx, &claim)
+	if err != nil {
+		return ExecutionClaim{}, err
+	}
+	if created {
+		return ExecutionClaim{Claimed: true}, nil
+	}
+
+	// 2. LogID เคยถูกประมวลผลแล้ว: ล็อกแถวแล้วดูสถานะ
+	var result ExecutionClaim
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		existing, err := s.automationExecutionRepo.GetByIDForUpdate(txCtx, log.LogID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case !existing.FinishedAt.IsZero():
+			// จบไปแล้ว (SUCCESS, SKIPPED, FAILED ที่เข้า dead-letter)
+			return nil
+		case existing.Status == "RUNNING" && existing.ClaimedUntil.IsZero():
+			// worker เรียก action ครบแล้ว เหลือรอ callback ของ action แบบ async
+			return nil
+		case existing.Attempt > log.Attempt, existing.Attempt == log.Attempt && existing.Status == "RETRYING":
+			// message ของรอบที่ตั้งเวลา retry ไปแล้ว
+			return nil
+		case existing.ClaimedUntil.After(time.Now()):
+			result.BusyUntil = existing.ClaimedUntil
+			return nil
+		}
+
+		// 3. รอบ retry, message ที่ถูก abandon หรือ worker เดิมตายกลางทาง: ทำต่อจากเดิม
+		result.Claimed = true
+		result.Resumed = true
+		return s.automationExecutionRepo.Claim(txCtx, log.LogID, log.Attempt, claimedUntil)
+	})
+	return result, err
+}
+
+func (s *logService) MarkReplayed(ctx context.Context, logID string, replayLogID string) (bool, error) {
+	return s.automationExecutionRepo.MarkReplayed(ctx, logID, replayLogID)
+}
+
+func (s *logService) UnmarkReplayed(ctx context.Context, logID string, replayLogID string) error {
+	return s.automationExecutionRepo.UnmarkReplayed(ctx, logID, replayLogID)
+}
+
+func (s *logService) GenerateStepID() string {
+	return s.automationActionExecutionRepo.GenerateStepID()
+}
+
+func (s *logService) RecordStep(ctx context.Context, step *model.LogAutomationActionExecution) error {
+	return s.automationActionExecutionRepo.Create(ctx, step)
+}
+
+// FinishDispatch writes the HTTP result of an async step that was recorded as RUNNING before the request was sent.
+// It returns true when the callback (or the sweeper) closed the step first, the step then keeps that result.
+func (s *logService) FinishDispatch(ctx context.Context, dispatched *model.LogAutomationActionExecution) (bool, error) {
+	completed := false
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		step, err := s.automationActionExecutionRepo.GetByIDForUpdate(txCtx, dispatched.StepID)
+		if err != nil {
+			return err
+		}
+		if step.Status != "RUNNING" {
+			completed = true
+			step.HTTPStatus = dispatched.HTTPStatus
+			return s.automationActionExecutionRepo.Save(txCtx, step)
+		}
+		return s.automationActionExecutionRepo.Save(txCtx, dispatched)
+	})
+	return completed, err
+}
+
+// CompleteStep finishes a RUNNING (async) step and settles its execution once no step is left running
+func (s *logService) CompleteStep(ctx context.Context, stepID string, result StepResult) error {
+	return s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		step, err := s.automationActionExecutionRepo.GetByIDForUpdate(txCtx, stepID)
+		if err != nil {
+			return err
+		}
+		if step.Status != "RUNNING" {
+			return ErrStepNotRunning
+		}
+
+		// ล็อก log ก่อนอ่าน step อื่น เพื่อไม่ให้ callback ที่มาพร้อมกันต่างคนต่างคิดว่ายังมี step ค้างอยู่
+		if _, err := s.automationExecutionRepo.GetByIDForUpdate(txCtx, step.LogID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		step.Status = result.Status
+		step.ResponseBody = result.ResponseBody
+		step.ErrorMessage = result.ErrorMessage
+		if result.Status == "SUCCESS" {
+			// response ของ callback ใช้เป็น output ของ step ได้เหมือน action แบบ sync (เมื่อเป็น JSON object)
+			var output map[string]interface{}
+			if json.Unmarshal([]byte(result.ResponseBody), &output) == nil && output != nil {
+				step.OutputJSON = result.ResponseBody
+			}
+		}
+		step.FinishedAt = time.Now()
+		step.LatencyMs = step.FinishedAt.Sub(step.StartedAt).Milliseconds()
+		if err := s.automationActionExecutionRepo.Save(txCtx, step); err != nil {
+			return err
+		}
+
+		return s.settle(txCtx, step.LogID)
+	})
+}
+
+// SettleExecution closes a RUNNING execution whose steps are all finished.
+// A callback never settles an execution that a worker still holds, so the worker calls it after writing the log
+// back with an empty claimed_until.
+func (s *logService) SettleExecution(ctx context.Context, logID string) error {
+	return s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := s.automationExecutionRepo.GetByIDForUpdate(txCtx, logID); err != nil {
+			return err
+		}
+		return s.settle(txCtx, logID)
+	})
+}
+
+// ExpireRunningSteps fails async steps whose callback did not arrive before the deadline
+func (s *logService) ExpireRunningSteps(ctx context.Context, now time.Time) (int, error) {
+	steps, err := s.automationActionExecutionRepo.ListExpired(ctx, "RUNNING", now, expireStepBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, step := range steps {
+		err := s.CompleteStep(ctx, step.StepID, StepResult{
+			Status:       "FAILED",
+			ErrorMessage: "callback timeout",
+		})
+		if err != nil {
+			// callback อาจเข้ามาพอดีระหว่างนี้
+			if errors.Is(err, ErrStepNotRunning) {
+				continue
+			}
+			return expired, err
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// settle must run inside a transaction holding the log row lock
+func (s *logService) settle(ctx context.Context, logID string) error {
+	log, err := s.automationExecutionRepo.GetByID(ctx, logID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// worker ยังไม่ได้บันทึก log, worker จะเรียก SettleExecution เองหลังบันทึก
+			return nil
+		}
+		return err
+	}
+	if log.Status != "RUNNING" {
+		return nil
+	}
+	// worker ยังถือ claim อยู่ อาจยังเรียก action ถัดไปไม่ครบ, worker จะปล่อย claim แล้วเรียก SettleExecution เอง
+	// (claim ที่หมดเวลาก็ไม่ปิด เพราะ message ที่ถูกส่งซ้ำจะ resume ต่อจาก step ที่มีอยู่)
+	if !log.ClaimedUntil.IsZero() {
+		return nil
+	}
+
+	steps, err := s.automationActionExecutionRepo.ListByLogID(ctx, logID)
+	if err != nil {
+		return err
+	}
+
+	log.Status = "SUCCESS"
+	log.FinishedAt = time.Time{}
+	// ล้มเหลวเฉพาะผู้รับบางคนของ action แบบ fan-out
+	targetsOnly := true
+	for _, step := range latestAttempts(steps) {
+		if step.Status == "RUNNING" {
+			return nil
+		}
+		if step.Status == "FAILED" {
+			if log.Status != "FAILED" {
+				log.Status = "FAILED"
+				log.ErrorMessage = step.ErrorMessage
+			}
+			targetsOnly = targetsOnly && step.EmployeeID != ""
+		}
+		if step.FinishedAt.After(log.FinishedAt) {
+			log.FinishedAt = step.FinishedAt
+		}
+	}
+
+	RollUpTargets(log, steps)
+	if log.Status == "FAILED" && targetsOnly && log.TargetSucceeded > 0 {
+		log.Status = StatusPartial
+	}
+
+	return s.automationExecutionRepo.Upsert(ctx, log)
+}
+
+// RollUpTargets counts the latest step of every fan-out recipient (steps with an employee_id) onto the execution log
+func RollUpTargets(log *model.LogAutomationExecution, steps []*model.LogAutomationActionExecution) {
+	log.TargetTotal, log.TargetSucceeded, log.TargetFailed, log.TargetSkipped = 0, 0, 0, 0
+	for _, step := range latestAttempts(steps) {
+		if step.EmployeeID == "" {
+			continue
+		}
+
+		log.TargetTotal++
+		switch step.Status {
+		case "SUCCESS", "DRY_RUN":
+			log.TargetSucceeded++
+		case "FAILED":
+			log.TargetFailed++
+		case "SKIPPED":
+			log.TargetSkipped++
+		}
+	}
+}
+
+// StepKey identifies one call of an automation action within an execution (per recipient for fan-out actions)
+func StepKey(automationActionID string, employeeID string) string {
+	if employeeID == "" {
+		return automationActionID
+	}
+	return automationActionID + "/" + employeeID
+}
+
+// StepOutputs rebuilds the execution context of a resumed execution: the output of the latest successful attempt
+// of every SINGLE action, keyed by automation_action_id
+func StepOutputs(steps []*model.LogAutomationActionExecution) map[string]interface{} {
+	outputs := map[string]interface{}{}
+	for _, step := range latestAttempts(steps) {
+		if step.EmployeeID != "" || step.Status != "SUCCESS" || step.OutputJSON == "" {
+			continue
+		}
+
+		var output map[string]interface{}
+		if err := json.Unmarshal([]byte(step.OutputJSON), &output); err != nil || output == nil {
+			continue
+		}
+		outputs[step.AutomationActionID] = output
+	}
+	return outputs
+}
+
+// latestAttempts keeps the last attempt of each automation action (and fan-out recipient), earlier attempts were retried
+func latestAttempts(steps []*model.LogAutomationActionExecution) []*model.LogAutomationActionExecution {
+	latest := make(map[string]*model.LogAutomationActionExecution, len(steps))
+	order := make([]string, 0, len(steps))
+	for _, step := range steps {
+		key := StepKey(step.AutomationActionID, step.EmployeeID)
+		current, ok := latest[key]
+		if !ok {
+			order = append(order, key)
+		}
+		if !ok || step.Attempt > current.Attempt || (step.Attempt == current.Attempt && step.StartedAt.After(current.StartedAt)) {
+			latest[key] = step
+		}
+	}
+
+	result := make([]*model.LogAutomationActionExecution, 0, len(order))
+	for _, id := range order {
+		result = append(result, latest[id])
+	}
+	return result
+}
+
+func (s *logService) ListSteps(ctx context.Context, logID string) ([]*model.LogAutomationActionExecution, error) {
+	return s.automationActionExecutionRepo.ListByLogID(ctx, logID)
+}
+
 func (s *logService) DeleteLogsBefore(ctx context.Context, t time.Time) error {
-	return s.automationExecutionRepo.DeleteBefore(ctx, t)
+	return s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.automationActionExecutionRepo.DeleteBefore(txCtx, t); err != nil {
+			return err
+		}
+
+		return s.automationExecutionRepo.DeleteBefore(txCtx, t)
+	})
 }
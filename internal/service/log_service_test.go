@@ -0,0 +1,329 @@
+package service
+
+import (
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/repository"
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func step(actionID string, employeeID string, attempt int32, status string) *model.LogAutomationActionExecution {
+	return &model.LogAutomationActionExecution{
+		AutomationActionID: actionID,
+		EmployeeID:         employeeID,
+		Attempt:            attempt,
+		Status:             status,
+	}
+}
+
+func TestLatestAttempts(t *testing.T) {
+	started := time.Date(2024, 1, 15, 2, 0, 0, 0, time.UTC)
+	replayed := step("AA001", "", 2, "SUCCESS")
+	replayed.StartedAt = started.Add(time.Minute)
+	earlier := step("AA001", "", 2, "FAILED")
+	earlier.StartedAt = started
+
+	steps := []*model.LogAutomationActionExecution{
+		step("AA001", "", 1, "FAILED"),
+		step("AA002", "E001", 1, "SUCCESS"),
+		replayed,
+		earlier,
+		step("AA002", "E002", 1, "FAILED"),
+	}
+
+	got := latestAttempts(steps)
+	if len(got) != 3 {
+		t.Fatalf("latestAttempts() returned %d steps, want 3", len(got))
+	}
+	if got[0] != replayed {
+		t.Errorf("AA001 = attempt %d %s, want the later attempt 2", got[0].Attempt, got[0].Status)
+	}
+	if got[1].EmployeeID != "E001" || got[2].EmployeeID != "E002" {
+		t.Errorf("fan-out steps = %s, %s, want E001, E002 in log order", got[1].EmployeeID, got[2].EmployeeID)
+	}
+}
+
+func TestRollUpTargets(t *testing.T) {
+	log := &model.LogAutomationExecution{TargetTotal: 9, TargetFailed: 9}
+	RollUpTargets(log, []*model.LogAutomationActionExecution{
+		step("AA001", "", 1, "SUCCESS"),
+		step("AA002", "E001", 1, "FAILED"),
+		step("AA002", "E001", 2, "SUCCESS"),
+		step("AA002", "E002", 1, "FAILED"),
+		step("AA002", "E003", 1, "SKIPPED"),
+		step("AA002", "E004", 1, "DRY_RUN"),
+		step("AA002", "E005", 1, "RUNNING"),
+	})
+
+	if log.TargetTotal != 5 || log.TargetSucceeded != 2 || log.TargetFailed != 1 || log.TargetSkipped != 1 {
+		t.Errorf("RollUpTargets() total=%d succeeded=%d failed=%d skipped=%d, want 5/2/1/1",
+			log.TargetTotal, log.TargetSucceeded, log.TargetFailed, log.TargetSkipped)
+	}
+}
+
+func TestStepKey(t *testing.T) {
+	if got := StepKey("AA001", ""); got != "AA001" {
+		t.Errorf("StepKey() = %s, want AA001", got)
+	}
+	if got := StepKey("AA001", "E001"); got != "AA001/E001" {
+		t.Errorf("StepKey() = %s, want AA001/E001", got)
+	}
+}
+
+func TestStepOutputs(t *testing.T) {
+	withOutput := func(s *model.LogAutomationActionExecution, output string) *model.LogAutomationActionExecution {
+		s.OutputJSON = output
+		return s
+	}
+
+	outputs := StepOutputs([]*model.LogAutomationActionExecution{
+		withOutput(step("AA001", "", 1, "FAILED"), `{"document_id":"OLD"}`),
+		withOutput(step("AA001", "", 2, "SUCCESS"), `{"document_id":"DOC9"}`),
+		withOutput(step("AA002", "", 1, "FAILED"), `{"error":"timeout"}`),
+		withOutput(step("AA003", "E001", 1, "SUCCESS"), `{"sent":true}`),
+		withOutput(step("AA004", "", 1, "SUCCESS"), `not json`),
+		withOutput(step("AA005", "", 1, "SUCCESS"), ``),
+		withOutput(step("AA006", "", 2, "SUCCESS"), `{"id":1}`),
+		withOutput(step("AA006", "", 3, "FAILED"), `{"id":2}`),
+	})
+
+	if len(outputs) != 1 {
+		t.Fatalf("StepOutputs() = %v, want only AA001", outputs)
+	}
+	output, _ := outputs["AA001"].(map[string]interface{})
+	if output["document_id"] != "DOC9" {
+		t.Errorf("AA001 output = %v, want the latest successful attempt", outputs["AA001"])
+	}
+}
+
+type fakeTransactionManager struct{}
+
+func (fakeTransactionManager) WithTransaction(ctx context.Context, fn func(txCtx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeExecutionRepo keeps execution logs in memory, methods the tests do not use are left unimplemented
+type fakeExecutionRepo struct {
+	repository.AutomationExecutionRepository
+	logs map[string]*model.LogAutomationExecution
+	// onMarkReplayed runs once before the next MarkReplayed (to race another replay)
+	onMarkReplayed func()
+}
+
+func (r *fakeExecutionRepo) CreateIfNotExists(ctx context.Context, log *model.LogAutomationExecution) (bool, error) {
+	if _, ok := r.logs[log.LogID]; ok {
+		return false, nil
+	}
+	created := *log
+	r.logs[log.LogID] = &created
+	return true, nil
+}
+
+func (r *fakeExecutionRepo) GetByIDForUpdate(ctx context.Context, logID string) (*model.LogAutomationExecution, error) {
+	log := *r.logs[logID]
+	return &log, nil
+}
+
+func (r *fakeExecutionRepo) GetByID(ctx context.Context, logID string) (*model.LogAutomationExecution, error) {
+	return r.GetByIDForUpdate(ctx, logID)
+}
+
+func (r *fakeExecutionRepo) Upsert(ctx context.Context, log *model.LogAutomationExecution) error {
+	stored := *log
+	r.logs[log.LogID] = &stored
+	return nil
+}
+
+func (r *fakeExecutionRepo) Claim(ctx context.Context, logID string, attempt int32, claimedUntil time.Time) error {
+	log := r.logs[logID]
+	log.Status = "RUNNING"
+	log.Attempt = attempt
+	log.ClaimedUntil = claimedUntil
+	return nil
+}
+
+func TestClaimExecution(t *testing.T) {
+	now := time.Now()
+	claimedUntil := now.Add(10 * time.Minute)
+
+	tests := []struct {
+		name     string
+		existing *model.LogAutomationExecution
+		attempt  int32
+		want     ExecutionClaim
+	}{
+		{
+			name:    "new log id",
+			attempt: 1,
+			want:    ExecutionClaim{Claimed: true},
+		},
+		{
+			name:     "finished execution is not run again",
+			existing: &model.LogAutomationExecution{Status: "SUCCESS", Attempt: 1, FinishedAt: now.Add(-time.Minute)},
+			attempt:  1,
+			want:     ExecutionClaim{},
+		},
+		{
+			name:     "waiting for async callbacks",
+			existing: &model.LogAutomationExecution{Status: "RUNNING", Attempt: 1},
+			attempt:  1,
+			want:     ExecutionClaim{},
+		},
+		{
+			name:     "message of an older attempt",
+			existing: &model.LogAutomationExecution{Status: "RUNNING", Attempt: 2, ClaimedUntil: now.Add(-time.Minute)},
+			attempt:  1,
+			want:     ExecutionClaim{},
+		},
+		{
+			name:     "retry already scheduled for this attempt",
+			existing: &model.LogAutomationExecution{Status: "RETRYING", Attempt: 2},
+			attempt:  2,
+			want:     ExecutionClaim{},
+		},
+		{
+			name:     "held by another worker",
+			existing: &model.LogAutomationExecution{Status: "RUNNING", Attempt: 1, ClaimedUntil: now.Add(5 * time.Minute)},
+			attempt:  1,
+			want:     ExecutionClaim{BusyUntil: now.Add(5 * time.Minute)},
+		},
+		{
+			name:     "worker died mid-way",
+			existing: &model.LogAutomationExecution{Status: "RUNNING", Attempt: 1, ClaimedUntil: now.Add(-time.Minute)},
+			attempt:  1,
+			want:     ExecutionClaim{Claimed: true, Resumed: true},
+		},
+		{
+			name:     "retry message",
+			existing: &model.LogAutomationExecution{Status: "RETRYING", Attempt: 1},
+			attempt:  2,
+			want:     ExecutionClaim{Claimed: true, Resumed: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeExecutionRepo{logs: map[string]*model.LogAutomationExecution{}}
+			if tt.existing != nil {
+				tt.existing.LogID = "LOG001"
+				repo.logs["LOG001"] = tt.existing
+			}
+			svc := NewLogService(fakeTransactionManager{}, repo, nil)
+
+			got, err := svc.ClaimExecution(context.Background(), &model.LogAutomationExecution{LogID: "LOG001", Attempt: tt.attempt}, claimedUntil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Claimed != tt.want.Claimed || got.Resumed != tt.want.Resumed || !got.BusyUntil.Equal(tt.want.BusyUntil) {
+				t.Fatalf("ClaimExecution() = %+v, want %+v", got, tt.want)
+			}
+
+			stored := repo.logs["LOG001"]
+			if got.Claimed && (stored.Status != "RUNNING" || !stored.ClaimedUntil.Equal(claimedUntil) || stored.Attempt != tt.attempt) {
+				t.Errorf("claimed log = %s attempt %d until %s", stored.Status, stored.Attempt, stored.ClaimedUntil)
+			}
+			if !got.Claimed && tt.existing != nil && stored.ClaimedUntil.Equal(claimedUntil) {
+				t.Error("log was claimed without running")
+			}
+		})
+	}
+}
+
+// fakeStepRepo keeps action steps in memory in the order they were recorded
+type fakeStepRepo struct {
+	repository.AutomationActionExecutionRepository
+	steps []*model.LogAutomationActionExecution
+}
+
+func (r *fakeStepRepo) GetByIDForUpdate(ctx context.Context, stepID string) (*model.LogAutomationActionExecution, error) {
+	for _, step := range r.steps {
+		if step.StepID == stepID {
+			stored := *step
+			return &stored, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeStepRepo) Create(ctx context.Context, step *model.LogAutomationActionExecution) error {
+	stored := *step
+	r.steps = append(r.steps, &stored)
+	return nil
+}
+
+func (r *fakeStepRepo) Save(ctx context.Context, step *model.LogAutomationActionExecution) error {
+	for i, stored := range r.steps {
+		if stored.StepID == step.StepID {
+			saved := *step
+			r.steps[i] = &saved
+			return nil
+		}
+	}
+	return r.Create(ctx, step)
+}
+
+func (r *fakeStepRepo) ListByLogID(ctx context.Context, logID string) ([]*model.LogAutomationActionExecution, error) {
+	result := make([]*model.LogAutomationActionExecution, 0, len(r.steps))
+	for _, step := range r.steps {
+		if step.LogID == logID {
+			result = append(result, step)
+		}
+	}
+	return result, nil
+}
+
+func TestSettleWaitsForWorkerClaim(t *testing.T) {
+	ctx := context.Background()
+	executions := &fakeExecutionRepo{logs: map[string]*model.LogAutomationExecution{}}
+	steps := &fakeStepRepo{}
+	svc := NewLogService(fakeTransactionManager{}, executions, steps)
+
+	// 1. worker จอง execution แล้วส่ง action แบบ async ตัวแรก
+	claim, err := svc.ClaimExecution(ctx, &model.LogAutomationExecution{LogID: "LOG001", Attempt: 1}, time.Now().Add(10*time.Minute))
+	if err != nil || !claim.Claimed {
+		t.Fatalf("ClaimExecution() = %+v, %v", claim, err)
+	}
+	started := time.Now()
+	async := &model.LogAutomationActionExecution{StepID: "S1", LogID: "LOG001", AutomationActionID: "AA001", Attempt: 1, Status: "RUNNING", StartedAt: started}
+	if err := svc.RecordStep(ctx, async); err != nil {
+		t.Fatal(err)
+	}
+
+	// 2. callback มาถึงก่อนที่ worker จะตอบกลับจาก HTTP call และก่อนเรียก action ถัดไป
+	if err := svc.CompleteStep(ctx, "S1", StepResult{Status: "SUCCESS", ResponseBody: `{"ok":true}`}); err != nil {
+		t.Fatal(err)
+	}
+	if log := executions.logs["LOG001"]; log.Status != "RUNNING" || !log.FinishedAt.IsZero() {
+		t.Fatalf("early callback settled the claimed execution: %s finished at %s", log.Status, log.FinishedAt)
+	}
+
+	// 3. worker บันทึกผลของ HTTP call แล้วเรียก action ถัดไป (ล้มเหลว)
+	dispatched := *async
+	dispatched.HTTPStatus = 202
+	completed, err := svc.FinishDispatch(ctx, &dispatched)
+	if err != nil || !completed {
+		t.Fatalf("FinishDispatch() = %v, %v, want the callback result kept", completed, err)
+	}
+	next := &model.LogAutomationActionExecution{StepID: "S2", LogID: "LOG001", AutomationActionID: "AA002", Attempt: 1, Status: "FAILED", ErrorMessage: "boom", StartedAt: started, FinishedAt: started.Add(time.Second)}
+	if err := svc.RecordStep(ctx, next); err != nil {
+		t.Fatal(err)
+	}
+
+	// 4. worker ปล่อย claim แล้ว settle
+	if err := svc.Upsert(ctx, &model.LogAutomationExecution{LogID: "LOG001", Attempt: 1, Status: "RUNNING"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SettleExecution(ctx, "LOG001"); err != nil {
+		t.Fatal(err)
+	}
+
+	log := executions.logs["LOG001"]
+	if log.Status != "FAILED" || log.ErrorMessage != "boom" || log.FinishedAt.IsZero() {
+		t.Errorf("settled execution = %s %q finished at %s, want FAILED from the later action", log.Status, log.ErrorMessage, log.FinishedAt)
+	}
+	if step, _ := steps.GetByIDForUpdate(ctx, "S1"); step.Status != "SUCCESS" || step.HTTPStatus != 202 {
+		t.Errorf("async step = %s %d, want SUCCESS with the dispatch status", step.Status, step.HTTPStatus)
+	}
+}
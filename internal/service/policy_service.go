@@ -11,6 +11,7 @@ type PolicyService interface {
 	SetConditionOperators(ctx context.Context, conditionID string, operators []*model.PolicyConditionOperator, createdBy string) error
 	SetConditionUnits(ctx context.Context, conditionID string, units []*model.PolicyConditionUnit, createdBy string) error
 	SetConditionActions(ctx context.Context, conditionID string, actions []*model.PolicyConditionAction, createdBy string) error
+	ValidateAutomation(ctx context.Context, conditions []*model.RunAutomationCondition, actions []*model.RunAutomationAction) error
 }
 
 type policyService struct {
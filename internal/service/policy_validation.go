@@ -0,0 +1,158 @@
+package service
+
+import (
+	"automation-engine/internal/domain/model"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PolicyViolation describes one field of an automation that is not allowed by the policy_condition_* rules
+type PolicyViolation struct {
+	AutomationConditionID string   `json:"automation_condition_id,omitempty"`
+	ConditionID           string   `json:"condition_id"`
+	Field                 string   `json:"field"`
+	Value                 string   `json:"value"`
+	Allowed               []string `json:"allowed"`
+}
+
+// PolicyViolationError is returned when an automation breaks one or more policy rules
+type PolicyViolationError struct {
+	Violations []PolicyViolation
+}
+
+func (e *PolicyViolationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, fmt.Sprintf("condition %s: %s %q is not allowed", v.ConditionID, v.Field, v.Value))
+	}
+	return "policy violation: " + strings.Join(parts, "; ")
+}
+
+// ValidateAutomation checks every condition of an automation against policy_condition_operators/units/actions.
+// A nil error means the automation is allowed; rule violations are returned as *PolicyViolationError.
+func (s *policyService) ValidateAutomation(ctx context.Context, conditions []*model.RunAutomationCondition, actions []*model.RunAutomationAction) error {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	conditionIDs := make([]string, 0, len(conditions))
+	for _, cond := range conditions {
+		conditionIDs = append(conditionIDs, cond.ConditionID)
+	}
+
+	defConditions, err := s.conditionRepo.ListByConditionIDs(ctx, conditionIDs)
+	if err != nil {
+		return err
+	}
+	conditionOperators, err := s.conditionOperatorRepo.ListByConditionIDs(ctx, conditionIDs)
+	if err != nil {
+		return err
+	}
+	conditionUnits, err := s.conditionUnitRepo.ListByConditionIDs(ctx, conditionIDs)
+	if err != nil {
+		return err
+	}
+	conditionActions, err := s.conditionActionRepo.ListByConditionIDs(ctx, conditionIDs)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(defConditions))
+	for _, c := range defConditions {
+		known[c.ConditionID] = c.Status == "" || c.Status == "ACTIVE"
+	}
+
+	allowedOperators := make(map[string]map[string]bool)
+	for _, r := range conditionOperators {
+		addRule(allowedOperators, r.ConditionID, r.OperatorID)
+	}
+	allowedUnits := make(map[string]map[string]bool)
+	for _, r := range conditionUnits {
+		addRule(allowedUnits, r.ConditionID, r.UnitID)
+	}
+	allowedActions := make(map[string]map[string]bool)
+	for _, r := range conditionActions {
+		addRule(allowedActions, r.ConditionID, r.ActionID)
+	}
+
+	var violations []PolicyViolation
+	checkedActions := make(map[string]bool)
+
+	for _, cond := range conditions {
+		if !known[cond.ConditionID] {
+			violations = append(violations, PolicyViolation{
+				AutomationConditionID: cond.AutomationConditionID,
+				ConditionID:           cond.ConditionID,
+				Field:                 "condition_id",
+				Value:                 cond.ConditionID,
+				Allowed:               []string{},
+			})
+			continue
+		}
+
+		// operator ต้องได้รับอนุญาตเสมอ
+		if !allowedOperators[cond.ConditionID][cond.OperatorID] {
+			violations = append(violations, PolicyViolation{
+				AutomationConditionID: cond.AutomationConditionID,
+				ConditionID:           cond.ConditionID,
+				Field:                 "operator_id",
+				Value:                 cond.OperatorID,
+				Allowed:               ruleValues(allowedOperators[cond.ConditionID]),
+			})
+		}
+
+		// unit ว่างได้เฉพาะ condition ที่ไม่มี unit ใน policy
+		units := allowedUnits[cond.ConditionID]
+		if (cond.UnitID != "" && !units[cond.UnitID]) || (cond.UnitID == "" && len(units) > 0) {
+			violations = append(violations, PolicyViolation{
+				AutomationConditionID: cond.AutomationConditionID,
+				ConditionID:           cond.ConditionID,
+				Field:                 "unit_id",
+				Value:                 cond.UnitID,
+				Allowed:               ruleValues(units),
+			})
+		}
+
+		// ทุก action ของ automation ต้องได้รับอนุญาตจากทุก condition
+		for _, action := range actions {
+			key := cond.ConditionID + "|" + action.ActionID
+			if checkedActions[key] {
+				continue
+			}
+			checkedActions[key] = true
+
+			if !allowedActions[cond.ConditionID][action.ActionID] {
+				violations = append(violations, PolicyViolation{
+					ConditionID: cond.ConditionID,
+					Field:       "action_id",
+					Value:       action.ActionID,
+					Allowed:     ruleValues(allowedActions[cond.ConditionID]),
+				})
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PolicyViolationError{Violations: violations}
+	}
+
+	return nil
+}
+
+func addRule(rules map[string]map[string]bool, conditionID, value string) {
+	if rules[conditionID] == nil {
+		rules[conditionID] = make(map[string]bool)
+	}
+	rules[conditionID][value] = true
+}
+
+func ruleValues(rule map[string]bool) []string {
+	values := make([]string, 0, len(rule))
+	for v := range rule {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
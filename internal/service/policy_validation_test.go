@@ -0,0 +1,178 @@
+package service
+
+import (
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/repository"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type fakeConditionRepo struct {
+	repository.ConditionRepository
+	conditions []*model.DefCondition
+}
+
+func (r *fakeConditionRepo) ListByConditionIDs(ctx context.Context, conditionIDs []string) ([]*model.DefCondition, error) {
+	return r.conditions, nil
+}
+
+type fakeConditionOperatorRepo struct {
+	repository.ConditionOperatorRepository
+	rules []*model.PolicyConditionOperator
+}
+
+func (r *fakeConditionOperatorRepo) ListByConditionIDs(ctx context.Context, conditionIDs []string) ([]*model.PolicyConditionOperator, error) {
+	return r.rules, nil
+}
+
+type fakeConditionUnitRepo struct {
+	repository.ConditionUnitRepository
+	rules []*model.PolicyConditionUnit
+}
+
+func (r *fakeConditionUnitRepo) ListByConditionIDs(ctx context.Context, conditionIDs []string) ([]*model.PolicyConditionUnit, error) {
+	return r.rules, nil
+}
+
+type fakeConditionActionRepo struct {
+	repository.ConditionActionRepository
+	rules []*model.PolicyConditionAction
+}
+
+func (r *fakeConditionActionRepo) ListByConditionIDs(ctx context.Context, conditionIDs []string) ([]*model.PolicyConditionAction, error) {
+	return r.rules, nil
+}
+
+// newPolicyTestService allows:
+//
+//	C_AGE  (ACTIVE)   operators OP_GT, OP_LT  units U_YEAR, U_MONTH  actions ACT_MAIL
+//	C_DEPT (ACTIVE)   operators OP_EQ         no unit              actions ACT_MAIL, ACT_SMS
+//	C_OLD  (INACTIVE) operators OP_EQ
+func newPolicyTestService() PolicyService {
+	return NewPolicyService(nil,
+		&fakeConditionRepo{conditions: []*model.DefCondition{
+			{ConditionID: "C_AGE", Status: "ACTIVE"},
+			{ConditionID: "C_DEPT", Status: "ACTIVE"},
+			{ConditionID: "C_OLD", Status: "INACTIVE"},
+		}},
+		nil, nil, nil,
+		&fakeConditionOperatorRepo{rules: []*model.PolicyConditionOperator{
+			{ConditionID: "C_AGE", OperatorID: "OP_LT"},
+			{ConditionID: "C_AGE", OperatorID: "OP_GT"},
+			{ConditionID: "C_DEPT", OperatorID: "OP_EQ"},
+			{ConditionID: "C_OLD", OperatorID: "OP_EQ"},
+		}},
+		&fakeConditionUnitRepo{rules: []*model.PolicyConditionUnit{
+			{ConditionID: "C_AGE", UnitID: "U_YEAR"},
+			{ConditionID: "C_AGE", UnitID: "U_MONTH"},
+		}},
+		&fakeConditionActionRepo{rules: []*model.PolicyConditionAction{
+			{ConditionID: "C_AGE", ActionID: "ACT_MAIL"},
+			{ConditionID: "C_DEPT", ActionID: "ACT_MAIL"},
+			{ConditionID: "C_DEPT", ActionID: "ACT_SMS"},
+		}},
+	)
+}
+
+func TestValidateAutomation(t *testing.T) {
+	mail := []*model.RunAutomationAction{{ActionID: "ACT_MAIL"}}
+	ageCondition := func(operatorID, unitID string) *model.RunAutomationCondition {
+		return &model.RunAutomationCondition{AutomationConditionID: "AC1", ConditionID: "C_AGE", OperatorID: operatorID, UnitID: unitID}
+	}
+
+	tests := []struct {
+		name       string
+		conditions []*model.RunAutomationCondition
+		actions    []*model.RunAutomationAction
+		want       []PolicyViolation
+	}{
+		{
+			name:       "allowed",
+			conditions: []*model.RunAutomationCondition{ageCondition("OP_GT", "U_YEAR"), {AutomationConditionID: "AC2", ConditionID: "C_DEPT", OperatorID: "OP_EQ"}},
+			actions:    mail,
+		},
+		{
+			name:    "no conditions",
+			actions: []*model.RunAutomationAction{{ActionID: "ACT_ANY"}},
+		},
+		{
+			name:       "disallowed operator",
+			conditions: []*model.RunAutomationCondition{ageCondition("OP_EQ", "U_YEAR")},
+			actions:    mail,
+			want: []PolicyViolation{
+				{AutomationConditionID: "AC1", ConditionID: "C_AGE", Field: "operator_id", Value: "OP_EQ", Allowed: []string{"OP_GT", "OP_LT"}},
+			},
+		},
+		{
+			name:       "missing unit where one is required",
+			conditions: []*model.RunAutomationCondition{ageCondition("OP_GT", "")},
+			actions:    mail,
+			want: []PolicyViolation{
+				{AutomationConditionID: "AC1", ConditionID: "C_AGE", Field: "unit_id", Value: "", Allowed: []string{"U_MONTH", "U_YEAR"}},
+			},
+		},
+		{
+			name:       "disallowed unit",
+			conditions: []*model.RunAutomationCondition{ageCondition("OP_GT", "U_DAY")},
+			actions:    mail,
+			want: []PolicyViolation{
+				{AutomationConditionID: "AC1", ConditionID: "C_AGE", Field: "unit_id", Value: "U_DAY", Allowed: []string{"U_MONTH", "U_YEAR"}},
+			},
+		},
+		{
+			name:       "unit given where none is allowed",
+			conditions: []*model.RunAutomationCondition{{AutomationConditionID: "AC2", ConditionID: "C_DEPT", OperatorID: "OP_EQ", UnitID: "U_YEAR"}},
+			actions:    mail,
+			want: []PolicyViolation{
+				{AutomationConditionID: "AC2", ConditionID: "C_DEPT", Field: "unit_id", Value: "U_YEAR", Allowed: []string{}},
+			},
+		},
+		{
+			name:       "disallowed action is reported once per condition",
+			conditions: []*model.RunAutomationCondition{ageCondition("OP_GT", "U_YEAR"), ageCondition("OP_LT", "U_MONTH")},
+			actions:    []*model.RunAutomationAction{{ActionID: "ACT_MAIL"}, {ActionID: "ACT_SMS"}, {ActionID: "ACT_SMS"}},
+			want: []PolicyViolation{
+				{ConditionID: "C_AGE", Field: "action_id", Value: "ACT_SMS", Allowed: []string{"ACT_MAIL"}},
+			},
+		},
+		{
+			name:       "unknown condition",
+			conditions: []*model.RunAutomationCondition{{AutomationConditionID: "AC3", ConditionID: "C_NONE", OperatorID: "OP_EQ"}},
+			actions:    mail,
+			want: []PolicyViolation{
+				{AutomationConditionID: "AC3", ConditionID: "C_NONE", Field: "condition_id", Value: "C_NONE", Allowed: []string{}},
+			},
+		},
+		{
+			name:       "inactive condition",
+			conditions: []*model.RunAutomationCondition{{AutomationConditionID: "AC4", ConditionID: "C_OLD", OperatorID: "OP_EQ"}},
+			actions:    mail,
+			want: []PolicyViolation{
+				{AutomationConditionID: "AC4", ConditionID: "C_OLD", Field: "condition_id", Value: "C_OLD", Allowed: []string{}},
+			},
+		},
+	}
+
+	svc := newPolicyTestService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.ValidateAutomation(context.Background(), tt.conditions, tt.actions)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ValidateAutomation() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var policyErr *PolicyViolationError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("ValidateAutomation() error = %v, want *PolicyViolationError", err)
+			}
+			if !reflect.DeepEqual(policyErr.Violations, tt.want) {
+				t.Errorf("Violations = %+v, want %+v", policyErr.Violations, tt.want)
+			}
+		})
+	}
+}
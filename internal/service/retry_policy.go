@@ -0,0 +1,104 @@
+package service
+
+import (
+	"automation-engine/internal/domain/model"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRetryBackoff is the delay before the first retry when the action does not set one
+	DefaultRetryBackoff = 30 * time.Second
+	// DefaultRetryMaxBackoff caps the exponential backoff when the action does not set a cap
+	DefaultRetryMaxBackoff = time.Hour
+)
+
+// RetryPolicy is the retry configuration of a DefAction.
+// Attempts are counted per execution (the attempt number carried in the message).
+type RetryPolicy struct {
+	MaxAttempts int32
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	// StatusCodes are the HTTP statuses worth retrying, empty means 408, 429 and 5xx
+	StatusCodes map[int]bool
+}
+
+// ActionRetryPolicy reads the retry columns of a DefAction, filling in the defaults
+func ActionRetryPolicy(action *model.DefAction) (RetryPolicy, error) {
+	policy := RetryPolicy{
+		MaxAttempts: action.RetryMaxAttempts,
+		Backoff:     time.Duration(action.RetryBackoffSeconds) * time.Second,
+		MaxBackoff:  time.Duration(action.RetryMaxBackoffSeconds) * time.Second,
+	}
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	if policy.Backoff <= 0 {
+		policy.Backoff = DefaultRetryBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultRetryMaxBackoff
+	}
+
+	codes, err := ParseRetryStatusCodes(action.RetryStatusCodes)
+	if err != nil {
+		return RetryPolicy{}, fmt.Errorf("invalid retry_status_codes of action %s: %w", action.ActionID, err)
+	}
+	policy.StatusCodes = codes
+	return policy, nil
+}
+
+// ParseRetryStatusCodes parses a comma separated list such as "429,502,503"
+func ParseRetryStatusCodes(value string) (map[int]bool, error) {
+	codes := map[int]bool{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		code, err := strconv.Atoi(part)
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("%q is not an HTTP status code", part)
+		}
+		codes[code] = true
+	}
+	return codes, nil
+}
+
+// FormatRetryStatusCodes is the reverse of ParseRetryStatusCodes
+func FormatRetryStatusCodes(codes []int) string {
+	parts := make([]string, 0, len(codes))
+	for _, code := range codes {
+		parts = append(parts, strconv.Itoa(code))
+	}
+	return strings.Join(parts, ",")
+}
+
+// IsRetryableStatus reports whether a failed response with this status should be retried
+func (p RetryPolicy) IsRetryableStatus(code int) bool {
+	if len(p.StatusCodes) > 0 {
+		return p.StatusCodes[code]
+	}
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+}
+
+// CanRetry reports whether another attempt is allowed after the given attempt failed
+func (p RetryPolicy) CanRetry(attempt int32) bool {
+	return attempt < p.MaxAttempts
+}
+
+// Delay is the exponential backoff before the attempt that follows the failed one (attempt is 1-based)
+func (p RetryPolicy) Delay(attempt int32) time.Duration {
+	delay := p.Backoff
+	for i := int32(1); i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
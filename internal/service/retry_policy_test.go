@@ -0,0 +1,73 @@
+package service
+
+import (
+	"automation-engine/internal/domain/model"
+	"testing"
+	"time"
+)
+
+func TestActionRetryPolicyDefaults(t *testing.T) {
+	policy, err := ActionRetryPolicy(&model.DefAction{ActionID: "ACT001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.MaxAttempts != 1 || policy.Backoff != DefaultRetryBackoff || policy.MaxBackoff != DefaultRetryMaxBackoff {
+		t.Errorf("ActionRetryPolicy() = %+v, want defaults", policy)
+	}
+	if policy.CanRetry(1) {
+		t.Error("an action without retry_max_attempts was retried")
+	}
+
+	if _, err := ActionRetryPolicy(&model.DefAction{ActionID: "ACT001", RetryStatusCodes: "429,abc"}); err == nil {
+		t.Error("invalid retry_status_codes accepted")
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 6, Backoff: 10 * time.Second, MaxBackoff: time.Minute}
+
+	want := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, delay := range want {
+		attempt := int32(i + 1)
+		if got := policy.Delay(attempt); got != delay {
+			t.Errorf("Delay(%d) = %s, want %s", attempt, got, delay)
+		}
+	}
+
+	if !policy.CanRetry(5) || policy.CanRetry(6) {
+		t.Error("CanRetry() does not stop at MaxAttempts")
+	}
+}
+
+func TestRetryPolicyIsRetryableStatus(t *testing.T) {
+	defaults := RetryPolicy{}
+	for code, want := range map[int]bool{408: true, 429: true, 500: true, 503: true, 400: false, 404: false, 409: false} {
+		if got := defaults.IsRetryableStatus(code); got != want {
+			t.Errorf("default IsRetryableStatus(%d) = %v, want %v", code, got, want)
+		}
+	}
+
+	codes, err := ParseRetryStatusCodes(" 429, 502 ,,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom := RetryPolicy{StatusCodes: codes}
+	for code, want := range map[int]bool{429: true, 502: true, 500: false, 503: false} {
+		if got := custom.IsRetryableStatus(code); got != want {
+			t.Errorf("custom IsRetryableStatus(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestParseRetryStatusCodes(t *testing.T) {
+	for _, value := range []string{"abc", "99", "600", "429;503"} {
+		if _, err := ParseRetryStatusCodes(value); err == nil {
+			t.Errorf("ParseRetryStatusCodes(%q) succeeded, want error", value)
+		}
+	}
+
+	codes, err := ParseRetryStatusCodes(FormatRetryStatusCodes([]int{429, 503}))
+	if err != nil || len(codes) != 2 || !codes[429] || !codes[503] {
+		t.Errorf("round trip = %v, %v", codes, err)
+	}
+}
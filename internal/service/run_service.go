@@ -1,20 +1,41 @@
 package service
 
 import (
+	"automation-engine/internal/directory"
 	"automation-engine/internal/domain/model"
 	"automation-engine/internal/dto"
 	"automation-engine/internal/repository"
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
+var (
+	ErrInvalidAutomation = errors.New("invalid automation")
+	// ErrAutomationLocked means a scheduler is dispatching the automation right now
+	ErrAutomationLocked = errors.New("automation is locked by the scheduler")
+)
+
 type RunService interface {
+	// Automation CRUD
+	CreateAutomation(ctx context.Context, snapshot *dto.AutomationSnapshot, createdBy string) (*dto.AutomationSnapshot, error)
+	ReplaceAutomation(ctx context.Context, automationID string, snapshot *dto.AutomationSnapshot, updatedBy string) (*dto.AutomationSnapshot, error)
+	DeleteAutomation(ctx context.Context, automationID string) error
+	ListAutomations(ctx context.Context, filter model.RunAutomation) ([]*model.RunAutomation, error)
+	TransitionAutomation(ctx context.Context, automationID string, transition string, updatedBy string) (*model.RunAutomation, error)
+
+	ValidatePolicy(ctx context.Context, snapshot *dto.AutomationSnapshot) error
+
 	GetAutomationByID(ctx context.Context, automationID string) (*model.RunAutomation, error)
 	GetAutomationSnapshot(ctx context.Context, automationID string) (*dto.AutomationSnapshot, error)
 	UpdateAutomationByID(ctx context.Context, automation *model.RunAutomation) error
-	FetchAndLockTasks(ctx context.Context, runTime time.Time, limit int) ([]*model.RunAutomation, error)
+	FetchAndLockTasks(ctx context.Context, runTime time.Time, limit int, lockedBy string, lease time.Duration) ([]*model.RunAutomation, error)
+	ReclaimExpiredLeases(ctx context.Context, now time.Time) (int, error)
+	LoadBlackoutCalendar(ctx context.Context, automations []*model.RunAutomation) (BlackoutCalendar, error)
+	PreviewSchedule(ctx context.Context, automation *model.RunAutomation, count int) ([]time.Time, error)
 	MarkTasksCompleted(ctx context.Context, taskIDs []string) error
-	BulkUpdateNextRun(ctx context.Context, tasks []*model.RunAutomation) error
+	BulkUpdateNextRun(ctx context.Context, tasks []*model.RunAutomation, lockedBy string) ([]string, error)
 }
 
 type runService struct {
@@ -25,6 +46,8 @@ type runService struct {
 	automationConditionRepo      repository.AutomationConditionRepository
 	automationTargetRepo         repository.AutomationTargetRepository
 	automationExecutionRepo      repository.AutomationExecutionRepository
+	blackoutDateRepo             repository.BlackoutDateRepository
+	policyService                PolicyService
 }
 
 func NewRunService(
@@ -35,6 +58,8 @@ func NewRunService(
 	automationConditionRepo repository.AutomationConditionRepository,
 	automationTargetRepo repository.AutomationTargetRepository,
 	automationExecutionRepo repository.AutomationExecutionRepository,
+	blackoutDateRepo repository.BlackoutDateRepository,
+	policyService PolicyService,
 ) RunService {
 	return &runService{
 		txManager:                    txManager,
@@ -44,7 +69,452 @@ func NewRunService(
 		automationConditionRepo:      automationConditionRepo,
 		automationTargetRepo:         automationTargetRepo,
 		automationExecutionRepo:      automationExecutionRepo,
+		blackoutDateRepo:             blackoutDateRepo,
+		policyService:                policyService,
+	}
+}
+
+func (s *runService) CreateAutomation(ctx context.Context, snapshot *dto.AutomationSnapshot, createdBy string) (*dto.AutomationSnapshot, error) {
+	automation := snapshot.Automation
+	automation.AutomationID = s.automationRepo.GenerateID()
+	automation.Status = "PENDING"
+	automation.CreatedBy = createdBy
+	automation.LastUpdBy = createdBy
+	// automation ใหม่เริ่มได้แค่ DRAFT หรือ ACTIVE
+	if automation.LifecycleState == "" {
+		automation.LifecycleState = StateActive
+	}
+	if automation.LifecycleState != StateDraft && automation.LifecycleState != StateActive {
+		return nil, fmt.Errorf("%w: a new automation must be %s or %s", ErrInvalidAutomation, StateDraft, StateActive)
+	}
+	automation.IsActive = isActiveFlag(automation.LifecycleState)
+	if automation.MisfirePolicy == "" {
+		automation.MisfirePolicy = MisfireFireOnceNow
+	}
+	if automation.BlackoutRule == "" {
+		automation.BlackoutRule = BlackoutSkip
+	}
+	automation.RunCount = 0
+
+	if err := s.prepareSchedule(ctx, automation); err != nil {
+		return nil, err
+	}
+
+	if err := validateActionConfigs(snapshot.Actions); err != nil {
+		return nil, err
+	}
+
+	if err := validateExecutionModes(snapshot.Actions); err != nil {
+		return nil, err
+	}
+
+	if err := validateTargets(snapshot.Targets); err != nil {
+		return nil, err
+	}
+
+	if err := s.ValidatePolicy(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.automationRepo.Create(txCtx, automation); err != nil {
+			return err
+		}
+
+		return s.createChildren(txCtx, snapshot, createdBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAutomationSnapshot(ctx, automation.AutomationID)
+}
+
+// ReplaceAutomation overwrites the automation and replaces all of its condition groups, conditions, actions and targets
+func (s *runService) ReplaceAutomation(ctx context.Context, automationID string, snapshot *dto.AutomationSnapshot, updatedBy string) (*dto.AutomationSnapshot, error) {
+	current, err := s.automationRepo.GetByID(ctx, automationID)
+	if err != nil {
+		return nil, err
+	}
+	if current.LifecycleState == StateArchived {
+		return nil, ErrAutomationArchived
+	}
+
+	automation := snapshot.Automation
+	automation.AutomationID = automationID
+	// status, run_count และ lease เป็นของ scheduler, state เปลี่ยนผ่าน TransitionAutomation เท่านั้น (ไม่ถูกเขียนทับใน updateGuarded)
+	automation.Status = current.Status
+	automation.LifecycleState = current.LifecycleState
+	automation.IsActive = current.IsActive
+	automation.LockedBy = current.LockedBy
+	automation.LockExpiresAt = current.LockExpiresAt
+	automation.Created = current.Created
+	automation.CreatedBy = current.CreatedBy
+	automation.LastUpd = time.Now()
+	automation.LastUpdBy = updatedBy
+	if automation.MisfirePolicy == "" {
+		automation.MisfirePolicy = current.MisfirePolicy
+	}
+	if automation.BlackoutRule == "" {
+		automation.BlackoutRule = current.BlackoutRule
+	}
+	automation.RunCount = current.RunCount
+
+	// งานที่ถูก LOCKED อยู่จะได้ NextRunTime ใหม่ตอน scheduler อัปเดตรอบถัดไป
+	if err := s.prepareSchedule(ctx, automation); err != nil {
+		return nil, err
+	}
+
+	if err := validateActionConfigs(snapshot.Actions); err != nil {
+		return nil, err
+	}
+
+	if err := validateExecutionModes(snapshot.Actions); err != nil {
+		return nil, err
+	}
+
+	if err := validateTargets(snapshot.Targets); err != nil {
+		return nil, err
+	}
+
+	if err := s.ValidatePolicy(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.updateGuarded(txCtx, automation, current.LifecycleState, definitionColumns...); err != nil {
+			return err
+		}
+
+		if err := s.deleteChildren(txCtx, automationID); err != nil {
+			return err
+		}
+
+		return s.createChildren(txCtx, snapshot, updatedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAutomationSnapshot(ctx, automationID)
+}
+
+func (s *runService) DeleteAutomation(ctx context.Context, automationID string) error {
+	if _, err := s.automationRepo.GetByID(ctx, automationID); err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		// scheduler ที่ถือ lease อยู่จะเขียนรอบถัดไปกลับมา ลบตอนนี้ไม่ได้ (ลองใหม่หลัง scheduler ปล่อยงาน)
+		deleted, err := s.automationRepo.DeleteUnlessLocked(txCtx, automationID)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return ErrAutomationLocked
+		}
+
+		return s.deleteChildren(txCtx, automationID)
+	})
+}
+
+func (s *runService) ListAutomations(ctx context.Context, filter model.RunAutomation) ([]*model.RunAutomation, error) {
+	return s.automationRepo.List(ctx, filter)
+}
+
+// TransitionAutomation applies a lifecycle transition (activate, pause, resume, archive) to an automation.
+// Becoming ACTIVE recomputes NextRunTime from now, so runs missed while paused are not fired.
+func (s *runService) TransitionAutomation(ctx context.Context, automationID string, transition string, updatedBy string) (*model.RunAutomation, error) {
+	automation, err := s.automationRepo.GetByID(ctx, automationID)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := CheckTransition(transition, automation.LifecycleState)
+	if err != nil {
+		return nil, err
+	}
+
+	if state == StateActive {
+		blackouts, err := s.LoadBlackoutCalendar(ctx, []*model.RunAutomation{automation})
+		if err != nil {
+			return nil, err
+		}
+
+		nextRun, err := CalculateFirstRun(automation, time.Now(), blackouts)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAutomation, err)
+		}
+		automation.NextRunTime = nextRun
+	}
+	columns := []string{"lifecycle_state", "is_active", "last_upd", "last_upd_by"}
+	if state == StateActive {
+		columns = append(columns, "next_run_time")
+	}
+
+	from := automation.LifecycleState
+	automation.LifecycleState = state
+	automation.IsActive = isActiveFlag(state)
+	automation.LastUpd = time.Now()
+	automation.LastUpdBy = updatedBy
+
+	if err := s.updateGuarded(ctx, automation, from, columns...); err != nil {
+		return nil, err
+	}
+
+	// อ่านใหม่เพื่อให้ได้ next_run_time/run_count ล่าสุดที่ scheduler อาจเพิ่งเขียน
+	return s.automationRepo.GetByID(ctx, automationID)
+}
+
+// definitionColumns are the run_automations columns ReplaceAutomation may overwrite
+var definitionColumns = []string{
+	"instance_server_id", "instance_server_channel_id", "automation_name",
+	"frequency", "start_date", "day_of_week", "day_of_month", "month_of_year", "cron_expression", "time_zone",
+	"end_date", "max_runs", "misfire_policy", "blackout_calendar", "blackout_rule",
+	"next_run_time", "last_upd", "last_upd_by",
+}
+
+// updateGuarded writes only the given columns while the automation is still in expectedState.
+// ErrLifecycleChanged means another request changed the lifecycle state after it was read.
+func (s *runService) updateGuarded(ctx context.Context, automation *model.RunAutomation, expectedState string, columns ...string) error {
+	updated, err := s.automationRepo.UpdateColumns(ctx, automation, expectedState, columns...)
+	if err != nil || updated {
+		return err
+	}
+
+	// ไม่มีแถวเปลี่ยน: state ถูกเปลี่ยนไปแล้ว หรือค่าเหมือนเดิมทุกคอลัมน์ (MySQL นับเฉพาะแถวที่ค่าเปลี่ยน)
+	current, err := s.automationRepo.GetByID(ctx, automation.AutomationID)
+	if err != nil {
+		return err
+	}
+	if current.LifecycleState != expectedState {
+		return fmt.Errorf("%w: %s → %s", ErrLifecycleChanged, expectedState, current.LifecycleState)
 	}
+	return nil
+}
+
+// prepareSchedule validates the schedule fields of an automation being saved and sets its first NextRunTime
+func (s *runService) prepareSchedule(ctx context.Context, automation *model.RunAutomation) error {
+	if err := validateSchedule(automation); err != nil {
+		return err
+	}
+
+	blackouts, err := s.LoadBlackoutCalendar(ctx, []*model.RunAutomation{automation})
+	if err != nil {
+		return err
+	}
+
+	nextRun, err := CalculateFirstRun(automation, time.Now(), blackouts)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAutomation, err)
+	}
+	automation.NextRunTime = nextRun
+	return nil
+}
+
+// validateSchedule checks the schedule fields that binding tags cannot express
+func validateSchedule(automation *model.RunAutomation) error {
+	if automation.Frequency != "cron" {
+		automation.CronExpression = ""
+	} else if _, err := ParseCronExpression(automation.CronExpression); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAutomation, err)
+	}
+	if _, err := AutomationLocation(automation); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAutomation, err)
+	}
+	if !automation.EndDate.IsZero() && automation.EndDate.Before(automation.StartDate) {
+		return fmt.Errorf("%w: end_date must not be before start_date", ErrInvalidAutomation)
+	}
+	if automation.MaxRuns < 0 {
+		return fmt.Errorf("%w: max_runs must not be negative", ErrInvalidAutomation)
+	}
+	return nil
+}
+
+// PreviewSchedule returns the next fire times of a saved or unsaved automation without changing anything
+func (s *runService) PreviewSchedule(ctx context.Context, automation *model.RunAutomation, count int) ([]time.Time, error) {
+	if err := validateSchedule(automation); err != nil {
+		return nil, err
+	}
+	if count <= 0 {
+		count = DefaultPreviewCount
+	}
+	if count > MaxPreviewCount {
+		count = MaxPreviewCount
+	}
+
+	blackouts, err := s.LoadBlackoutCalendar(ctx, []*model.RunAutomation{automation})
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := PreviewRuns(automation, time.Now(), count, blackouts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAutomation, err)
+	}
+	return runs, nil
+}
+
+// LoadBlackoutCalendar loads the blackout dates of every calendar used by the automations
+func (s *runService) LoadBlackoutCalendar(ctx context.Context, automations []*model.RunAutomation) (BlackoutCalendar, error) {
+	seen := map[string]bool{}
+	var codes []string
+	for _, automation := range automations {
+		if automation.BlackoutCalendar != "" && !seen[automation.BlackoutCalendar] {
+			seen[automation.BlackoutCalendar] = true
+			codes = append(codes, automation.BlackoutCalendar)
+		}
+	}
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.blackoutDateRepo.ListActiveByCalendarCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	return NewBlackoutCalendar(rows), nil
+}
+
+// validateActionConfigs checks that every ConfigJSON can be merged into the action payload
+// and that its payload_template (if any) parses
+func validateActionConfigs(actions []*model.RunAutomationAction) error {
+	for i, action := range actions {
+		config, err := dto.ParseActionConfig(action.ConfigJSON)
+		if err != nil {
+			return fmt.Errorf("%w: actions[%d]: %v", ErrInvalidAutomation, i, err)
+		}
+
+		tmpl, err := dto.ConfigPayloadTemplate(config)
+		if err == nil && tmpl != "" {
+			_, err = dto.ParsePayloadTemplate(tmpl)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: actions[%d]: %v", ErrInvalidAutomation, i, err)
+		}
+	}
+	return nil
+}
+
+// validateExecutionModes defaults execution_mode to SINGLE and checks the fan-out concurrency
+func validateExecutionModes(actions []*model.RunAutomationAction) error {
+	for i, action := range actions {
+		if action.ExecutionMode == "" {
+			action.ExecutionMode = ExecutionModeSingle
+		}
+		if action.ExecutionMode != ExecutionModeSingle && action.ExecutionMode != ExecutionModeFanOut {
+			return fmt.Errorf("%w: actions[%d]: execution_mode must be %s or %s", ErrInvalidAutomation, i, ExecutionModeSingle, ExecutionModeFanOut)
+		}
+		if action.FanOutConcurrency < 0 || action.FanOutConcurrency > MaxFanOutConcurrency {
+			return fmt.Errorf("%w: actions[%d]: fan_out_concurrency must be between 0 and %d", ErrInvalidAutomation, i, MaxFanOutConcurrency)
+		}
+	}
+	return nil
+}
+
+// validateTargets defaults target_mode to INCLUDE and checks that every target selects something.
+// EXCLUDE targets only narrow the INCLUDE targets, so they cannot stand alone.
+func validateTargets(targets []*model.RunAutomationTarget) error {
+	included := false
+	for i, target := range targets {
+		if target.TargetMode == "" {
+			target.TargetMode = TargetInclude
+		}
+		if target.TargetMode != TargetInclude && target.TargetMode != TargetExclude {
+			return fmt.Errorf("%w: targets[%d]: target_mode must be %s or %s", ErrInvalidAutomation, i, TargetInclude, TargetExclude)
+		}
+		if directory.SelectorFromTarget(target).IsEmpty() {
+			return fmt.Errorf("%w: targets[%d]: at least one selector field is required", ErrInvalidAutomation, i)
+		}
+		included = included || target.TargetMode == TargetInclude
+	}
+
+	if len(targets) > 0 && !included {
+		return fmt.Errorf("%w: targets need at least one %s target", ErrInvalidAutomation, TargetInclude)
+	}
+	return nil
+}
+
+// createChildren assigns IDs to every child row of the snapshot and inserts them
+func (s *runService) createChildren(ctx context.Context, snapshot *dto.AutomationSnapshot, createdBy string) error {
+	automationID := snapshot.Automation.AutomationID
+
+	// map group id ที่ client ส่งมา (อาจเป็น id ชั่วคราว) → id จริง
+	groupIDs := make(map[string]string, len(snapshot.ConditionGroups))
+	for _, group := range snapshot.ConditionGroups {
+		newID := s.automationConditionGroupRepo.GenerateID()
+		groupIDs[group.AutomationConditionGroupID] = newID
+
+		group.AutomationConditionGroupID = newID
+		group.AutomationID = automationID
+		group.CreatedBy = createdBy
+		group.LastUpdBy = createdBy
+	}
+
+	for _, cond := range snapshot.Conditions {
+		groupID, ok := groupIDs[cond.AutomationConditionGroupID]
+		if !ok {
+			return fmt.Errorf("%w: condition references unknown group %s", ErrInvalidAutomation, cond.AutomationConditionGroupID)
+		}
+
+		cond.AutomationConditionID = s.automationConditionRepo.GenerateID()
+		cond.AutomationConditionGroupID = groupID
+		cond.CreatedBy = createdBy
+		cond.LastUpdBy = createdBy
+	}
+
+	for _, action := range snapshot.Actions {
+		action.AutomationActionID = s.automationActionRepo.GenerateID()
+		action.AutomationID = automationID
+		action.CreatedBy = createdBy
+		action.LastUpdBy = createdBy
+	}
+
+	for _, target := range snapshot.Targets {
+		target.AutomationTargetID = s.automationTargetRepo.GenerateID()
+		target.AutomationID = automationID
+		target.CreatedBy = createdBy
+		target.LastUpdBy = createdBy
+	}
+
+	if err := s.automationConditionGroupRepo.BulkCreate(ctx, snapshot.ConditionGroups); err != nil {
+		return err
+	}
+	if err := s.automationConditionRepo.BulkCreate(ctx, snapshot.Conditions); err != nil {
+		return err
+	}
+	if err := s.automationActionRepo.BulkCreate(ctx, snapshot.Actions); err != nil {
+		return err
+	}
+	return s.automationTargetRepo.BulkCreate(ctx, snapshot.Targets)
+}
+
+func (s *runService) deleteChildren(ctx context.Context, automationID string) error {
+	groups, err := s.automationConditionGroupRepo.ListByAutomationID(ctx, automationID)
+	if err != nil {
+		return err
+	}
+
+	var groupIDs []string
+	for _, group := range groups {
+		groupIDs = append(groupIDs, group.AutomationConditionGroupID)
+	}
+
+	if err := s.automationConditionRepo.DeleteByGroupIDs(ctx, groupIDs); err != nil {
+		return err
+	}
+	if err := s.automationConditionGroupRepo.DeleteByAutomationID(ctx, automationID); err != nil {
+		return err
+	}
+	if err := s.automationActionRepo.DeleteByAutomationID(ctx, automationID); err != nil {
+		return err
+	}
+	return s.automationTargetRepo.DeleteByAutomationID(ctx, automationID)
+}
+
+// ValidatePolicy checks the conditions and actions of the snapshot against the policy_condition_* rules
+func (s *runService) ValidatePolicy(ctx context.Context, snapshot *dto.AutomationSnapshot) error {
+	return s.policyService.ValidateAutomation(ctx, snapshot.Conditions, snapshot.Actions)
 }
 
 func (s *runService) GetAutomationByID(ctx context.Context, automationID string) (*model.RunAutomation, error) {
@@ -107,7 +577,9 @@ func (s *runService) UpdateAutomationByID(ctx context.Context, automation *model
 	return nil
 }
 
-func (s *runService) FetchAndLockTasks(ctx context.Context, runTime time.Time, limit int) ([]*model.RunAutomation, error) {
+// FetchAndLockTasks leases due automations to one scheduler. A lease that is not released by BulkUpdateNextRun
+// before it expires (crash, bus failure) is reclaimed by ReclaimExpiredLeases.
+func (s *runService) FetchAndLockTasks(ctx context.Context, runTime time.Time, limit int, lockedBy string, lease time.Duration) ([]*model.RunAutomation, error) {
 	var tasks []*model.RunAutomation
 
 	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
@@ -127,8 +599,8 @@ func (s *runService) FetchAndLockTasks(ctx context.Context, runTime time.Time, l
 			ids = append(ids, t.AutomationID)
 		}
 
-		// 3. เปลี่ยนสถานะเป็น LOCKED ทันที (จองงาน)
-		if err := s.automationRepo.UpdateStatusBatch(txCtx, ids, "LOCKED"); err != nil {
+		// 3. เปลี่ยนสถานะเป็น LOCKED ทันที (จองงาน) พร้อมเวลาหมดอายุของ lease
+		if err := s.automationRepo.AcquireLease(txCtx, ids, lockedBy, runTime.Add(lease)); err != nil {
 			return err
 		}
 
@@ -164,6 +636,43 @@ func (s *runService) FetchAndLockTasks(ctx context.Context, runTime time.Time, l
 	return tasks, err
 }
 
+// ReclaimExpiredLeases puts automations whose lease expired back to PENDING and returns how many were recovered.
+// NextRunTime is kept so the next scheduler tick treats the interrupted run as a misfire.
+func (s *runService) ReclaimExpiredLeases(ctx context.Context, now time.Time) (int, error) {
+	recovered := 0
+
+	for {
+		var tasks []*model.RunAutomation
+
+		err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+			expired, err := s.automationRepo.FetchExpiredLeases(txCtx, now, 100)
+			if err != nil || len(expired) == 0 {
+				return err
+			}
+
+			// แถวถูกล็อก FOR UPDATE อยู่ คืน lease ในชื่อของ scheduler เดิม
+			for _, task := range expired {
+				task.Status = "PENDING"
+				task.LastUpd = now
+				if _, err := s.automationRepo.ReleaseLease(txCtx, task, task.LockedBy); err != nil {
+					return err
+				}
+			}
+
+			tasks = expired
+			return nil
+		})
+		if err != nil {
+			return recovered, err
+		}
+
+		if len(tasks) == 0 {
+			return recovered, nil
+		}
+		recovered += len(tasks)
+	}
+}
+
 func (s *runService) MarkTasksCompleted(ctx context.Context, taskIDs []string) error {
 	if len(taskIDs) == 0 {
 		return nil
@@ -176,12 +685,25 @@ func (s *runService) MarkTasksCompleted(ctx context.Context, taskIDs []string) e
 	return nil
 }
 
-func (s *runService) BulkUpdateNextRun(ctx context.Context, tasks []*model.RunAutomation) error {
+// BulkUpdateNextRun writes the next run of every task back and releases the leases held by lockedBy.
+// It returns the IDs of tasks whose lease was lost in the meantime, those rows are left untouched.
+func (s *runService) BulkUpdateNextRun(ctx context.Context, tasks []*model.RunAutomation, lockedBy string) ([]string, error) {
+	var lost []string
 	if len(tasks) == 0 {
-		return nil
+		return lost, nil
 	}
 
-	return s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
-		return s.automationRepo.BulkUpdateNextRun(txCtx, tasks)
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		for _, task := range tasks {
+			released, err := s.automationRepo.ReleaseLease(txCtx, task, lockedBy)
+			if err != nil {
+				return err
+			}
+			if !released {
+				lost = append(lost, task.AutomationID)
+			}
+		}
+		return nil
 	})
+	return lost, err
 }
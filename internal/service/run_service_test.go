@@ -0,0 +1,197 @@
+package service
+
+import (
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/repository"
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeAutomationRepo keeps run_automations in memory with the lease semantics of the SQL repository
+type fakeAutomationRepo struct {
+	repository.AutomationRepository
+	rows map[string]*model.RunAutomation
+}
+
+func (r *fakeAutomationRepo) sortedIDs() []string {
+	ids := make([]string, 0, len(r.rows))
+	for id := range r.rows {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (r *fakeAutomationRepo) FetchAndLock(ctx context.Context, runTime time.Time, limit int) ([]*model.RunAutomation, error) {
+	result := make([]*model.RunAutomation, 0)
+	for _, id := range r.sortedIDs() {
+		row := r.rows[id]
+		if row.Status == "PENDING" && row.LifecycleState == StateActive && !row.NextRunTime.IsZero() && !row.NextRunTime.After(runTime) && len(result) < limit {
+			task := *row
+			result = append(result, &task)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeAutomationRepo) AcquireLease(ctx context.Context, ids []string, lockedBy string, expiresAt time.Time) error {
+	for _, id := range ids {
+		r.rows[id].Status = "LOCKED"
+		r.rows[id].LockedBy = lockedBy
+		r.rows[id].LockExpiresAt = expiresAt
+	}
+	return nil
+}
+
+func (r *fakeAutomationRepo) FetchExpiredLeases(ctx context.Context, now time.Time, limit int) ([]*model.RunAutomation, error) {
+	result := make([]*model.RunAutomation, 0)
+	for _, id := range r.sortedIDs() {
+		row := r.rows[id]
+		if row.Status == "LOCKED" && (row.LockExpiresAt.IsZero() || row.LockExpiresAt.Before(now)) && len(result) < limit {
+			task := *row
+			result = append(result, &task)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeAutomationRepo) ReleaseLease(ctx context.Context, task *model.RunAutomation, lockedBy string) (bool, error) {
+	row, ok := r.rows[task.AutomationID]
+	if !ok || row.Status != "LOCKED" || row.LockedBy != lockedBy {
+		return false, nil
+	}
+	row.Status = task.Status
+	row.NextRunTime = task.NextRunTime
+	row.RunCount = task.RunCount
+	row.LastUpd = task.LastUpd
+	row.LockedBy = ""
+	row.LockExpiresAt = time.Time{}
+	return true, nil
+}
+
+func newLeaseTestService(rows ...*model.RunAutomation) (RunService, *fakeAutomationRepo) {
+	repo := &fakeAutomationRepo{rows: map[string]*model.RunAutomation{}}
+	for _, row := range rows {
+		repo.rows[row.AutomationID] = row
+	}
+	return NewRunService(fakeTransactionManager{}, repo, nil, nil, nil, nil, nil, nil, nil), repo
+}
+
+func TestSchedulerLeaseLifecycle(t *testing.T) {
+	ctx := context.Background()
+	runTime := mustTime(t, "2024-01-15T02:00:00Z")
+	nextRun := mustTime(t, "2024-01-16T02:00:00Z")
+	svc, repo := newLeaseTestService(
+		&model.RunAutomation{AutomationID: "AUTO001", Status: "PENDING", LifecycleState: StateActive, NextRunTime: runTime},
+		&model.RunAutomation{AutomationID: "AUTO002", Status: "PENDING", LifecycleState: StateActive, NextRunTime: runTime},
+		&model.RunAutomation{AutomationID: "AUTO003", Status: "PENDING", LifecycleState: StatePaused, NextRunTime: runTime},
+	)
+
+	// 1. scheduler-1 จองงานที่ถึงเวลา
+	tasks, err := svc.FetchAndLockTasks(ctx, runTime, 100, "scheduler-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("FetchAndLockTasks() leased %d tasks, want 2", len(tasks))
+	}
+	if row := repo.rows["AUTO001"]; row.Status != "LOCKED" || row.LockedBy != "scheduler-1" || !row.LockExpiresAt.Equal(runTime.Add(time.Minute)) {
+		t.Errorf("leased row = %s by %q until %s", row.Status, row.LockedBy, row.LockExpiresAt)
+	}
+
+	// 2. lease ยังไม่หมดอายุ reaper ไม่แตะ
+	if recovered, err := svc.ReclaimExpiredLeases(ctx, runTime.Add(30*time.Second)); err != nil || recovered != 0 {
+		t.Fatalf("ReclaimExpiredLeases() before expiry = %d, %v", recovered, err)
+	}
+
+	// 3. scheduler-1 ค้างจน lease หมดอายุ reaper คืนงาน แล้ว scheduler-2 รับ AUTO001 ไป
+	recovered, err := svc.ReclaimExpiredLeases(ctx, runTime.Add(2*time.Minute))
+	if err != nil || recovered != 2 {
+		t.Fatalf("ReclaimExpiredLeases() = %d, %v, want 2", recovered, err)
+	}
+	if row := repo.rows["AUTO002"]; row.Status != "PENDING" || row.LockedBy != "" || !row.NextRunTime.Equal(runTime) {
+		t.Errorf("reclaimed row = %s by %q next %s, want PENDING with the interrupted run kept", row.Status, row.LockedBy, row.NextRunTime)
+	}
+	if err := repo.AcquireLease(ctx, []string{"AUTO001"}, "scheduler-2", runTime.Add(3*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 4. scheduler-1 กลับมาเขียนผล: lease หายไปแล้วทั้งคู่ ต้องไม่เขียนทับ
+	for _, task := range tasks {
+		task.Status = "PENDING"
+		task.NextRunTime = nextRun
+		task.RunCount++
+	}
+	lost, err := svc.BulkUpdateNextRun(ctx, tasks, "scheduler-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lost, []string{"AUTO001", "AUTO002"}) {
+		t.Errorf("BulkUpdateNextRun() lost = %v, want [AUTO001 AUTO002]", lost)
+	}
+	if row := repo.rows["AUTO001"]; row.Status != "LOCKED" || row.LockedBy != "scheduler-2" || !row.NextRunTime.Equal(runTime) || row.RunCount != 0 {
+		t.Errorf("re-leased row was overwritten: %s by %q next %s count %d", row.Status, row.LockedBy, row.NextRunTime, row.RunCount)
+	}
+
+	// 5. scheduler-2 ยังถือ lease อยู่ เขียนผลได้
+	lost, err = svc.BulkUpdateNextRun(ctx, []*model.RunAutomation{{AutomationID: "AUTO001", Status: "PENDING", NextRunTime: nextRun, RunCount: 1}}, "scheduler-2")
+	if err != nil || len(lost) != 0 {
+		t.Fatalf("BulkUpdateNextRun() = %v, %v, want released", lost, err)
+	}
+	if row := repo.rows["AUTO001"]; row.Status != "PENDING" || row.LockedBy != "" || !row.NextRunTime.Equal(nextRun) || row.RunCount != 1 {
+		t.Errorf("released row = %s by %q next %s count %d", row.Status, row.LockedBy, row.NextRunTime, row.RunCount)
+	}
+}
+
+func TestReclaimExpiredLeasesWithoutOwner(t *testing.T) {
+	// แถวที่ถูก LOCKED ก่อนมี locked_by/lock_expires_at
+	svc, repo := newLeaseTestService(&model.RunAutomation{AutomationID: "AUTO001", Status: "LOCKED", LifecycleState: StateActive})
+
+	recovered, err := svc.ReclaimExpiredLeases(context.Background(), mustTime(t, "2024-01-15T02:00:00Z"))
+	if err != nil || recovered != 1 {
+		t.Fatalf("ReclaimExpiredLeases() = %d, %v, want 1", recovered, err)
+	}
+	if row := repo.rows["AUTO001"]; row.Status != "PENDING" {
+		t.Errorf("row status = %s, want PENDING", row.Status)
+	}
+}
+
+func (r *fakeAutomationRepo) GetByID(ctx context.Context, id string) (*model.RunAutomation, error) {
+	row, ok := r.rows[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	automation := *row
+	return &automation, nil
+}
+
+func (r *fakeAutomationRepo) DeleteUnlessLocked(ctx context.Context, id string) (bool, error) {
+	row, ok := r.rows[id]
+	if !ok || row.Status == "LOCKED" {
+		return false, nil
+	}
+	delete(r.rows, id)
+	return true, nil
+}
+
+func TestDeleteAutomationWhileLocked(t *testing.T) {
+	svc, repo := newLeaseTestService(&model.RunAutomation{AutomationID: "AUTO001", Status: "LOCKED", LockedBy: "scheduler-1", LifecycleState: StateActive})
+
+	err := svc.DeleteAutomation(context.Background(), "AUTO001")
+	if !errors.Is(err, ErrAutomationLocked) {
+		t.Fatalf("DeleteAutomation() error = %v, want %v", err, ErrAutomationLocked)
+	}
+	if _, ok := repo.rows["AUTO001"]; !ok {
+		t.Error("locked automation was deleted")
+	}
+
+	if err := svc.DeleteAutomation(context.Background(), "AUTO999"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("DeleteAutomation() of a missing automation error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/utils"
+	"fmt"
+	"time"
+	_ "time/tzdata" // ไม่ต้องพึ่ง zoneinfo ของ container
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	MisfireFireOnceNow   = "fire_once_now"
+	MisfireFireAllMissed = "fire_all_missed"
+	MisfireSkipToNext    = "skip_to_next"
+
+	// MisfireGrace is how late a run may start and still count as on time (scheduler ticks every minute)
+	MisfireGrace = 59 * time.Second
+
+	// maxCatchUpRuns caps fire_all_missed even inside the catch-up window
+	maxCatchUpRuns = 100
+
+	// maxBlackoutSkips caps how many consecutive slots may be skipped by blackout days
+	maxBlackoutSkips = 1000
+
+	// DefaultPreviewCount and MaxPreviewCount bound the number of fire times returned by PreviewRuns
+	DefaultPreviewCount = 10
+	MaxPreviewCount     = 100
+)
+
+// PlannedRun is one message the scheduler should dispatch for an automation
+type PlannedRun struct {
+	ScheduledFor time.Time
+	IsCatchUp    bool
+}
+
+// PlanRuns decides which runs of a due automation to fire at runTime and returns its next NextRunTime.
+// A run older than MisfireGrace is a misfire and is handled by the automation's MisfirePolicy.
+// Misfires older than maxCatchUp are dropped (maxCatchUp <= 0 means no limit).
+// MaxRuns caps the returned runs, a zero next time means the automation has finished.
+func PlanRuns(task *model.RunAutomation, runTime time.Time, maxCatchUp time.Duration, blackouts BlackoutCalendar) ([]PlannedRun, time.Time, error) {
+	runs, err := planRuns(task, runTime, maxCatchUp, blackouts)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if task.MaxRuns > 0 {
+		remaining := int(task.MaxRuns - task.RunCount)
+		if remaining < 0 {
+			remaining = 0
+		}
+		if len(runs) > remaining {
+			runs = runs[:remaining]
+		}
+		if int(task.RunCount)+len(runs) >= int(task.MaxRuns) {
+			return runs, time.Time{}, nil
+		}
+	}
+
+	nextRun, err := NextScheduledRun(task, runTime, blackouts)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return runs, nextRun, nil
+}
+
+func planRuns(task *model.RunAutomation, runTime time.Time, maxCatchUp time.Duration, blackouts BlackoutCalendar) ([]PlannedRun, error) {
+	due := task.NextRunTime
+
+	// ตรงเวลา (อยู่ในนาทีปัจจุบัน)
+	if !due.Before(runTime.Add(-MisfireGrace)) {
+		return []PlannedRun{{ScheduledFor: due}}, nil
+	}
+
+	inWindow := func(t time.Time) bool {
+		return maxCatchUp <= 0 || !t.Before(runTime.Add(-maxCatchUp))
+	}
+
+	switch task.MisfirePolicy {
+	case MisfireSkipToNext:
+		return nil, nil
+
+	case MisfireFireAllMissed:
+		var (
+			runs []PlannedRun
+			err  error
+		)
+		for slot := due; !slot.IsZero() && !slot.After(runTime) && len(runs) < maxCatchUpRuns; {
+			if inWindow(slot) {
+				runs = append(runs, PlannedRun{
+					ScheduledFor: slot,
+					IsCatchUp:    slot.Before(runTime.Add(-MisfireGrace)),
+				})
+			}
+
+			slot, err = NextScheduledRun(task, slot, blackouts)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return runs, nil
+
+	default: // MisfireFireOnceNow
+		// รอบที่พลาดล่าสุดยังอยู่ในช่วงที่ยอมให้ตามได้หรือไม่
+		latest := due
+		for {
+			slot, err := NextScheduledRun(task, latest, blackouts)
+			if err != nil {
+				return nil, err
+			}
+			if slot.IsZero() || slot.After(runTime) {
+				break
+			}
+			latest = slot
+		}
+
+		if !inWindow(latest) {
+			return nil, nil
+		}
+		return []PlannedRun{{ScheduledFor: latest, IsCatchUp: latest.Before(runTime.Add(-MisfireGrace))}}, nil
+	}
+}
+
+// NextScheduledRun is CalculateNextRun with the end conditions (EndDate, MaxRuns) and blackout days applied.
+// A zero time means the automation has no further run.
+func NextScheduledRun(task *model.RunAutomation, after time.Time, blackouts BlackoutCalendar) (time.Time, error) {
+	if task.MaxRuns > 0 && task.RunCount >= task.MaxRuns {
+		return time.Time{}, nil
+	}
+
+	for i := 0; i < maxBlackoutSkips; i++ {
+		slot, err := CalculateNextRun(task, after)
+		if err != nil || slot.IsZero() {
+			return slot, err
+		}
+
+		run, ok, err := applyBlackout(task, slot, blackouts)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if ok {
+			return run, nil
+		}
+		after = slot
+	}
+
+	return time.Time{}, fmt.Errorf("no run outside blackout dates within %d occurrences", maxBlackoutSkips)
+}
+
+// applyBlackout resolves one schedule slot. ok=false means the slot is skipped and the following slot should be tried.
+// A zero run with ok=true means the schedule has ended.
+func applyBlackout(task *model.RunAutomation, slot time.Time, blackouts BlackoutCalendar) (time.Time, bool, error) {
+	if pastEndDate(task, slot) {
+		return time.Time{}, true, nil
+	}
+
+	loc, err := AutomationLocation(task)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	local := slot.In(loc)
+	if !blackouts.IsBlackout(task.BlackoutCalendar, local) {
+		return slot, true, nil
+	}
+	if task.BlackoutRule != BlackoutShift {
+		return time.Time{}, false, nil
+	}
+
+	shifted, found := blackouts.shift(task.BlackoutCalendar, local, loc)
+	if !found {
+		return time.Time{}, false, nil
+	}
+
+	// ถ้าเลื่อนไปแล้วชนหรือเลยรอบปกติถัดไป ให้รอบปกติทำแทน (ไม่รันซ้ำ)
+	following, err := CalculateNextRun(task, slot)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if !following.IsZero() && !shifted.Before(following) {
+		return time.Time{}, false, nil
+	}
+
+	if pastEndDate(task, shifted) {
+		return time.Time{}, true, nil
+	}
+	return shifted.UTC(), true, nil
+}
+
+func pastEndDate(task *model.RunAutomation, t time.Time) bool {
+	return !task.EndDate.IsZero() && t.After(task.EndDate)
+}
+
+// CalculateNextRun returns the next fire time of the automation strictly after now, in UTC.
+// The schedule is evaluated in the automation's TimeZone. A zero time means the automation has no further run.
+func CalculateNextRun(task *model.RunAutomation, now time.Time) (time.Time, error) {
+	loc, err := AutomationLocation(task)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var next time.Time
+	switch task.Frequency {
+	case "once":
+		return time.Time{}, nil
+	case "daily":
+		next, err = utils.CalculateDailyNextRun(now, task.StartDate, loc)
+	case "weekly":
+		next, err = utils.CalculateWeeklyNextRun(now, task.StartDate, task.DayOfWeek, loc)
+	case "monthly":
+		next, err = utils.CalculateMonthlyNextRun(now, task.StartDate, int(task.DayOfMonth), loc)
+	case "yearly":
+		next, err = utils.CalculateYearlyNextRun(now, task.StartDate, int(task.DayOfMonth), int(task.MonthOfYear), loc)
+	case "cron":
+		var schedule cron.Schedule
+		schedule, err = ParseCronExpression(task.CronExpression)
+		if err == nil {
+			next = schedule.Next(now.In(loc))
+		}
+	default:
+		return time.Time{}, fmt.Errorf("unsupported frequency: %s", task.Frequency)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return next.UTC(), nil
+}
+
+// AutomationLocation loads the IANA time zone of the automation.
+// Automations saved before time zones existed have none and keep using the server zone.
+func AutomationLocation(task *model.RunAutomation) (*time.Location, error) {
+	if task.TimeZone == "" {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(task.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time_zone %q: %w", task.TimeZone, err)
+	}
+	return loc, nil
+}
+
+// ParseCronExpression parses a standard 5-field cron expression (minute hour day-of-month month day-of-week).
+// Descriptors such as @hourly and a CRON_TZ= prefix are accepted as well.
+func ParseCronExpression(expr string) (cron.Schedule, error) {
+	if expr == "" {
+		return nil, fmt.Errorf("cron_expression is required for cron frequency")
+	}
+
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron_expression %q: %w", expr, err)
+	}
+	return schedule, nil
+}
+
+// CalculateFirstRun returns the first fire time of a newly saved (or resumed) automation.
+// Runs never start before StartDate.
+func CalculateFirstRun(task *model.RunAutomation, now time.Time, blackouts BlackoutCalendar) (time.Time, error) {
+	if task.Frequency == "once" {
+		if !task.StartDate.After(now) || (task.MaxRuns > 0 && task.RunCount >= task.MaxRuns) {
+			return time.Time{}, nil
+		}
+
+		run, _, err := applyBlackout(task, task.StartDate.UTC(), blackouts)
+		return run, err
+	}
+
+	from := now
+	if task.StartDate.After(now) {
+		// ถอยไป 1 วินาทีเพื่อให้ StartDate เองเป็นรอบแรกได้
+		from = task.StartDate.Add(-time.Second)
+	}
+
+	return NextScheduledRun(task, from, blackouts)
+}
+
+// PreviewRuns returns the next count fire times of the automation after now, in the automation's time zone.
+// A saved automation continues from its NextRunTime, otherwise the preview starts at its first run.
+// End conditions and blackout days are applied the same way the scheduler applies them.
+func PreviewRuns(task *model.RunAutomation, now time.Time, count int, blackouts BlackoutCalendar) ([]time.Time, error) {
+	loc, err := AutomationLocation(task)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.MaxRuns > 0 {
+		remaining := int(task.MaxRuns - task.RunCount)
+		if remaining < count {
+			count = remaining
+		}
+	}
+
+	next := task.NextRunTime
+	if !next.After(now) {
+		if next, err = CalculateFirstRun(task, now, blackouts); err != nil {
+			return nil, err
+		}
+	}
+
+	runs := make([]time.Time, 0, max(count, 0))
+	for !next.IsZero() && len(runs) < count {
+		runs = append(runs, next.In(loc))
+
+		if next, err = NextScheduledRun(task, next, blackouts); err != nil {
+			return nil, err
+		}
+	}
+	return runs, nil
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"automation-engine/internal/domain/model"
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("invalid test time %q: %v", value, err)
+	}
+	return parsed
+}
+
+// bangkokDaily runs every day at 09:00 Asia/Bangkok (02:00 UTC)
+func bangkokDaily(t *testing.T) *model.RunAutomation {
+	return &model.RunAutomation{
+		Frequency: "daily",
+		StartDate: mustTime(t, "2024-01-01T09:00:00+07:00"),
+		TimeZone:  "Asia/Bangkok",
+	}
+}
+
+func TestCalculateNextRun(t *testing.T) {
+	start := "2024-01-01T09:00:00+07:00"
+	tests := []struct {
+		name    string
+		task    model.RunAutomation
+		now     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "daily later today",
+			task: model.RunAutomation{Frequency: "daily", TimeZone: "Asia/Bangkok"},
+			now:  "2024-03-10T01:00:00Z",
+			want: "2024-03-10T02:00:00Z",
+		},
+		{
+			name: "daily already passed today",
+			task: model.RunAutomation{Frequency: "daily", TimeZone: "Asia/Bangkok"},
+			now:  "2024-03-10T02:00:00Z",
+			want: "2024-03-11T02:00:00Z",
+		},
+		{
+			name: "weekly on monday",
+			task: model.RunAutomation{Frequency: "weekly", DayOfWeek: "mon", TimeZone: "Asia/Bangkok"},
+			now:  "2024-03-13T00:00:00Z",
+			want: "2024-03-18T02:00:00Z",
+		},
+		{
+			name: "weekly same day after the time",
+			task: model.RunAutomation{Frequency: "weekly", DayOfWeek: "MON", TimeZone: "Asia/Bangkok"},
+			now:  "2024-03-18T03:00:00Z",
+			want: "2024-03-25T02:00:00Z",
+		},
+		{
+			name: "monthly skips months without the day",
+			task: model.RunAutomation{Frequency: "monthly", DayOfMonth: 31, TimeZone: "Asia/Bangkok"},
+			now:  "2024-04-05T00:00:00Z",
+			want: "2024-05-31T02:00:00Z",
+		},
+		{
+			name: "yearly next year",
+			task: model.RunAutomation{Frequency: "yearly", DayOfMonth: 15, MonthOfYear: 6, TimeZone: "Asia/Bangkok"},
+			now:  "2024-07-01T00:00:00Z",
+			want: "2025-06-15T02:00:00Z",
+		},
+		{
+			name: "once has no next run",
+			task: model.RunAutomation{Frequency: "once", TimeZone: "Asia/Bangkok"},
+			now:  "2024-03-10T00:00:00Z",
+			want: "",
+		},
+		{
+			name:    "invalid day of week",
+			task:    model.RunAutomation{Frequency: "weekly", DayOfWeek: "funday", TimeZone: "Asia/Bangkok"},
+			now:     "2024-03-10T00:00:00Z",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported frequency",
+			task:    model.RunAutomation{Frequency: "hourly", TimeZone: "Asia/Bangkok"},
+			now:     "2024-03-10T00:00:00Z",
+			wantErr: true,
+		},
+		{
+			name:    "invalid time zone",
+			task:    model.RunAutomation{Frequency: "daily", TimeZone: "Mars/Olympus"},
+			now:     "2024-03-10T00:00:00Z",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tt.task
+			task.StartDate = mustTime(t, start)
+
+			got, err := CalculateNextRun(&task, mustTime(t, tt.now))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("CalculateNextRun() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CalculateNextRun() error = %v", err)
+			}
+			assertTime(t, got, tt.want)
+		})
+	}
+}
+
+func TestCalculateFirstRun(t *testing.T) {
+	now := mustTime(t, "2024-03-10T05:00:00Z")
+
+	t.Run("future start date is the first run", func(t *testing.T) {
+		task := bangkokDaily(t)
+		task.StartDate = mustTime(t, "2024-04-01T09:00:00+07:00")
+		got, err := CalculateFirstRun(task, now, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertTime(t, got, "2024-04-01T02:00:00Z")
+	})
+
+	t.Run("past start date continues from now", func(t *testing.T) {
+		got, err := CalculateFirstRun(bangkokDaily(t), now, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertTime(t, got, "2024-03-11T02:00:00Z")
+	})
+
+	t.Run("once in the future", func(t *testing.T) {
+		task := &model.RunAutomation{Frequency: "once", StartDate: mustTime(t, "2024-03-12T09:00:00+07:00"), TimeZone: "Asia/Bangkok"}
+		got, err := CalculateFirstRun(task, now, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertTime(t, got, "2024-03-12T02:00:00Z")
+	})
+
+	t.Run("once in the past never runs", func(t *testing.T) {
+		task := &model.RunAutomation{Frequency: "once", StartDate: mustTime(t, "2024-03-01T09:00:00+07:00"), TimeZone: "Asia/Bangkok"}
+		got, err := CalculateFirstRun(task, now, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertTime(t, got, "")
+	})
+}
+
+func TestPlanRunsMisfirePolicy(t *testing.T) {
+	runTime := mustTime(t, "2024-03-10T05:00:00Z")
+	missedFrom := mustTime(t, "2024-03-08T02:00:00Z")
+
+	type run struct {
+		at      string
+		catchUp bool
+	}
+	tests := []struct {
+		name       string
+		policy     string
+		nextRun    time.Time
+		runTime    time.Time
+		maxCatchUp time.Duration
+		maxRuns    int32
+		runCount   int32
+		want       []run
+		wantNext   string
+	}{
+		{
+			name:     "on time",
+			policy:   MisfireSkipToNext,
+			nextRun:  mustTime(t, "2024-03-10T02:00:00Z"),
+			runTime:  mustTime(t, "2024-03-10T02:00:30Z"),
+			want:     []run{{"2024-03-10T02:00:00Z", false}},
+			wantNext: "2024-03-11T02:00:00Z",
+		},
+		{
+			name:     "fire all missed",
+			policy:   MisfireFireAllMissed,
+			nextRun:  missedFrom,
+			runTime:  runTime,
+			want:     []run{{"2024-03-08T02:00:00Z", true}, {"2024-03-09T02:00:00Z", true}, {"2024-03-10T02:00:00Z", true}},
+			wantNext: "2024-03-11T02:00:00Z",
+		},
+		{
+			name:       "fire all missed inside the catch-up window",
+			policy:     MisfireFireAllMissed,
+			nextRun:    missedFrom,
+			runTime:    runTime,
+			maxCatchUp: 36 * time.Hour,
+			want:       []run{{"2024-03-09T02:00:00Z", true}, {"2024-03-10T02:00:00Z", true}},
+			wantNext:   "2024-03-11T02:00:00Z",
+		},
+		{
+			name:     "fire all missed capped by max runs",
+			policy:   MisfireFireAllMissed,
+			nextRun:  missedFrom,
+			runTime:  runTime,
+			maxRuns:  5,
+			runCount: 3,
+			want:     []run{{"2024-03-08T02:00:00Z", true}, {"2024-03-09T02:00:00Z", true}},
+			wantNext: "",
+		},
+		{
+			name:     "fire once now runs the latest missed slot",
+			policy:   MisfireFireOnceNow,
+			nextRun:  missedFrom,
+			runTime:  runTime,
+			want:     []run{{"2024-03-10T02:00:00Z", true}},
+			wantNext: "2024-03-11T02:00:00Z",
+		},
+		{
+			name:     "empty policy is fire once now",
+			policy:   "",
+			nextRun:  missedFrom,
+			runTime:  runTime,
+			want:     []run{{"2024-03-10T02:00:00Z", true}},
+			wantNext: "2024-03-11T02:00:00Z",
+		},
+		{
+			name:       "fire once now outside the catch-up window",
+			policy:     MisfireFireOnceNow,
+			nextRun:    missedFrom,
+			runTime:    runTime,
+			maxCatchUp: time.Hour,
+			want:       nil,
+			wantNext:   "2024-03-11T02:00:00Z",
+		},
+		{
+			name:     "skip to next",
+			policy:   MisfireSkipToNext,
+			nextRun:  missedFrom,
+			runTime:  runTime,
+			want:     nil,
+			wantNext: "2024-03-11T02:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := bangkokDaily(t)
+			task.MisfirePolicy = tt.policy
+			task.NextRunTime = tt.nextRun
+			task.MaxRuns = tt.maxRuns
+			task.RunCount = tt.runCount
+
+			runs, next, err := PlanRuns(task, tt.runTime, tt.maxCatchUp, nil)
+			if err != nil {
+				t.Fatalf("PlanRuns() error = %v", err)
+			}
+			if len(runs) != len(tt.want) {
+				t.Fatalf("PlanRuns() = %d runs %v, want %d", len(runs), runs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !runs[i].ScheduledFor.Equal(mustTime(t, want.at)) || runs[i].IsCatchUp != want.catchUp {
+					t.Errorf("run %d = %s catch-up %t, want %s catch-up %t", i, runs[i].ScheduledFor.Format(time.RFC3339), runs[i].IsCatchUp, want.at, want.catchUp)
+				}
+			}
+			assertTime(t, next, tt.wantNext)
+		})
+	}
+}
+
+func TestCronSchedule(t *testing.T) {
+	for _, expr := range []string{"0 9 * * 1-5", "*/15 * * * *", "@hourly"} {
+		if _, err := ParseCronExpression(expr); err != nil {
+			t.Errorf("ParseCronExpression(%q) error = %v", expr, err)
+		}
+	}
+	for _, expr := range []string{"", "0 9 * *", "61 * * * *", "0 0 9 * * 1"} {
+		if _, err := ParseCronExpression(expr); err == nil {
+			t.Errorf("ParseCronExpression(%q) succeeded, want error", expr)
+		}
+	}
+
+	// วันทำงาน 09:00 ตามเวลาไทย: เสาร์ → จันทร์
+	task := &model.RunAutomation{Frequency: "cron", CronExpression: "0 9 * * 1-5", TimeZone: "Asia/Bangkok"}
+	got, err := CalculateNextRun(task, mustTime(t, "2024-03-09T00:00:00Z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertTime(t, got, "2024-03-11T02:00:00Z")
+}
+
+func TestValidateScheduleCron(t *testing.T) {
+	task := &model.RunAutomation{Frequency: "daily", CronExpression: "not a cron", TimeZone: "UTC"}
+	if err := validateSchedule(task); err != nil {
+		t.Fatalf("validateSchedule() error = %v", err)
+	}
+	if task.CronExpression != "" {
+		t.Errorf("cron_expression of a daily automation = %q, want cleared", task.CronExpression)
+	}
+
+	task = &model.RunAutomation{Frequency: "cron", CronExpression: "not a cron", TimeZone: "UTC"}
+	if err := validateSchedule(task); !errors.Is(err, ErrInvalidAutomation) {
+		t.Errorf("validateSchedule() error = %v, want %v", err, ErrInvalidAutomation)
+	}
+}
+
+func TestPreviewRunsAppliesEndConditions(t *testing.T) {
+	task := bangkokDaily(t)
+	task.MaxRuns, task.RunCount = 5, 2
+
+	runs, err := PreviewRuns(task, mustTime(t, "2024-03-10T03:00:00Z"), 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("PreviewRuns() = %d runs, want the 3 remaining", len(runs))
+	}
+	for i, run := range runs {
+		if run.Location().String() != "Asia/Bangkok" || run.Hour() != 9 {
+			t.Errorf("run %d = %s, want 09:00 Asia/Bangkok", i, run)
+		}
+	}
+}
+
+// assertTime compares got with an RFC3339 time, "" means the zero time (no run)
+func assertTime(t *testing.T, got time.Time, want string) {
+	t.Helper()
+	if want == "" {
+		if !got.IsZero() {
+			t.Errorf("got %s, want no run", got.Format(time.RFC3339))
+		}
+		return
+	}
+	if !got.Equal(mustTime(t, want)) {
+		t.Errorf("got %s, want %s", got.UTC().Format(time.RFC3339), want)
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"automation-engine/internal/directory"
+	"automation-engine/internal/domain/model"
+	"context"
+	"fmt"
+)
+
+// Target modes of run_automation_targets.target_mode
+const (
+	TargetInclude = "INCLUDE"
+	TargetExclude = "EXCLUDE"
+)
+
+// Execution modes of run_automation_actions.execution_mode.
+// A FAN_OUT action is called once per resolved recipient instead of once per execution.
+const (
+	ExecutionModeSingle = "SINGLE"
+	ExecutionModeFanOut = "FAN_OUT"
+
+	// MaxFanOutConcurrency caps run_automation_actions.fan_out_concurrency
+	MaxFanOutConcurrency = 50
+)
+
+type TargetService interface {
+	ResolveTargets(ctx context.Context, targets []*model.RunAutomationTarget) ([]directory.Recipient, error)
+	PreviewTargets(ctx context.Context, automationID string) ([]directory.Recipient, error)
+}
+
+type targetService struct {
+	runService RunService
+	provider   directory.Provider
+}
+
+// NewTargetService resolves targets through the given directory provider.
+// provider may be nil, then only selectors naming a single employee can be resolved.
+func NewTargetService(
+	runService RunService,
+	provider directory.Provider,
+) TargetService {
+	return &targetService{
+		runService: runService,
+		provider:   provider,
+	}
+}
+
+// ResolveTargets expands the target selectors into employees: the union of every INCLUDE selector
+// minus every EXCLUDE selector, de-duplicated by employee_id and kept in target order
+func (s *targetService) ResolveTargets(ctx context.Context, targets []*model.RunAutomationTarget) ([]directory.Recipient, error) {
+	// 1. รวมพนักงานจาก target แบบ INCLUDE (ตัดคนซ้ำ)
+	recipients := make([]directory.Recipient, 0)
+	seen := map[string]bool{}
+	for _, target := range targets {
+		if target.TargetMode == TargetExclude {
+			continue
+		}
+
+		employees, err := s.lookup(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		for _, employee := range employees {
+			if employee.EmployeeID == "" || seen[employee.EmployeeID] {
+				continue
+			}
+			seen[employee.EmployeeID] = true
+			recipients = append(recipients, employee)
+		}
+	}
+
+	// 2. ตัดพนักงานที่ตรงกับ target แบบ EXCLUDE ออก
+	excluded := map[string]bool{}
+	for _, target := range targets {
+		if target.TargetMode != TargetExclude {
+			continue
+		}
+
+		employees, err := s.lookup(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		for _, employee := range employees {
+			excluded[employee.EmployeeID] = true
+		}
+	}
+	if len(excluded) == 0 {
+		return recipients, nil
+	}
+
+	result := make([]directory.Recipient, 0, len(recipients))
+	for _, recipient := range recipients {
+		if !excluded[recipient.EmployeeID] {
+			result = append(result, recipient)
+		}
+	}
+	return result, nil
+}
+
+// PreviewTargets resolves the saved targets of an automation against the directory as it is now
+func (s *targetService) PreviewTargets(ctx context.Context, automationID string) ([]directory.Recipient, error) {
+	snapshot, err := s.runService.GetAutomationSnapshot(ctx, automationID)
+	if err != nil {
+		return nil, err
+	}
+	return s.ResolveTargets(ctx, snapshot.Targets)
+}
+
+func (s *targetService) lookup(ctx context.Context, target *model.RunAutomationTarget) ([]directory.Recipient, error) {
+	selector := directory.SelectorFromTarget(target)
+	if s.provider == nil {
+		// ไม่มี directory: ระบุตัวพนักงานตรงๆ ได้อย่างเดียว
+		if selector.IsEmployeeOnly() {
+			return []directory.Recipient{{EmployeeID: selector.EmployeeID}}, nil
+		}
+		return nil, fmt.Errorf("target %s: %w", target.AutomationTargetID, directory.ErrNoProvider)
+	}
+
+	employees, err := s.provider.FindEmployees(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("target %s: %w", target.AutomationTargetID, err)
+	}
+	return employees, nil
+}
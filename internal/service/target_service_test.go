@@ -0,0 +1,105 @@
+package service
+
+import (
+	"automation-engine/internal/directory"
+	"automation-engine/internal/domain/model"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func employeeIDs(recipients []directory.Recipient) []string {
+	ids := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		ids = append(ids, recipient.EmployeeID)
+	}
+	return ids
+}
+
+func TestResolveTargets(t *testing.T) {
+	provider := directory.NewStaticProvider([]directory.Recipient{
+		{EmployeeID: "E001", CompanyID: "C1", DepartmentID: "D1", PositionID: "MGR"},
+		{EmployeeID: "E002", CompanyID: "C1", DepartmentID: "D1"},
+		{EmployeeID: "E003", CompanyID: "C1", DepartmentID: "D2"},
+		{EmployeeID: "E004", CompanyID: "C2", DepartmentID: "D1"},
+	})
+	svc := NewTargetService(nil, provider)
+
+	tests := []struct {
+		name    string
+		targets []*model.RunAutomationTarget
+		want    []string
+	}{
+		{
+			name:    "no targets",
+			targets: nil,
+			want:    []string{},
+		},
+		{
+			name: "union of includes without duplicates",
+			targets: []*model.RunAutomationTarget{
+				{AutomationTargetID: "T1", TargetMode: TargetInclude, DepartmentID: "D2"},
+				{AutomationTargetID: "T2", TargetMode: TargetInclude, CompanyID: "C1"},
+			},
+			want: []string{"E003", "E001", "E002"},
+		},
+		{
+			name: "exclude removes matching employees",
+			targets: []*model.RunAutomationTarget{
+				{AutomationTargetID: "T1", TargetMode: TargetInclude, DepartmentID: "D1"},
+				{AutomationTargetID: "T2", TargetMode: TargetExclude, PositionID: "MGR"},
+			},
+			want: []string{"E002", "E004"},
+		},
+		{
+			name: "exclude before include still applies",
+			targets: []*model.RunAutomationTarget{
+				{AutomationTargetID: "T1", TargetMode: TargetExclude, CompanyID: "C2"},
+				{AutomationTargetID: "T2", TargetMode: TargetInclude, DepartmentID: "D1"},
+			},
+			want: []string{"E001", "E002"},
+		},
+		{
+			name: "only excludes resolve to nobody",
+			targets: []*model.RunAutomationTarget{
+				{AutomationTargetID: "T1", TargetMode: TargetExclude, CompanyID: "C1"},
+			},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.ResolveTargets(context.Background(), tt.targets)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := employeeIDs(got); !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("ResolveTargets() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTargetsWithoutProvider(t *testing.T) {
+	svc := NewTargetService(nil, nil)
+
+	got, err := svc.ResolveTargets(context.Background(), []*model.RunAutomationTarget{
+		{AutomationTargetID: "T1", TargetMode: TargetInclude, EmployeeID: "E001"},
+		{AutomationTargetID: "T2", TargetMode: TargetInclude, EmployeeID: "E002"},
+		{AutomationTargetID: "T3", TargetMode: TargetExclude, EmployeeID: "E001"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := employeeIDs(got); !reflect.DeepEqual(ids, []string{"E002"}) {
+		t.Errorf("ResolveTargets() = %v, want [E002]", ids)
+	}
+
+	_, err = svc.ResolveTargets(context.Background(), []*model.RunAutomationTarget{
+		{AutomationTargetID: "T1", TargetMode: TargetInclude, DepartmentID: "D1"},
+	})
+	if !errors.Is(err, directory.ErrNoProvider) {
+		t.Errorf("ResolveTargets() error = %v, want %v", err, directory.ErrNoProvider)
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"automation-engine/internal/dto"
+	"automation-engine/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultReplayBatchSize = 50
+	MaxReplayBatchSize     = 100
+)
+
+var (
+	ErrNotDeadLettered = errors.New("execution is not dead-lettered")
+	ErrAlreadyReplayed = errors.New("execution has already been replayed")
+)
+
+// ReplayResult is the outcome of replaying one dead-lettered execution in a batch
+type ReplayResult struct {
+	OriginalLogID string
+	Message       *dto.MessageServiceBus
+	Err           error
+}
+
+// MessageSender publishes a run message to the automation queue (implemented by azbus.Sender)
+type MessageSender interface {
+	SendMessage(ctx context.Context, sessionID string, body []byte) error
+}
+
+type TriggerService interface {
+	TriggerAutomation(ctx context.Context, automationID string, dryRun bool, triggeredBy string) (*dto.MessageServiceBus, error)
+	ReplayExecution(ctx context.Context, logID string, replayedBy string) (*dto.MessageServiceBus, error)
+	ReplayDeadLetters(ctx context.Context, filter repository.ExecutionFilter, replayedBy string) ([]ReplayResult, error)
+}
+
+type triggerService struct {
+	runService RunService
+	logService LogService
+	sender     MessageSender
+}
+
+func NewTriggerService(
+	runService RunService,
+	logService LogService,
+	sender MessageSender,
+) TriggerService {
+	return &triggerService{
+		runService: runService,
+		logService: logService,
+		sender:     sender,
+	}
+}
+
+// TriggerAutomation publishes a run of the automation right away, outside its schedule.
+// DRAFT and PAUSED automations may be triggered too, the schedule (NextRunTime, RunCount) is not touched.
+func (s *triggerService) TriggerAutomation(ctx context.Context, automationID string, dryRun bool, triggeredBy string) (*dto.MessageServiceBus, error) {
+	// 1. ตรวจว่า automation มีอยู่จริง (และใช้ channel เป็น session ของ message)
+	automation, err := s.runService.GetAutomationByID(ctx, automationID)
+	if err != nil {
+		return nil, err
+	}
+	if automation.LifecycleState == StateArchived {
+		return nil, ErrAutomationArchived
+	}
+
+	// 2. เตรียม Message (DTO) แบบเดียวกับ scheduler
+	now := time.Now()
+	msgPayload := &dto.MessageServiceBus{
+		LogID:        s.logService.GenerateLogID(),
+		AutomationID: automation.AutomationID,
+		TriggeredAt:  now,
+		ScheduledFor: now,
+		TriggerType:  dto.TriggerManual,
+		TriggeredBy:  triggeredBy,
+		DryRun:       dryRun,
+	}
+
+	body, err := json.Marshal(msgPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. ส่งเข้า Service Bus
+	if err := s.sender.SendMessage(ctx, automation.InstanceServerChannelID, body); err != nil {
+		return nil, fmt.Errorf("failed to dispatch automation %s: %w", automationID, err)
+	}
+
+	return msgPayload, nil
+}
+
+// ReplayExecution sends the original message of a dead-lettered execution again under a fresh LogID.
+// The new execution keeps replay_of_log_id, the original gets replayed_by_log_id. The link is written before the
+// message is sent, so concurrent replays of the same execution send it once; it is removed again when the send fails.
+func (s *triggerService) ReplayExecution(ctx context.Context, logID string, replayedBy string) (*dto.MessageServiceBus, error) {
+	// 1. ตรวจว่า execution เข้า dead-letter และยังไม่เคย replay
+	original, err := s.logService.GetExecution(ctx, logID)
+	if err != nil {
+		return nil, err
+	}
+	if original.DeadLetterReason == "" {
+		return nil, ErrNotDeadLettered
+	}
+	if original.ReplayedByLogID != "" {
+		return nil, fmt.Errorf("%w by %s", ErrAlreadyReplayed, original.ReplayedByLogID)
+	}
+
+	// 2. ใช้ message เดิม เปลี่ยนเฉพาะ LogID, เวลา และนับ attempt ใหม่
+	var msgPayload dto.MessageServiceBus
+	if err := json.Unmarshal([]byte(original.MessagePayload), &msgPayload); err != nil {
+		return nil, fmt.Errorf("invalid stored message of execution %s: %w", logID, err)
+	}
+	msgPayload.LogID = s.logService.GenerateLogID()
+	msgPayload.TriggeredAt = time.Now()
+	msgPayload.TriggerType = dto.TriggerReplay
+	msgPayload.TriggeredBy = replayedBy
+	msgPayload.Attempt = 0
+	msgPayload.ReplayOfLogID = original.LogID
+
+	automation, err := s.runService.GetAutomationByID(ctx, msgPayload.AutomationID)
+	if err != nil {
+		return nil, fmt.Errorf("automation %s: %w", msgPayload.AutomationID, err)
+	}
+	if automation.LifecycleState == StateArchived {
+		return nil, ErrAutomationArchived
+	}
+
+	body, err := json.Marshal(msgPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. จองการ replay โดยผูก log เดิมกับ log ใหม่ (replay ที่มาพร้อมกันจะจองไม่ได้)
+	marked, err := s.logService.MarkReplayed(ctx, original.LogID, msgPayload.LogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link execution %s: %w", logID, err)
+	}
+	if !marked {
+		return nil, ErrAlreadyReplayed
+	}
+
+	// 4. ส่งเข้า Service Bus, ส่งไม่สำเร็จให้ยกเลิกการจองเพื่อ replay ใหม่ได้
+	if err := s.sender.SendMessage(ctx, automation.InstanceServerChannelID, body); err != nil {
+		if unmarkErr := s.logService.UnmarkReplayed(ctx, original.LogID, msgPayload.LogID); unmarkErr != nil {
+			return nil, fmt.Errorf("failed to replay execution %s: %w (and failed to unlink it: %v)", logID, err, unmarkErr)
+		}
+		return nil, fmt.Errorf("failed to replay execution %s: %w", logID, err)
+	}
+
+	return &msgPayload, nil
+}
+
+// ReplayDeadLetters replays every dead-lettered execution matching the filter that has not been replayed yet.
+// One failed replay does not stop the batch, its error is returned in the result.
+func (s *triggerService) ReplayDeadLetters(ctx context.Context, filter repository.ExecutionFilter, replayedBy string) ([]ReplayResult, error) {
+	filter.DeadLettered = true
+	filter.ExcludeReplayed = true
+	filter.Cursor = ""
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultReplayBatchSize
+	}
+	if filter.Limit > MaxReplayBatchSize {
+		filter.Limit = MaxReplayBatchSize
+	}
+
+	rows, _, err := s.logService.ListExecutions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ReplayResult, 0, len(rows))
+	for _, row := range rows {
+		msg, err := s.ReplayExecution(ctx, row.LogID, replayedBy)
+		results = append(results, ReplayResult{
+			OriginalLogID: row.LogID,
+			Message:       msg,
+			Err:           err,
+		})
+	}
+	return results, nil
+}
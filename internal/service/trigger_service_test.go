@@ -0,0 +1,237 @@
+package service
+
+import (
+	"automation-engine/internal/domain/model"
+	"automation-engine/internal/dto"
+	"automation-engine/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func (r *fakeExecutionRepo) GenerateLogID() string {
+	return fmt.Sprintf("NEW%03d", len(r.logs))
+}
+
+func (r *fakeExecutionRepo) List(ctx context.Context, filter repository.ExecutionFilter) ([]*model.LogAutomationExecution, error) {
+	ids := make([]string, 0, len(r.logs))
+	for id := range r.logs {
+		ids = append(ids, id)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	result := make([]*model.LogAutomationExecution, 0)
+	for _, id := range ids {
+		log := *r.logs[id]
+		if filter.DeadLettered && log.DeadLetterReason == "" || filter.ExcludeReplayed && log.ReplayedByLogID != "" {
+			continue
+		}
+		result = append(result, &log)
+	}
+	return result, nil
+}
+
+func (r *fakeExecutionRepo) MarkReplayed(ctx context.Context, logID string, replayLogID string) (bool, error) {
+	if r.onMarkReplayed != nil {
+		onMark := r.onMarkReplayed
+		r.onMarkReplayed = nil
+		onMark()
+	}
+	log, ok := r.logs[logID]
+	if !ok || log.ReplayedByLogID != "" {
+		return false, nil
+	}
+	log.ReplayedByLogID = replayLogID
+	return true, nil
+}
+
+func (r *fakeExecutionRepo) UnmarkReplayed(ctx context.Context, logID string, replayLogID string) error {
+	if log, ok := r.logs[logID]; ok && log.ReplayedByLogID == replayLogID {
+		log.ReplayedByLogID = ""
+	}
+	return nil
+}
+
+// fakeAutomationLookup serves GetAutomationByID, the only RunService method the trigger service needs
+type fakeAutomationLookup struct {
+	RunService
+	automations map[string]*model.RunAutomation
+}
+
+func (s *fakeAutomationLookup) GetAutomationByID(ctx context.Context, automationID string) (*model.RunAutomation, error) {
+	automation, ok := s.automations[automationID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return automation, nil
+}
+
+// fakeSender records sent messages, onSend runs before each send (to race another request) and err fails it
+type fakeSender struct {
+	sent   []dto.MessageServiceBus
+	onSend func()
+	err    error
+}
+
+func (s *fakeSender) SendMessage(ctx context.Context, sessionID string, body []byte) error {
+	if s.onSend != nil {
+		onSend := s.onSend
+		s.onSend = nil
+		onSend()
+	}
+	if s.err != nil {
+		return s.err
+	}
+
+	var msg dto.MessageServiceBus
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return err
+	}
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func newTriggerTestService(t *testing.T, lifecycleState string, logs ...*model.LogAutomationExecution) (TriggerService, *fakeExecutionRepo, *fakeSender) {
+	t.Helper()
+	executions := &fakeExecutionRepo{logs: map[string]*model.LogAutomationExecution{}}
+	for _, log := range logs {
+		executions.logs[log.LogID] = log
+	}
+	runService := &fakeAutomationLookup{automations: map[string]*model.RunAutomation{
+		"AUTO001": {AutomationID: "AUTO001", InstanceServerChannelID: "CH1", LifecycleState: lifecycleState},
+	}}
+	sender := &fakeSender{}
+	logService := NewLogService(fakeTransactionManager{}, executions, nil)
+	return NewTriggerService(runService, logService, sender), executions, sender
+}
+
+func deadLettered(t *testing.T, logID string) *model.LogAutomationExecution {
+	t.Helper()
+	payload, err := json.Marshal(dto.MessageServiceBus{LogID: logID, AutomationID: "AUTO001", TriggerType: dto.TriggerSchedule, Attempt: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &model.LogAutomationExecution{LogID: logID, AutomationID: "AUTO001", Status: "FAILED", DeadLetterReason: "RetryExhausted", MessagePayload: string(payload)}
+}
+
+func TestTriggerAutomation(t *testing.T) {
+	svc, _, sender := newTriggerTestService(t, StatePaused)
+
+	msg, err := svc.TriggerAutomation(context.Background(), "AUTO001", true, "U001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].LogID != msg.LogID || sender.sent[0].TriggerType != dto.TriggerManual || !sender.sent[0].DryRun {
+		t.Errorf("sent = %+v, want one manual dry run", sender.sent)
+	}
+
+	archived, _, _ := newTriggerTestService(t, StateArchived)
+	if _, err := archived.TriggerAutomation(context.Background(), "AUTO001", false, "U001"); !errors.Is(err, ErrAutomationArchived) {
+		t.Errorf("TriggerAutomation() of an archived automation error = %v", err)
+	}
+}
+
+func TestReplayExecution(t *testing.T) {
+	svc, executions, sender := newTriggerTestService(t, StateActive, deadLettered(t, "LOG001"), &model.LogAutomationExecution{LogID: "LOG002", Status: "SUCCESS"})
+
+	msg, err := svc.ReplayExecution(context.Background(), "LOG001", "U001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.ReplayOfLogID != "LOG001" || msg.TriggerType != dto.TriggerReplay || msg.Attempt != 0 || msg.TriggeredBy != "U001" {
+		t.Errorf("replay message = %+v", msg)
+	}
+	if len(sender.sent) != 1 || executions.logs["LOG001"].ReplayedByLogID != msg.LogID {
+		t.Errorf("sent %d messages, replayed_by_log_id = %q, want %s", len(sender.sent), executions.logs["LOG001"].ReplayedByLogID, msg.LogID)
+	}
+
+	if _, err := svc.ReplayExecution(context.Background(), "LOG001", "U001"); !errors.Is(err, ErrAlreadyReplayed) {
+		t.Errorf("second replay error = %v, want %v", err, ErrAlreadyReplayed)
+	}
+	if _, err := svc.ReplayExecution(context.Background(), "LOG002", "U001"); !errors.Is(err, ErrNotDeadLettered) {
+		t.Errorf("replay of a successful execution error = %v, want %v", err, ErrNotDeadLettered)
+	}
+	if len(sender.sent) != 1 {
+		t.Errorf("sent %d messages, want 1", len(sender.sent))
+	}
+}
+
+func TestReplayExecutionConcurrent(t *testing.T) {
+	svc, executions, sender := newTriggerTestService(t, StateActive, deadLettered(t, "LOG001"))
+
+	// ทั้งสองรายการอ่าน log ตอนที่ยังไม่ถูก replay แล้วอีกรายการจองได้ก่อน
+	var racer *dto.MessageServiceBus
+	executions.onMarkReplayed = func() {
+		var err error
+		if racer, err = svc.ReplayExecution(context.Background(), "LOG001", "U002"); err != nil {
+			t.Errorf("racing replay error = %v", err)
+		}
+	}
+
+	if _, err := svc.ReplayExecution(context.Background(), "LOG001", "U001"); !errors.Is(err, ErrAlreadyReplayed) {
+		t.Fatalf("losing replay error = %v, want %v", err, ErrAlreadyReplayed)
+	}
+	if racer == nil || len(sender.sent) != 1 || sender.sent[0].LogID != racer.LogID {
+		t.Fatalf("sent = %+v, want only the winning replay", sender.sent)
+	}
+	if linked := executions.logs["LOG001"].ReplayedByLogID; linked != racer.LogID {
+		t.Errorf("replayed_by_log_id = %q, want the winning replay %s", linked, racer.LogID)
+	}
+}
+
+func TestReplayExecutionSendFailure(t *testing.T) {
+	svc, executions, sender := newTriggerTestService(t, StateActive, deadLettered(t, "LOG001"))
+
+	sender.err = errors.New("bus unavailable")
+	if _, err := svc.ReplayExecution(context.Background(), "LOG001", "U001"); err == nil {
+		t.Fatal("ReplayExecution() succeeded while the send failed")
+	}
+	if linked := executions.logs["LOG001"].ReplayedByLogID; linked != "" {
+		t.Fatalf("failed replay left replayed_by_log_id = %q", linked)
+	}
+
+	sender.err = nil
+	if _, err := svc.ReplayExecution(context.Background(), "LOG001", "U001"); err != nil {
+		t.Errorf("replay after a failed send error = %v", err)
+	}
+}
+
+func TestReplayDeadLetters(t *testing.T) {
+	replayed := deadLettered(t, "LOG003")
+	replayed.ReplayedByLogID = "LOG009"
+	svc, executions, sender := newTriggerTestService(t, StateActive, deadLettered(t, "LOG001"), deadLettered(t, "LOG002"), replayed)
+
+	// replay เดี่ยวของ LOG001 แทรกเข้ามาหลังจาก batch อ่านรายการไปแล้ว
+	executions.onMarkReplayed = func() {
+		if _, err := svc.ReplayExecution(context.Background(), "LOG001", "U002"); err != nil {
+			t.Errorf("single replay error = %v", err)
+		}
+	}
+
+	results, err := svc.ReplayDeadLetters(context.Background(), repository.ExecutionFilter{}, "U001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].OriginalLogID != "LOG002" || results[0].Err != nil {
+		t.Fatalf("results = %+v, want LOG002 replayed first", results)
+	}
+	if results[1].OriginalLogID != "LOG001" || !errors.Is(results[1].Err, ErrAlreadyReplayed) {
+		t.Errorf("LOG001 result = %+v, want %v", results[1], ErrAlreadyReplayed)
+	}
+
+	sentFor := map[string]int{}
+	for _, msg := range sender.sent {
+		sentFor[msg.ReplayOfLogID]++
+	}
+	if sentFor["LOG001"] != 1 || sentFor["LOG002"] != 1 || sentFor["LOG003"] != 0 {
+		t.Errorf("replays sent = %v, want one each for LOG001 and LOG002", sentFor)
+	}
+	if executions.logs["LOG003"].ReplayedByLogID != "LOG009" {
+		t.Error("already replayed execution was relinked")
+	}
+}
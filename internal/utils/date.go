@@ -6,28 +6,61 @@ import (
 	"time"
 )
 
+// DateIn builds a wall-clock time in loc with deterministic DST handling.
+// A time inside a skipped hour (spring forward) moves forward by the gap, e.g. 02:30 → 03:30.
+// A time inside a repeated hour (fall back) resolves to its first occurrence.
+func DateIn(year int, month time.Month, day, hour, min, sec int, loc *time.Location) time.Time {
+	// เวลาตามนาฬิกา (ยังไม่มี timezone) หลัง normalize เช่น 31 เม.ย. → 1 พ.ค.
+	wall := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+
+	// offset ก่อนและหลังช่วงเวลานั้น (ถือว่าเปลี่ยน offset ไม่เกิน 1 ครั้งใน 2 วัน)
+	_, offBefore := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, offAfter := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	candBefore := wall.Add(-time.Duration(offBefore) * time.Second)
+	candAfter := wall.Add(-time.Duration(offAfter) * time.Second)
+
+	matches := func(t time.Time) bool {
+		local := t.In(loc)
+		return local.Year() == wall.Year() && local.YearDay() == wall.YearDay() &&
+			local.Hour() == wall.Hour() && local.Minute() == wall.Minute() && local.Second() == wall.Second()
+	}
+
+	switch okBefore, okAfter := matches(candBefore), matches(candAfter); {
+	case okBefore && okAfter:
+		// ชั่วโมงซ้ำ (หรือไม่มี DST) → ใช้ครั้งแรก
+		if candAfter.Before(candBefore) {
+			return candAfter.In(loc)
+		}
+		return candBefore.In(loc)
+	case okBefore:
+		return candBefore.In(loc)
+	case okAfter:
+		return candAfter.In(loc)
+	default:
+		// ชั่วโมงที่ถูกข้าม → ใช้ offset เดิม ซึ่งจะเลื่อนไปข้างหน้าเท่ากับช่วงที่ถูกข้าม
+		return candBefore.In(loc)
+	}
+}
+
 func CalculateDailyNextRun(
 	now time.Time,
 	runTime time.Time,
 	loc *time.Location,
 ) (time.Time, error) {
 
+	// เวลาของวันตาม timezone ของ automation
+	runTime = runTime.In(loc)
 	h, m, s := runTime.Hour(), runTime.Minute(), runTime.Second()
 
-	candidate := time.Date(
-		now.Year(),
-		now.Month(),
-		now.Day(),
-		h, m, s,
-		0,
-		loc,
-	)
+	now = now.In(loc)
 
+	candidate := DateIn(now.Year(), now.Month(), now.Day(), h, m, s, loc)
 	if candidate.After(now) {
 		return candidate, nil
 	}
 
-	return candidate.AddDate(0, 0, 1), nil
+	return DateIn(now.Year(), now.Month(), now.Day()+1, h, m, s, loc), nil
 }
 
 func CalculateWeeklyNextRun(
@@ -52,32 +85,24 @@ func CalculateWeeklyNextRun(
 		return time.Time{}, fmt.Errorf("invalid day_of_week: %s", dayOfWeek)
 	}
 
+	runTime = runTime.In(loc)
 	h, m, s := runTime.Hour(), runTime.Minute(), runTime.Second()
 
 	// วันนี้ใน timezone ที่ถูกต้อง
 	now = now.In(loc)
 
-	// สร้าง candidate ของ "สัปดาห์นี้"
-	candidate := time.Date(
-		now.Year(),
-		now.Month(),
-		now.Day(),
-		h, m, s,
-		0,
-		loc,
-	)
-
 	// คำนวณจำนวนวันต้องขยับ
 	daysDiff := int(targetWeekday - now.Weekday())
 	if daysDiff < 0 {
 		daysDiff += 7
 	}
 
-	candidate = candidate.AddDate(0, 0, daysDiff)
+	// สร้าง candidate ของ "สัปดาห์นี้"
+	candidate := DateIn(now.Year(), now.Month(), now.Day()+daysDiff, h, m, s, loc)
 
 	// ถ้าวันตรง แต่เวลาเลยแล้ว → ขยับไปอาทิตย์หน้า
 	if daysDiff == 0 && !candidate.After(now) {
-		candidate = candidate.AddDate(0, 0, 7)
+		candidate = DateIn(now.Year(), now.Month(), now.Day()+7, h, m, s, loc)
 	}
 
 	return candidate, nil
@@ -94,6 +119,7 @@ func CalculateMonthlyNextRun(
 		return time.Time{}, fmt.Errorf("invalid day_of_month: %d", dayOfMonth)
 	}
 
+	runTime = runTime.In(loc)
 	h, m, s := runTime.Hour(), runTime.Minute(), runTime.Second()
 	now = now.In(loc)
 
@@ -103,14 +129,7 @@ func CalculateMonthlyNextRun(
 		daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
 
 		if dayOfMonth <= daysInMonth {
-			candidate := time.Date(
-				year,
-				month,
-				dayOfMonth,
-				h, m, s,
-				0,
-				loc,
-			)
+			candidate := DateIn(year, month, dayOfMonth, h, m, s, loc)
 
 			if candidate.After(now) {
 				return candidate, nil
@@ -142,6 +161,7 @@ func CalculateYearlyNextRun(
 		return time.Time{}, fmt.Errorf("invalid month_of_year: %d", monthOfYear)
 	}
 
+	runTime = runTime.In(loc)
 	h, m, s := runTime.Hour(), runTime.Minute(), runTime.Second()
 	now = now.In(loc)
 
@@ -149,19 +169,12 @@ func CalculateYearlyNextRun(
 
 	// พยายามสร้าง candidate ในปีปัจจุบันก่อน
 	// ใช้ monthOfYear และ dayOfMonth ที่ได้รับมาจากพารามิเตอร์
-	candidate := time.Date(
-		year,
-		time.Month(monthOfYear),
-		dayOfMonth,
-		h, m, s,
-		0,
-		loc,
-	)
+	candidate := DateIn(year, time.Month(monthOfYear), dayOfMonth, h, m, s, loc)
 
 	// ถ้า candidate ที่สร้างขึ้น "ไม่มากกว่า" เวลาปัจจุบัน (คือผ่านมาแล้วในรอบปีนี้)
 	// ให้ขยับไปปีหน้า
 	if !candidate.After(now) {
-		candidate = candidate.AddDate(1, 0, 0)
+		candidate = DateIn(year+1, time.Month(monthOfYear), dayOfMonth, h, m, s, loc)
 	}
 
 	return candidate, nil
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestDateInDST(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name string
+		got  time.Time
+		want string
+	}{
+		{
+			name: "normal day",
+			got:  DateIn(2024, time.March, 9, 9, 0, 0, newYork),
+			want: "2024-03-09T14:00:00Z",
+		},
+		{
+			name: "skipped hour moves forward",
+			got:  DateIn(2024, time.March, 10, 2, 30, 0, newYork),
+			want: "2024-03-10T07:30:00Z",
+		},
+		{
+			name: "repeated hour uses the first occurrence",
+			got:  DateIn(2024, time.November, 3, 1, 30, 0, newYork),
+			want: "2024-11-03T05:30:00Z",
+		},
+		{
+			name: "day overflow is normalised",
+			got:  DateIn(2024, time.April, 31, 9, 0, 0, newYork),
+			want: "2024-05-01T13:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, _ := time.Parse(time.RFC3339, tt.want)
+			if !tt.got.Equal(want) {
+				t.Errorf("DateIn() = %s, want %s", tt.got.UTC().Format(time.RFC3339), tt.want)
+			}
+			if tt.got.Location() != newYork {
+				t.Errorf("DateIn() location = %s, want %s", tt.got.Location(), newYork)
+			}
+		})
+	}
+}
+
+func TestCalculateDailyNextRunKeepsWallClockAcrossDST(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+	start := time.Date(2024, time.January, 1, 9, 0, 0, 0, newYork)
+
+	now := time.Date(2024, time.March, 8, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		next, err := CalculateDailyNextRun(now, start, newYork)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		local := next.In(newYork)
+		if local.Hour() != 9 || local.Minute() != 0 {
+			t.Fatalf("run %d = %s, want 09:00 local", i, local.Format(time.RFC3339))
+		}
+		now = next
+	}
+}
+
+func TestCalculateMonthlyNextRunValidation(t *testing.T) {
+	if _, err := CalculateMonthlyNextRun(time.Now(), time.Now(), 0, time.UTC); err == nil {
+		t.Error("day_of_month 0 accepted")
+	}
+	if _, err := CalculateYearlyNextRun(time.Now(), time.Now(), 1, 13, time.UTC); err == nil {
+		t.Error("month_of_year 13 accepted")
+	}
+}
@@ -0,0 +1,19 @@
+-- Per-action step log of an automation execution (one row per DefAction invocation / attempt)
+CREATE TABLE log_automation_action_executions (
+    step_id              VARCHAR(20)  NOT NULL,
+    log_id               VARCHAR(20)  NULL,
+    automation_action_id VARCHAR(20)  NULL,
+    action_id            VARCHAR(50)  NULL,
+    sort_order           INT          NOT NULL,
+    attempt              INT          NOT NULL DEFAULT 1,
+    status               VARCHAR(20)  NOT NULL DEFAULT 'FAILED',
+    request_hash         VARCHAR(64)  NULL,
+    http_status          INT          NULL,
+    response_body        TEXT         NULL,
+    latency_ms           BIGINT       NOT NULL,
+    error_message        TEXT         NULL,
+    started_at           DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at          DATETIME     NOT NULL,
+    PRIMARY KEY (step_id),
+    KEY idx_log_automation_action_executions_log_id (log_id)
+);
@@ -0,0 +1,4 @@
+-- Per-action HTTP options: extra headers (JSON object) and timeout in seconds (0 = client default)
+ALTER TABLE def_actions
+    ADD COLUMN invoke_headers TEXT NULL,
+    ADD COLUMN invoke_timeout INT  NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- Async actions: a RUNNING step must receive its callback before deadline_at, otherwise the scheduler fails it
+ALTER TABLE log_automation_action_executions
+    ADD COLUMN deadline_at DATETIME NULL,
+    ADD KEY idx_log_automation_action_executions_status_deadline (status, deadline_at);
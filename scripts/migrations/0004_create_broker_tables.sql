@@ -0,0 +1,27 @@
+-- Message broker backed by MySQL (BROKER_BACKEND=mysql), used for local development instead of Azure Service Bus
+CREATE TABLE broker_messages (
+    message_id              BIGINT        NOT NULL AUTO_INCREMENT,
+    queue_name              VARCHAR(100)  NOT NULL,
+    session_id              VARCHAR(100)  NOT NULL,
+    body                    MEDIUMBLOB    NOT NULL,
+    delivery_count          INT           NOT NULL DEFAULT 0,
+    status                  VARCHAR(20)   NOT NULL DEFAULT 'ACTIVE',
+    enqueued_at             DATETIME(6)   NOT NULL,
+    available_at            DATETIME(6)   NOT NULL,
+    locked_until            DATETIME(6)   NULL,
+    dead_letter_reason      VARCHAR(255)  NULL,
+    dead_letter_description TEXT          NULL,
+    dead_lettered_at        DATETIME(6)   NULL,
+    PRIMARY KEY (message_id),
+    KEY idx_broker_messages_queue_status_available (queue_name, status, available_at),
+    KEY idx_broker_messages_queue_session (queue_name, session_id, status)
+);
+
+-- One row per session currently owned by a receiver
+CREATE TABLE broker_session_locks (
+    queue_name   VARCHAR(100) NOT NULL,
+    session_id   VARCHAR(100) NOT NULL,
+    locked_by    VARCHAR(32)  NOT NULL,
+    locked_until DATETIME(6)  NOT NULL,
+    PRIMARY KEY (queue_name, session_id)
+);
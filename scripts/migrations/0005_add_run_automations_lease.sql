@@ -0,0 +1,5 @@
+-- Scheduler lease: a LOCKED automation belongs to locked_by until lock_expires_at, then the reaper resets it to PENDING
+ALTER TABLE run_automations
+    ADD COLUMN locked_by       VARCHAR(100) NULL,
+    ADD COLUMN lock_expires_at DATETIME     NULL,
+    ADD KEY idx_run_automations_status_lock_expires_at (status, lock_expires_at);
@@ -0,0 +1,8 @@
+-- Misfire policy: what the scheduler does with runs missed while it was down
+ALTER TABLE run_automations
+    ADD COLUMN misfire_policy VARCHAR(20) NOT NULL DEFAULT 'fire_once_now';
+
+-- Runs fired late by the misfire policy are marked as catch-up
+ALTER TABLE log_automation_executions
+    ADD COLUMN scheduled_for DATETIME   NULL,
+    ADD COLUMN is_catch_up   TINYINT(1) NOT NULL DEFAULT 0;
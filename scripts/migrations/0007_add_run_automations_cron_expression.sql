@@ -0,0 +1,3 @@
+-- Standard 5-field cron expression, used when frequency = 'cron'
+ALTER TABLE run_automations
+    ADD COLUMN cron_expression VARCHAR(100) NULL;
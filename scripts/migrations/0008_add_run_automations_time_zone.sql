@@ -0,0 +1,31 @@
+-- Services now read and write DATETIME values in UTC (DSN loc=UTC, time_zone='+00:00').
+-- The scheduling columns are converted from the TZ the services ran with before this migration.
+-- The operator must set it in the same session first, e.g.
+--   mysql --init-command="SET @legacy_tz = '+07:00'" ... < 0008_add_run_automations_time_zone.sql
+-- ('+00:00' when the services already ran in UTC). Audit columns (created, last_upd) are left as they are.
+
+-- Stops before changing anything (error 1242 "Subquery returns more than 1 row") when @legacy_tz is unset or not a valid zone
+SET @legacy_tz_checked = (
+    SELECT 1
+    UNION ALL
+    SELECT 1 FROM DUAL WHERE CONVERT_TZ('2000-01-01 00:00:00', @legacy_tz, '+00:00') IS NULL
+);
+
+-- Per-automation IANA time zone (NULL = server zone, the behaviour before this migration)
+ALTER TABLE run_automations
+    ADD COLUMN time_zone VARCHAR(64) NULL;
+
+UPDATE run_automations
+SET start_date      = CONVERT_TZ(start_date, @legacy_tz, '+00:00'),
+    next_run_time   = CONVERT_TZ(next_run_time, @legacy_tz, '+00:00'),
+    lock_expires_at = CONVERT_TZ(lock_expires_at, @legacy_tz, '+00:00');
+
+UPDATE log_automation_executions
+SET triggered_at  = CONVERT_TZ(triggered_at, @legacy_tz, '+00:00'),
+    finished_at   = CONVERT_TZ(finished_at, @legacy_tz, '+00:00'),
+    scheduled_for = CONVERT_TZ(scheduled_for, @legacy_tz, '+00:00');
+
+UPDATE log_automation_action_executions
+SET started_at  = CONVERT_TZ(started_at, @legacy_tz, '+00:00'),
+    finished_at = CONVERT_TZ(finished_at, @legacy_tz, '+00:00'),
+    deadline_at = CONVERT_TZ(deadline_at, @legacy_tz, '+00:00');
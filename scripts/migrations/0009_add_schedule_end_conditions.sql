@@ -0,0 +1,24 @@
+-- Schedule end conditions: stop after end_date or after max_runs dispatches (0 = unlimited)
+-- blackout_calendar links to def_blackout_dates.calendar_code; blackout_rule is skip or shift
+ALTER TABLE run_automations
+    ADD COLUMN end_date          DATETIME    NULL,
+    ADD COLUMN max_runs          INT         NOT NULL DEFAULT 0,
+    ADD COLUMN run_count         INT         NOT NULL DEFAULT 0,
+    ADD COLUMN blackout_calendar VARCHAR(50) NULL,
+    ADD COLUMN blackout_rule     VARCHAR(10) NOT NULL DEFAULT 'skip';
+
+-- Holiday / blackout calendar, date ranges are inclusive and compared in the automation's time zone
+CREATE TABLE def_blackout_dates (
+    blackout_id   VARCHAR(20)  NOT NULL,
+    calendar_code VARCHAR(50)  NOT NULL,
+    start_date    DATE         NOT NULL,
+    end_date      DATE         NOT NULL,
+    description   VARCHAR(255) NULL,
+    status        VARCHAR(20)  NOT NULL DEFAULT 'ACTIVE',
+    created       DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by    VARCHAR(50)  NULL,
+    last_upd      DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_upd_by   VARCHAR(50)  NULL,
+    PRIMARY KEY (blackout_id),
+    KEY idx_def_blackout_dates_calendar (calendar_code, status)
+);
@@ -0,0 +1,9 @@
+-- Manual "run now" triggers and dry runs
+ALTER TABLE log_automation_executions
+    ADD COLUMN trigger_type VARCHAR(20) NOT NULL DEFAULT 'SCHEDULE',
+    ADD COLUMN triggered_by VARCHAR(50) NULL,
+    ADD COLUMN is_dry_run   TINYINT(1)  NOT NULL DEFAULT 0;
+
+-- Request a dry run would have sent (status DRY_RUN), credential-like header values are masked
+ALTER TABLE log_automation_action_executions
+    ADD COLUMN request_preview TEXT NULL;
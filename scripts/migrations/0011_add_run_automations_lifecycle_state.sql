@@ -0,0 +1,8 @@
+-- Lifecycle state machine: DRAFT → ACTIVE ↔ PAUSED → ARCHIVED (only ACTIVE is scheduled)
+-- is_active is kept in sync (Y only when ACTIVE) for existing readers
+ALTER TABLE run_automations
+    ADD COLUMN lifecycle_state VARCHAR(20) NOT NULL DEFAULT 'ACTIVE',
+    ADD KEY idx_run_automations_lifecycle_due (lifecycle_state, status, next_run_time);
+
+UPDATE run_automations
+SET lifecycle_state = CASE WHEN is_active = 'Y' THEN 'ACTIVE' ELSE 'PAUSED' END;
@@ -0,0 +1,11 @@
+-- Per-action retry policy: attempts include the first call, backoff doubles after each failure up to the cap
+-- retry_status_codes is a comma separated list (NULL = 408, 429 and 5xx)
+ALTER TABLE def_actions
+    ADD COLUMN retry_max_attempts        INT          NOT NULL DEFAULT 1,
+    ADD COLUMN retry_backoff_seconds     INT          NOT NULL DEFAULT 0,
+    ADD COLUMN retry_max_backoff_seconds INT          NOT NULL DEFAULT 0,
+    ADD COLUMN retry_status_codes        VARCHAR(100) NULL;
+
+-- Current attempt of the execution (status RETRYING while a retry is scheduled)
+ALTER TABLE log_automation_executions
+    ADD COLUMN attempt INT NOT NULL DEFAULT 1;
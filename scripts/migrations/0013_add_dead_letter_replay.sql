@@ -0,0 +1,9 @@
+-- Dead-letter inspection and replay: the original message is kept so it can be sent again under a new log_id
+-- replay_of_log_id (new execution) and replayed_by_log_id (original execution) link the two logs
+ALTER TABLE log_automation_executions
+    ADD COLUMN message_payload    TEXT         NULL,
+    ADD COLUMN dead_letter_reason VARCHAR(100) NULL,
+    ADD COLUMN dead_lettered_at   DATETIME     NULL,
+    ADD COLUMN replay_of_log_id   VARCHAR(20)  NULL,
+    ADD COLUMN replayed_by_log_id VARCHAR(20)  NULL,
+    ADD KEY idx_log_automation_executions_dead_letter (dead_letter_reason, dead_lettered_at);
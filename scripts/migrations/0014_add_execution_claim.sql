@@ -0,0 +1,4 @@
+-- Idempotent execution: the worker claims log_id (status RUNNING) until claimed_until before invoking actions,
+-- a duplicate delivery of the same message skips or takes over the execution instead of calling the actions again
+ALTER TABLE log_automation_executions
+    ADD COLUMN claimed_until DATETIME NULL;
@@ -0,0 +1,3 @@
+-- Target resolution: INCLUDE selectors add the matching employees, EXCLUDE selectors remove them again
+ALTER TABLE run_automation_targets
+    ADD COLUMN target_mode VARCHAR(10) NOT NULL DEFAULT 'INCLUDE';
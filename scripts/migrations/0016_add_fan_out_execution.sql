@@ -0,0 +1,15 @@
+-- Fan-out actions are called once per resolved recipient (fan_out_concurrency 0 = worker FAN_OUT_POOL)
+ALTER TABLE run_automation_actions
+    ADD COLUMN execution_mode      VARCHAR(10) NOT NULL DEFAULT 'SINGLE',
+    ADD COLUMN fan_out_concurrency INT         NOT NULL DEFAULT 0;
+
+-- One step per recipient of a fan-out action (status SKIPPED when the recipient does not match the conditions)
+ALTER TABLE log_automation_action_executions
+    ADD COLUMN employee_id VARCHAR(50) NULL;
+
+-- Per-recipient roll-up on the execution (status PARTIAL when only some recipients failed)
+ALTER TABLE log_automation_executions
+    ADD COLUMN target_total     INT NOT NULL DEFAULT 0,
+    ADD COLUMN target_succeeded INT NOT NULL DEFAULT 0,
+    ADD COLUMN target_failed    INT NOT NULL DEFAULT 0,
+    ADD COLUMN target_skipped   INT NOT NULL DEFAULT 0;
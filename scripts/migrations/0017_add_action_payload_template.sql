@@ -0,0 +1,3 @@
+-- Go text/template rendering the JSON body of the action (run_automation_actions.config_json "payload_template" overrides it)
+ALTER TABLE def_actions
+    ADD COLUMN payload_template TEXT NULL;
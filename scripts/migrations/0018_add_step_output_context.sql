@@ -0,0 +1,4 @@
+-- Output of a successful step (JSON object response or async callback) and the outputs of the earlier steps it could read
+ALTER TABLE log_automation_action_executions
+    ADD COLUMN output_json  TEXT NULL,
+    ADD COLUMN context_json TEXT NULL;
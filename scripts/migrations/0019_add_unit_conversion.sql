@@ -0,0 +1,16 @@
+-- Numbered after 0018 on purpose: it belongs to the unit conversion change but was added during review, when
+-- 0002-0018 could already have been applied. It only touches def_units, no earlier migration depends on it.
+-- Conversion of condition values between units: value_in_base = value * factor, units convert only within the same dimension.
+-- A unit without factor can be used as is but not converted.
+ALTER TABLE def_units
+    ADD COLUMN dimension VARCHAR(20)    NULL,
+    ADD COLUMN factor    DECIMAL(20, 6) NULL;
+
+-- Time units (base = second), singular and plural codes
+UPDATE def_units SET dimension = 'time', factor = 1        WHERE UPPER(unit_code) IN ('SECOND', 'SECONDS');
+UPDATE def_units SET dimension = 'time', factor = 60       WHERE UPPER(unit_code) IN ('MINUTE', 'MINUTES');
+UPDATE def_units SET dimension = 'time', factor = 3600     WHERE UPPER(unit_code) IN ('HOUR', 'HOURS');
+UPDATE def_units SET dimension = 'time', factor = 86400    WHERE UPPER(unit_code) IN ('DAY', 'DAYS');
+UPDATE def_units SET dimension = 'time', factor = 604800   WHERE UPPER(unit_code) IN ('WEEK', 'WEEKS');
+UPDATE def_units SET dimension = 'time', factor = 2592000  WHERE UPPER(unit_code) IN ('MONTH', 'MONTHS');
+UPDATE def_units SET dimension = 'time', factor = 31536000 WHERE UPPER(unit_code) IN ('YEAR', 'YEARS');